@@ -0,0 +1,142 @@
+// Provides clocks so anything that depends on time
+// can run against real time or simulated time
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// A source of time
+type Clock interface {
+	// Returns the current time
+	Now() time.Time
+
+	// Returns the time passed since t
+	Since(t time.Time) time.Duration
+
+	// Blocks for the duration
+	Sleep(d time.Duration)
+
+	// Creates a timer which sends the
+	// time on its channel after the duration
+	NewTimer(d time.Duration) Timer
+}
+
+// A single event in the future
+type Timer interface {
+	// Receives the time when the timer fires
+	C() <-chan time.Time
+
+	// Stops the timer from firing. Returns false
+	// if the timer already fired or was stopped
+	Stop() bool
+}
+
+// Creates a clock which uses the system time
+func NewReal() Clock {
+	return realClock{}
+}
+
+// Uses the system time
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+func (realClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{timer: time.NewTimer(d)}
+}
+
+type realTimer struct {
+	timer *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t realTimer) Stop() bool {
+	return t.timer.Stop()
+}
+
+// Creates a manual clock starting at the given time
+func NewManual(start time.Time) *Manual {
+	return &Manual{now: start}
+}
+
+// A simulated clock which only moves when told to.
+// Waiting on it never blocks and instead moves the clock
+// forward by the duration, so anything run against it
+// runs as fast as possible. Safe to use from multiple goroutines
+type Manual struct {
+	lock sync.Mutex
+	now  time.Time
+}
+
+// Returns the current simulated time
+func (m *Manual) Now() time.Time {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.now
+}
+
+// Returns the simulated time passed since t
+func (m *Manual) Since(t time.Time) time.Duration {
+	return m.Now().Sub(t)
+}
+
+// Moves the clock forward by the duration
+// without blocking
+func (m *Manual) Sleep(d time.Duration) {
+	m.Advance(d)
+}
+
+// Moves the clock forward by the duration and
+// returns a timer which has already fired
+func (m *Manual) NewTimer(d time.Duration) Timer {
+	c := make(chan time.Time, 1)
+	c <- m.Advance(d)
+	return manualTimer{c: c}
+}
+
+// Moves the clock forward by the duration and returns
+// the new time. Negative durations are ignored
+func (m *Manual) Advance(d time.Duration) time.Time {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if d > 0 {
+		m.now = m.now.Add(d)
+	}
+	return m.now
+}
+
+// Sets the current time
+func (m *Manual) Set(t time.Time) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.now = t
+}
+
+type manualTimer struct {
+	c chan time.Time
+}
+
+func (t manualTimer) C() <-chan time.Time {
+	return t.c
+}
+
+// Manual timers fire as soon as they
+// are created so can't be stopped
+func (t manualTimer) Stop() bool {
+	return false
+}
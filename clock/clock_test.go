@@ -0,0 +1,47 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManual(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewManual(start)
+	if !c.Now().Equal(start) {
+		t.Error("expected the start time", c.Now())
+	}
+
+	c.Sleep(10 * time.Minute)
+	if c.Since(start) != 10*time.Minute {
+		t.Error("sleep should advance the clock", c.Since(start))
+	}
+
+	timer := c.NewTimer(time.Second)
+	select {
+	case fired := <-timer.C():
+		if fired.Sub(start) != 10*time.Minute+time.Second {
+			t.Error("unexpected fire time", fired)
+		}
+	default:
+		t.Error("manual timers should fire straight away")
+	}
+
+	c.Advance(-time.Hour)
+	if c.Since(start) != 10*time.Minute+time.Second {
+		t.Error("negative durations should be ignored")
+	}
+}
+
+func TestReal(t *testing.T) {
+	c := NewReal()
+	start := c.Now()
+	timer := c.NewTimer(time.Millisecond)
+	<-timer.C()
+	if c.Since(start) < time.Millisecond {
+		t.Error("timer fired early")
+	}
+	if timer.Stop() {
+		t.Error("fired timer should not be stoppable")
+	}
+}
@@ -0,0 +1,165 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// The json format of a behaviour node
+type behaviourNodeData struct {
+	// The kind of node such as "sequence" or "action"
+	Type string `json:"type"`
+	// The registered action or condition of a leaf
+	Name     string              `json:"name"`
+	Children []behaviourNodeData `json:"children"`
+	Child    *behaviourNodeData  `json:"child"`
+	// "all" or "one" for parallel nodes
+	Policy string `json:"policy"`
+	// Used by repeat nodes
+	Count *int `json:"count"`
+	// Milliseconds used by cooldown and timeout nodes
+	Duration float64 `json:"duration"`
+}
+
+// The scene file data of a behaviour tree component
+type behaviourTreeComponentData struct {
+	Tree       *behaviourNodeData `json:"tree"`
+	Blackboard map[string]any     `json:"blackboard"`
+}
+
+// Registers an action so it can be used in
+// behaviour trees loaded from json
+func (w *GameWorld) RegisterBehaviourAction(name string, action func(ctx *BehaviourContext) NodeStatus) {
+	w.behaviourActions[name] = action
+}
+
+// Registers a condition so it can be used in
+// behaviour trees loaded from json
+func (w *GameWorld) RegisterBehaviourCondition(name string, condition func(ctx *BehaviourContext) bool) {
+	w.behaviourConditions[name] = condition
+}
+
+// Loads a behaviour tree from json. Leaves refer to
+// registered actions and conditions by name. For example
+//
+//	{
+//	  "type": "selector",
+//	  "children": [
+//	    {
+//	      "type": "sequence",
+//	      "children": [
+//	        {"type": "condition", "name": "canSeePlayer"},
+//	        {"type": "cooldown", "duration": 1000, "child": {"type": "action", "name": "attack"}}
+//	      ]
+//	    },
+//	    {"type": "action", "name": "wander"}
+//	  ]
+//	}
+func (w *GameWorld) LoadBehaviourTree(r io.Reader) (BehaviourNode, error) {
+	data := behaviourNodeData{}
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, fmt.Errorf("load behaviour tree: %w", err)
+	}
+	node, err := w.buildBehaviourNode(data)
+	if err != nil {
+		return nil, fmt.Errorf("load behaviour tree: %w", err)
+	}
+	return node, nil
+}
+
+// Builds the node and its children
+func (w *GameWorld) buildBehaviourNode(data behaviourNodeData) (BehaviourNode, error) {
+	children := []BehaviourNode{}
+	for _, childData := range data.Children {
+		child, err := w.buildBehaviourNode(childData)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+	// Decorators need exactly one child
+	decorated := func() (BehaviourNode, error) {
+		if data.Child == nil {
+			return nil, fmt.Errorf("%s node has no child", data.Type)
+		}
+		return w.buildBehaviourNode(*data.Child)
+	}
+
+	switch data.Type {
+	case "sequence":
+		return NewSequenceNode(children...), nil
+	case "selector":
+		return NewSelectorNode(children...), nil
+	case "parallel":
+		switch data.Policy {
+		case "", "all":
+			return NewParallelNode(RequireAll, children...), nil
+		case "one":
+			return NewParallelNode(RequireOne, children...), nil
+		}
+		return nil, fmt.Errorf("unknown parallel policy %q", data.Policy)
+	case "inverter":
+		child, err := decorated()
+		if err != nil {
+			return nil, err
+		}
+		return NewInverterNode(child), nil
+	case "repeat":
+		child, err := decorated()
+		if err != nil {
+			return nil, err
+		}
+		count := -1
+		if data.Count != nil {
+			count = *data.Count
+		}
+		return NewRepeatNode(count, child), nil
+	case "cooldown":
+		child, err := decorated()
+		if err != nil {
+			return nil, err
+		}
+		return NewCooldownNode(data.Duration, child), nil
+	case "timeout":
+		child, err := decorated()
+		if err != nil {
+			return nil, err
+		}
+		return NewTimeoutNode(data.Duration, child), nil
+	case "action":
+		action, exists := w.behaviourActions[data.Name]
+		if !exists {
+			return nil, fmt.Errorf("unknown action %q", data.Name)
+		}
+		return NewActionNode(action), nil
+	case "condition":
+		condition, exists := w.behaviourConditions[data.Name]
+		if !exists {
+			return nil, fmt.Errorf("unknown condition %q", data.Name)
+		}
+		return NewConditionNode(condition), nil
+	}
+	return nil, fmt.Errorf("unknown node type %q", data.Type)
+}
+
+// Creates behaviour tree components in scene files
+// using the actions and conditions of the world
+func (w *GameWorld) behaviourTreeComponentFactory(data json.RawMessage) (Component, error) {
+	bData := behaviourTreeComponentData{}
+	if err := unmarshalComponentData(data, &bData); err != nil {
+		return nil, err
+	}
+	if bData.Tree == nil {
+		return nil, fmt.Errorf("behaviour tree has no tree")
+	}
+	root, err := w.buildBehaviourNode(*bData.Tree)
+	if err != nil {
+		return nil, err
+	}
+	c := NewBehaviourTreeComponent(root)
+	for key, value := range bData.Blackboard {
+		c.Blackboard.Set(key, value)
+	}
+	return c, nil
+}
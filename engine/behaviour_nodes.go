@@ -0,0 +1,394 @@
+package engine
+
+// Clones all the nodes
+func cloneNodes(nodes []BehaviourNode) []BehaviourNode {
+	clones := make([]BehaviourNode, len(nodes))
+	for i, n := range nodes {
+		clones[i] = n.Clone()
+	}
+	return clones
+}
+
+// Ticks its children in order until one fails.
+// Succeeds if every child succeeds
+type SequenceNode struct {
+	Children []BehaviourNode
+	// The child that is running
+	current int
+}
+
+// Creates a sequence node ticking the children in order
+func NewSequenceNode(children ...BehaviourNode) *SequenceNode {
+	return &SequenceNode{Children: children}
+}
+
+// Ticks the running child and the ones after it
+func (n *SequenceNode) Tick(ctx *BehaviourContext) NodeStatus {
+	for n.current < len(n.Children) {
+		status := n.Children[n.current].Tick(ctx)
+		switch status {
+		case NodeRunning:
+			return NodeRunning
+		case NodeFailure:
+			n.current = 0
+			return NodeFailure
+		}
+		n.current++
+	}
+	n.current = 0
+	return NodeSuccess
+}
+
+// Aborts the running child and starts again from the first
+func (n *SequenceNode) Reset() {
+	if n.current < len(n.Children) {
+		n.Children[n.current].Reset()
+	}
+	n.current = 0
+}
+
+// Returns a sequence node with clones of the children
+func (n *SequenceNode) Clone() BehaviourNode {
+	return NewSequenceNode(cloneNodes(n.Children)...)
+}
+
+// Ticks its children in order until one succeeds.
+// Fails if every child fails
+type SelectorNode struct {
+	Children []BehaviourNode
+	// The child that is running
+	current int
+}
+
+// Creates a selector node trying the children in order
+func NewSelectorNode(children ...BehaviourNode) *SelectorNode {
+	return &SelectorNode{Children: children}
+}
+
+// Ticks the running child and the ones after it
+func (n *SelectorNode) Tick(ctx *BehaviourContext) NodeStatus {
+	for n.current < len(n.Children) {
+		status := n.Children[n.current].Tick(ctx)
+		switch status {
+		case NodeRunning:
+			return NodeRunning
+		case NodeSuccess:
+			n.current = 0
+			return NodeSuccess
+		}
+		n.current++
+	}
+	n.current = 0
+	return NodeFailure
+}
+
+// Aborts the running child and starts again from the first
+func (n *SelectorNode) Reset() {
+	if n.current < len(n.Children) {
+		n.Children[n.current].Reset()
+	}
+	n.current = 0
+}
+
+// Returns a selector node with clones of the children
+func (n *SelectorNode) Clone() BehaviourNode {
+	return NewSelectorNode(cloneNodes(n.Children)...)
+}
+
+// When a parallel node finishes
+type ParallelPolicy int
+
+const (
+	// Succeeds once every child succeeds
+	// and fails as soon as one fails
+	RequireAll ParallelPolicy = iota
+	// Succeeds as soon as one child succeeds
+	// and fails once every child fails
+	RequireOne
+)
+
+// Ticks all its children every tick
+type ParallelNode struct {
+	Children []BehaviourNode
+	Policy   ParallelPolicy
+	// The status of each child that finished
+	finished map[int]NodeStatus
+}
+
+// Creates a parallel node finishing by the policy
+func NewParallelNode(policy ParallelPolicy, children ...BehaviourNode) *ParallelNode {
+	return &ParallelNode{
+		Children: children,
+		Policy:   policy,
+		finished: map[int]NodeStatus{},
+	}
+}
+
+// Ticks every child that hasn't finished yet
+func (n *ParallelNode) Tick(ctx *BehaviourContext) NodeStatus {
+	// Nodes made without NewParallelNode have no map yet
+	if n.finished == nil {
+		n.finished = map[int]NodeStatus{}
+	}
+	successes, failures := 0, 0
+	for i, child := range n.Children {
+		status, done := n.finished[i]
+		if !done {
+			status = child.Tick(ctx)
+			if status != NodeRunning {
+				n.finished[i] = status
+			}
+		}
+		switch status {
+		case NodeSuccess:
+			successes++
+		case NodeFailure:
+			failures++
+		}
+	}
+
+	result := NodeRunning
+	switch n.Policy {
+	case RequireAll:
+		if failures > 0 {
+			result = NodeFailure
+		} else if successes == len(n.Children) {
+			result = NodeSuccess
+		}
+	case RequireOne:
+		if successes > 0 {
+			result = NodeSuccess
+		} else if failures == len(n.Children) {
+			result = NodeFailure
+		}
+	}
+	if result != NodeRunning {
+		n.Reset()
+	}
+	return result
+}
+
+// Aborts the children still running
+func (n *ParallelNode) Reset() {
+	for i, child := range n.Children {
+		if _, done := n.finished[i]; !done {
+			child.Reset()
+		}
+	}
+	n.finished = map[int]NodeStatus{}
+}
+
+// Returns a parallel node with clones of the children
+func (n *ParallelNode) Clone() BehaviourNode {
+	return NewParallelNode(n.Policy, cloneNodes(n.Children)...)
+}
+
+// Swaps the success and failure of its child
+type InverterNode struct {
+	Child BehaviourNode
+}
+
+// Creates an inverter node of the child
+func NewInverterNode(child BehaviourNode) *InverterNode {
+	return &InverterNode{Child: child}
+}
+
+// Ticks the child and swaps its result
+func (n *InverterNode) Tick(ctx *BehaviourContext) NodeStatus {
+	switch n.Child.Tick(ctx) {
+	case NodeSuccess:
+		return NodeFailure
+	case NodeFailure:
+		return NodeSuccess
+	}
+	return NodeRunning
+}
+
+// Aborts the child
+func (n *InverterNode) Reset() {
+	n.Child.Reset()
+}
+
+// Returns an inverter node with a clone of the child
+func (n *InverterNode) Clone() BehaviourNode {
+	return NewInverterNode(n.Child.Clone())
+}
+
+// Runs its child again each time it succeeds, at most
+// once a tick. Fails as soon as the child fails
+type RepeatNode struct {
+	Child BehaviourNode
+	// The number of times to succeed. -1 repeats
+	// forever and 0 succeeds without ticking the child
+	Count int
+	// The number of times the child succeeded
+	successes int
+}
+
+// Creates a repeat node succeeding after the child
+// succeeds count times. -1 repeats forever
+func NewRepeatNode(count int, child BehaviourNode) *RepeatNode {
+	return &RepeatNode{Child: child, Count: count}
+}
+
+// Ticks the child and counts its successes
+func (n *RepeatNode) Tick(ctx *BehaviourContext) NodeStatus {
+	if n.Count == 0 {
+		return NodeSuccess
+	}
+	switch n.Child.Tick(ctx) {
+	case NodeRunning:
+		return NodeRunning
+	case NodeFailure:
+		n.successes = 0
+		return NodeFailure
+	}
+	n.successes++
+	if n.Count != -1 && n.successes >= n.Count {
+		n.successes = 0
+		return NodeSuccess
+	}
+	return NodeRunning
+}
+
+// Aborts the child and clears the successes
+func (n *RepeatNode) Reset() {
+	n.Child.Reset()
+	n.successes = 0
+}
+
+// Returns a repeat node with a clone of the child
+func (n *RepeatNode) Clone() BehaviourNode {
+	return NewRepeatNode(n.Count, n.Child.Clone())
+}
+
+// Fails without ticking its child until the duration
+// in milliseconds has passed since the child last finished
+type CooldownNode struct {
+	Child    BehaviourNode
+	Duration float64
+	// The tree time the child can run again
+	readyTime float64
+}
+
+// Creates a cooldown node of the child
+func NewCooldownNode(duration float64, child BehaviourNode) *CooldownNode {
+	return &CooldownNode{Child: child, Duration: duration}
+}
+
+// Ticks the child unless it is cooling down
+func (n *CooldownNode) Tick(ctx *BehaviourContext) NodeStatus {
+	if ctx.Time < n.readyTime {
+		return NodeFailure
+	}
+	status := n.Child.Tick(ctx)
+	if status != NodeRunning {
+		n.readyTime = ctx.Time + n.Duration
+	}
+	return status
+}
+
+// Aborts the child. The cooldown keeps going
+func (n *CooldownNode) Reset() {
+	n.Child.Reset()
+}
+
+// Returns a cooldown node with a clone of the child.
+// The clone is ready straight away
+func (n *CooldownNode) Clone() BehaviourNode {
+	return NewCooldownNode(n.Duration, n.Child.Clone())
+}
+
+// Aborts its child and fails if it is still running
+// after the duration in milliseconds
+type TimeoutNode struct {
+	Child    BehaviourNode
+	Duration float64
+	// Whether the child is running
+	started bool
+	// The tree time the child started
+	startTime float64
+}
+
+// Creates a timeout node of the child
+func NewTimeoutNode(duration float64, child BehaviourNode) *TimeoutNode {
+	return &TimeoutNode{Child: child, Duration: duration}
+}
+
+// Ticks the child and fails once it runs too long
+func (n *TimeoutNode) Tick(ctx *BehaviourContext) NodeStatus {
+	if !n.started {
+		n.started = true
+		n.startTime = ctx.Time
+	}
+	status := n.Child.Tick(ctx)
+	if status != NodeRunning {
+		n.started = false
+		return status
+	}
+	if ctx.Time-n.startTime >= n.Duration {
+		n.Reset()
+		return NodeFailure
+	}
+	return NodeRunning
+}
+
+// Aborts the child and restarts the timeout
+func (n *TimeoutNode) Reset() {
+	n.Child.Reset()
+	n.started = false
+}
+
+// Returns a timeout node with a clone of the child
+func (n *TimeoutNode) Clone() BehaviourNode {
+	return NewTimeoutNode(n.Duration, n.Child.Clone())
+}
+
+// Runs a go function
+type ActionNode struct {
+	Action func(ctx *BehaviourContext) NodeStatus
+}
+
+// Creates an action node running the function
+func NewActionNode(action func(ctx *BehaviourContext) NodeStatus) *ActionNode {
+	return &ActionNode{Action: action}
+}
+
+// Returns the result of the action
+func (n *ActionNode) Tick(ctx *BehaviourContext) NodeStatus {
+	return n.Action(ctx)
+}
+
+// Does nothing as the action keeps no state
+func (n *ActionNode) Reset() {}
+
+// Returns an action node with the same function
+func (n *ActionNode) Clone() BehaviourNode {
+	return NewActionNode(n.Action)
+}
+
+// Succeeds if the go function returns true
+type ConditionNode struct {
+	Condition func(ctx *BehaviourContext) bool
+}
+
+// Creates a condition node checking the function
+func NewConditionNode(condition func(ctx *BehaviourContext) bool) *ConditionNode {
+	return &ConditionNode{Condition: condition}
+}
+
+// Succeeds if the condition is true
+func (n *ConditionNode) Tick(ctx *BehaviourContext) NodeStatus {
+	if n.Condition(ctx) {
+		return NodeSuccess
+	}
+	return NodeFailure
+}
+
+// Does nothing as the condition keeps no state
+func (n *ConditionNode) Reset() {}
+
+// Returns a condition node with the same function
+func (n *ConditionNode) Clone() BehaviourNode {
+	return NewConditionNode(n.Condition)
+}
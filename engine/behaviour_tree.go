@@ -0,0 +1,163 @@
+package engine
+
+// The result of ticking a behaviour node
+type NodeStatus int
+
+const (
+	// The node finished and succeeded
+	NodeSuccess NodeStatus = iota
+	// The node finished and failed
+	NodeFailure
+	// The node needs more ticks to finish
+	NodeRunning
+)
+
+func (s NodeStatus) String() string {
+	switch s {
+	case NodeSuccess:
+		return "success"
+	case NodeFailure:
+		return "failure"
+	case NodeRunning:
+		return "running"
+	}
+	return "unknown"
+}
+
+// Passed to every node when the tree is ticked
+type BehaviourContext struct {
+	// The object the tree belongs to
+	Object *GameObject
+
+	// Shared memory of the tree
+	Blackboard *Blackboard
+
+	// The time since the last tick in milliseconds
+	Delta float64
+
+	// The total milliseconds the tree has been ticked for
+	Time float64
+}
+
+// A node of a behaviour tree. Nodes keep their own
+// running state so each tree needs its own nodes
+type BehaviourNode interface {
+	// Runs the node for a tick
+	Tick(ctx *BehaviourContext) NodeStatus
+
+	// Clears any running state. Called
+	// when a running node is aborted
+	Reset()
+
+	// Makes a copy of the node and its children
+	// with no running state
+	Clone() BehaviourNode
+}
+
+// Shared memory for the nodes of a behaviour tree
+type Blackboard struct {
+	values map[string]any
+}
+
+// Creates an empty blackboard
+func NewBlackboard() *Blackboard {
+	return &Blackboard{values: map[string]any{}}
+}
+
+// Sets the value of the key
+func (b *Blackboard) Set(key string, value any) {
+	b.values[key] = value
+}
+
+// Gets the value of the key
+func (b *Blackboard) Get(key string) (any, bool) {
+	value, exists := b.values[key]
+	return value, exists
+}
+
+// Returns whether the key has a value
+func (b *Blackboard) Has(key string) bool {
+	_, exists := b.values[key]
+	return exists
+}
+
+// Removes the value of the key
+func (b *Blackboard) Delete(key string) {
+	delete(b.values, key)
+}
+
+// Gets the value of the key as type T. Returns
+// false if the key has no value or a different type
+func GetBlackboardValue[T any](b *Blackboard, key string) (T, bool) {
+	value, ok := b.values[key].(T)
+	return value, ok
+}
+
+// Creates a behaviour tree component with the given root
+func NewBehaviourTreeComponent(root BehaviourNode) *BehaviourTreeComponent {
+	return &BehaviourTreeComponent{
+		Root:       root,
+		Blackboard: NewBlackboard(),
+		status:     NodeRunning,
+	}
+}
+
+// Ticks a behaviour tree every step. When the tree
+// finishes, it starts again from the root on the next step
+type BehaviourTreeComponent struct {
+	BaseComponent
+
+	// The root node of the tree
+	Root BehaviourNode
+
+	// Memory shared by the nodes of the tree
+	Blackboard *Blackboard
+
+	// The total time ticked
+	time float64
+
+	// The status of the last tick
+	status NodeStatus
+}
+
+// Overrides
+func (b *BehaviourTreeComponent) Step(delta float64) {
+	if b.Root == nil {
+		return
+	}
+	b.time += delta
+	b.status = b.Root.Tick(&BehaviourContext{
+		Object:     b.GetGameObject(),
+		Blackboard: b.Blackboard,
+		Delta:      delta,
+		Time:       b.time,
+	})
+}
+
+// Returns the status of the last tick
+func (b *BehaviourTreeComponent) GetStatus() NodeStatus {
+	return b.status
+}
+
+// Aborts the running nodes so the tree
+// starts from the root on the next step
+func (b *BehaviourTreeComponent) Reset() {
+	if b.Root != nil {
+		b.Root.Reset()
+	}
+	b.status = NodeRunning
+}
+
+// Makes a copy of the tree and the blackboard values.
+// The copy starts from the root
+func (b *BehaviourTreeComponent) Clone() Component {
+	var root BehaviourNode
+	if b.Root != nil {
+		root = b.Root.Clone()
+	}
+	clone := NewBehaviourTreeComponent(root)
+	for key, value := range b.Blackboard.values {
+		clone.Blackboard.Set(key, value)
+	}
+	return clone
+}
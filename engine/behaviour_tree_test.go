@@ -0,0 +1,192 @@
+package engine
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// An action which runs for the given ticks and then succeeds
+func tickingAction(ticks int, log *[]string, name string) *ActionNode {
+	count := 0
+	return NewActionNode(func(ctx *BehaviourContext) NodeStatus {
+		*log = append(*log, name)
+		count++
+		if count < ticks {
+			return NodeRunning
+		}
+		count = 0
+		return NodeSuccess
+	})
+}
+
+func TestSequenceAndSelector(t *testing.T) {
+	log := []string{}
+	canSee := false
+	tree := NewSelectorNode(
+		NewSequenceNode(
+			NewConditionNode(func(ctx *BehaviourContext) bool { return canSee }),
+			tickingAction(2, &log, "attack"),
+		),
+		tickingAction(1, &log, "wander"),
+	)
+	bt := NewBehaviourTreeComponent(tree)
+	bt.Step(10)
+	if bt.GetStatus() != NodeSuccess || !reflect.DeepEqual(log, []string{"wander"}) {
+		t.Error("expected to wander", bt.GetStatus(), log)
+	}
+
+	canSee = true
+	bt.Step(10)
+	if bt.GetStatus() != NodeRunning {
+		t.Error("attack should still be running", bt.GetStatus())
+	}
+	bt.Step(10)
+	if bt.GetStatus() != NodeSuccess || !reflect.DeepEqual(log, []string{"wander", "attack", "attack"}) {
+		t.Error("expected to attack", bt.GetStatus(), log)
+	}
+}
+
+func TestParallelNode(t *testing.T) {
+	log := []string{}
+	all := NewParallelNode(RequireAll, tickingAction(1, &log, "a"), tickingAction(3, &log, "b"))
+	ctx := &BehaviourContext{}
+	if all.Tick(ctx) != NodeRunning || all.Tick(ctx) != NodeRunning || all.Tick(ctx) != NodeSuccess {
+		t.Error("expected to succeed once all children succeed")
+	}
+	if !reflect.DeepEqual(log, []string{"a", "b", "b", "b"}) {
+		t.Error("finished children should not be ticked again", log)
+	}
+
+	one := NewParallelNode(RequireOne, tickingAction(1, &log, "a"), tickingAction(3, &log, "b"))
+	if one.Tick(ctx) != NodeSuccess {
+		t.Error("expected to succeed once one child succeeds")
+	}
+
+	literal := &ParallelNode{
+		Policy:   RequireAll,
+		Children: []BehaviourNode{tickingAction(1, &log, "c"), tickingAction(2, &log, "d")},
+	}
+	if literal.Tick(ctx) != NodeRunning || literal.Tick(ctx) != NodeSuccess {
+		t.Error("expected a parallel node made without the constructor to work")
+	}
+}
+
+func TestDecorators(t *testing.T) {
+	log := []string{}
+	ctx := &BehaviourContext{}
+	fail := NewConditionNode(func(ctx *BehaviourContext) bool { return false })
+
+	if NewInverterNode(fail).Tick(ctx) != NodeSuccess {
+		t.Error("inverter should succeed when the child fails")
+	}
+
+	repeat := NewRepeatNode(3, tickingAction(1, &log, "r"))
+	if repeat.Tick(ctx) != NodeRunning || repeat.Tick(ctx) != NodeRunning || repeat.Tick(ctx) != NodeSuccess {
+		t.Error("repeat should succeed after 3 successes")
+	}
+	ticked := false
+	never := NewRepeatNode(0, NewActionNode(func(ctx *BehaviourContext) NodeStatus {
+		ticked = true
+		return NodeSuccess
+	}))
+	if never.Tick(ctx) != NodeSuccess || ticked {
+		t.Error("repeat of 0 should succeed without ticking the child")
+	}
+
+	cooldown := NewCooldownNode(100, tickingAction(1, &log, "c"))
+	if cooldown.Tick(ctx) != NodeSuccess {
+		t.Error("cooldown should run the child first")
+	}
+	ctx.Time = 50
+	if cooldown.Tick(ctx) != NodeFailure {
+		t.Error("cooldown should fail while cooling down")
+	}
+	ctx.Time = 100
+	if cooldown.Tick(ctx) != NodeSuccess {
+		t.Error("cooldown should run the child once ready")
+	}
+
+	aborted := false
+	forever := &abortableNode{onReset: func() { aborted = true }}
+	timeout := NewTimeoutNode(100, forever)
+	ctx.Time = 0
+	timeout.Tick(ctx)
+	ctx.Time = 50
+	if timeout.Tick(ctx) != NodeRunning {
+		t.Error("timeout should be running")
+	}
+	ctx.Time = 100
+	if timeout.Tick(ctx) != NodeFailure || !aborted {
+		t.Error("timeout should abort the child and fail")
+	}
+}
+
+// Runs forever and records when aborted
+type abortableNode struct {
+	onReset func()
+}
+
+func (n *abortableNode) Tick(ctx *BehaviourContext) NodeStatus { return NodeRunning }
+func (n *abortableNode) Reset()                                { n.onReset() }
+func (n *abortableNode) Clone() BehaviourNode                  { return &abortableNode{onReset: n.onReset} }
+
+func TestLoadBehaviourTree(t *testing.T) {
+	w := NewGameWorld()
+	attacks := 0
+	w.RegisterBehaviourCondition("hasTarget", func(ctx *BehaviourContext) bool {
+		return ctx.Blackboard.Has("target")
+	})
+	w.RegisterBehaviourAction("attack", func(ctx *BehaviourContext) NodeStatus {
+		attacks++
+		return NodeSuccess
+	})
+
+	err := w.LoadPrefabs(strings.NewReader(`{
+		"prefabs": {
+			"enemy": {
+				"components": [{
+					"name": "ai",
+					"type": "behaviourTree",
+					"data": {
+						"blackboard": {"target": "player"},
+						"tree": {
+							"type": "sequence",
+							"children": [
+								{"type": "condition", "name": "hasTarget"},
+								{"type": "cooldown", "duration": 100, "child": {"type": "action", "name": "attack"}}
+							]
+						}
+					}
+				}]
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enemy, err := w.Instantiate("enemy", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Scene.AddChild(enemy)
+	for i := 0; i < 5; i++ {
+		w.Step(50)
+	}
+	// Attacks at 50, 150 and 250
+	if attacks != 3 {
+		t.Error("expected 3 attacks", attacks)
+	}
+
+	ai := MustGetComponent[*BehaviourTreeComponent](enemy)
+	target, _ := GetBlackboardValue[string](ai.Blackboard, "target")
+	if target != "player" {
+		t.Error("expected the blackboard from the file", target)
+	}
+
+	_, err = w.LoadBehaviourTree(strings.NewReader(`{"type": "action", "name": "missing"}`))
+	if err == nil {
+		t.Error("expected an unknown action error")
+	}
+}
@@ -0,0 +1,125 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+
+	"github.com/ashleycheung/go-game/utils"
+)
+
+// The number of functions that can be sent on the
+// commands channel before sending blocks
+const commandBufferSize = 256
+
+// Returned by Do when the world isn't running
+var ErrWorldNotRunning = errors.New("world is not running")
+
+// Returned by Do when the world stops
+// before the function is called
+var ErrWorldStopped = errors.New("world stopped before the function was called")
+
+// A function from Do waiting for the next tick
+type doCommand struct {
+	fn func()
+	// Receives the result once
+	// called or cancelled
+	done chan error
+}
+
+// Calls the function and sends the result. A panic is
+// sent to the caller then raised again
+func (cmd *doCommand) call() {
+	defer func() {
+		if r := recover(); r != nil {
+			cmd.done <- &utils.PanicError{Value: r, Stack: debug.Stack()}
+			panic(r)
+		}
+	}()
+	cmd.fn()
+	cmd.done <- nil
+}
+
+// Queues a function to be called at the start
+// of the next tick. This is needed to prevent
+// concurrent write errors. Safe to call from any goroutine
+func (w *GameWorld) QueueFunction(f func()) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.funcQueue = append(w.funcQueue, f)
+}
+
+// Returns a channel that functions can be sent on to be
+// called at the start of the next tick. Sending blocks
+// once the buffer is full until the world catches up,
+// so it can be used in a select with other channels
+func (w *GameWorld) Commands() chan<- func() {
+	return w.commands
+}
+
+// Calls the function on the world goroutine at the start
+// of the next tick and waits for it to finish. Returns
+// ErrWorldNotRunning if the world isn't running and
+// ErrWorldStopped if it stops first. If the function
+// panics, a *utils.PanicError is returned and the panic
+// is raised again on the world goroutine. This must not be
+// called from the world goroutine as it would wait forever
+func (w *GameWorld) Do(fn func()) error {
+	return w.DoContext(context.Background(), fn)
+}
+
+// Same as Do but stops waiting when the context is done.
+// If the context finishes first, the function may still be
+// called on the next tick
+func (w *GameWorld) DoContext(ctx context.Context, fn func()) error {
+	cmd := &doCommand{
+		fn:   fn,
+		done: make(chan error, 1),
+	}
+	w.lock.Lock()
+	if !w.running {
+		w.lock.Unlock()
+		return ErrWorldNotRunning
+	}
+	w.doQueue = append(w.doQueue, cmd)
+	w.lock.Unlock()
+
+	select {
+	case err := <-cmd.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Process the functions in the queue
+func (w *GameWorld) processFunctions() {
+	w.lock.Lock()
+	currQueue := w.funcQueue
+	w.funcQueue = []func(){}
+	currDoQueue := w.doQueue
+	w.doQueue = []*doCommand{}
+	w.lock.Unlock()
+
+	// If a function panics the commands not called
+	// yet are failed so their callers don't wait forever
+	defer func() {
+		for _, cmd := range currDoQueue {
+			cmd.done <- ErrWorldStopped
+		}
+	}()
+	for _, f := range currQueue {
+		f()
+	}
+	for len(currDoQueue) != 0 {
+		cmd := currDoQueue[0]
+		currDoQueue = currDoQueue[1:]
+		cmd.call()
+	}
+
+	// Only take the functions already sent so
+	// a busy sender can't stall the tick
+	for i := len(w.commands); i > 0; i-- {
+		(<-w.commands)()
+	}
+}
@@ -0,0 +1,157 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ashleycheung/go-game/utils"
+)
+
+// Runs the world on another goroutine and
+// waits for it to start
+func runWorld(t *testing.T, w *GameWorld) chan bool {
+	stopped := make(chan bool)
+	go func() {
+		w.Run(200)
+		close(stopped)
+	}()
+	for !w.IsRunning() {
+		time.Sleep(time.Millisecond)
+	}
+	return stopped
+}
+
+func TestDoNotRunning(t *testing.T) {
+	w := NewGameWorld()
+	if err := w.Do(func() {}); !errors.Is(err, ErrWorldNotRunning) {
+		t.Error("expected ErrWorldNotRunning, got", err)
+	}
+}
+
+func TestDoFromGoroutine(t *testing.T) {
+	w := NewGameWorld()
+	stopped := runWorld(t, w)
+
+	obj := NewGameObject()
+	obj.AddToGroup("player")
+	err := w.Do(func() {
+		w.Scene.AddChild(obj)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var players []*GameObject
+	w.Do(func() {
+		players = w.GetGroupObjects("player")
+	})
+	if len(players) != 1 || players[0] != obj {
+		t.Error("expected the player to be added", players)
+	}
+
+	// Commands sent on the channel are called on a later tick
+	called := make(chan bool)
+	w.Commands() <- func() { close(called) }
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		t.Error("command was never called")
+	}
+
+	go w.Stop()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("world did not stop")
+	}
+	if w.IsRunning() {
+		t.Error("world should not be running")
+	}
+	if err := w.Do(func() {}); !errors.Is(err, ErrWorldNotRunning) {
+		t.Error("expected ErrWorldNotRunning, got", err)
+	}
+}
+
+func TestDoStopped(t *testing.T) {
+	w := NewGameWorld()
+	stopped := runWorld(t, w)
+
+	// Stopping from inside a tick means the
+	// next queued function is never called
+	block := make(chan bool)
+	w.QueueFunction(func() {
+		w.Stop()
+		close(block)
+	})
+	<-block
+	err := w.Do(func() { t.Error("should not be called") })
+	if err != nil && !errors.Is(err, ErrWorldStopped) && !errors.Is(err, ErrWorldNotRunning) {
+		t.Error("unexpected error", err)
+	}
+	<-stopped
+}
+
+func TestDoPanic(t *testing.T) {
+	w := NewGameWorld()
+	commands := []*doCommand{}
+	for i := 0; i < 3; i++ {
+		cmd := &doCommand{fn: func() {}, done: make(chan error, 1)}
+		commands = append(commands, cmd)
+	}
+	commands[1].fn = func() { panic("do failed") }
+	w.doQueue = commands
+
+	func() {
+		defer func() {
+			if recover() != "do failed" {
+				t.Error("expected the panic raised on the world goroutine")
+			}
+		}()
+		w.processFunctions()
+	}()
+	if err := <-commands[0].done; err != nil {
+		t.Error("expected the first command called", err)
+	}
+	var panicErr *utils.PanicError
+	if err := <-commands[1].done; !errors.As(err, &panicErr) || panicErr.Value != "do failed" {
+		t.Error("expected the panic returned to the caller", err)
+	}
+	if err := <-commands[2].done; !errors.Is(err, ErrWorldStopped) {
+		t.Error("expected the remaining command failed", err)
+	}
+}
+
+func TestQueuedFunctionPanic(t *testing.T) {
+	w := NewGameWorld()
+	cmd := &doCommand{fn: func() {}, done: make(chan error, 1)}
+	w.doQueue = []*doCommand{cmd}
+	w.QueueFunction(func() { panic("failed") })
+	func() {
+		defer func() { recover() }()
+		w.processFunctions()
+	}()
+	select {
+	case err := <-cmd.done:
+		if !errors.Is(err, ErrWorldStopped) {
+			t.Error("expected the command failed", err)
+		}
+	default:
+		t.Error("expected the waiting command to be told")
+	}
+}
+
+func TestDoContext(t *testing.T) {
+	w := NewGameWorld()
+	stopped := runWorld(t, w)
+	defer func() {
+		w.Stop()
+		<-stopped
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := w.DoContext(ctx, func() {}); err != nil && !errors.Is(err, context.Canceled) {
+		t.Error("expected context.Canceled, got", err)
+	}
+}
@@ -5,7 +5,23 @@ import "github.com/ashleycheung/go-game/event"
 // Used to add extra functionality
 // to a game object
 type Component interface {
+	// Called every step before any
+	// game object is stepped
+	PreStep(delta float64)
+
+	// Called every step
 	Step(delta float64)
+
+	// Called every step after all game
+	// objects have stepped and before the
+	// physics world steps. Movement should
+	// be applied here
+	PhysicsStep(delta float64)
+
+	// Called every step after the
+	// physics world steps
+	PostStep(delta float64)
+
 	// Gets the game object
 	// this component is added to
 	GetGameObject() *GameObject
@@ -43,6 +59,20 @@ type Component interface {
 	// Sets the game object
 	// internally used by game object
 	setGameObject(obj *GameObject)
+
+	// Clears the base component state.
+	// Used internally when copying components
+	resetBase()
+}
+
+// Components that hold state which should not be shared
+// between copies can implement this to control how they
+// are copied when a prefab is instantiated
+type CloneableComponent interface {
+	Component
+	// Returns a copy of the component that is
+	// not attached to any game object
+	Clone() Component
 }
 
 // The base component that all
@@ -69,15 +99,17 @@ func (b *BaseComponent) baseOnGameObjectAttach() {
 		b.callInWorldFuncsQueue()
 	} else {
 		// Otherwise wait for gameobject to join scene
-		b.onSceneEnterRemoveListener = b.obj.Event.AddOneTimeListener(
-			OnSceneEnterEvent,
-			func(e event.Event[GameObjectEvent]) error {
+		b.onSceneEnterRemoveListener = event.OnOnce(
+			b.obj.Event,
+			OnSceneEnterTopic,
+			func(struct{}) error {
 				b.callInWorldFuncsQueue()
 				b.onSceneEnterRemoveListener = nil
 				// Add one time listener for scene exit event
-				b.onSceneExitRemoveListener = b.obj.Event.AddOneTimeListener(
-					OnSceneExitEvent,
-					func(e event.Event[GameObjectEvent]) error {
+				b.onSceneExitRemoveListener = event.OnOnce(
+					b.obj.Event,
+					OnSceneExitTopic,
+					func(struct{}) error {
 						b.OnSceneExit()
 						return nil
 					},
@@ -140,8 +172,15 @@ func (b *BaseComponent) setGameObject(obj *GameObject) {
 	b.obj = obj
 }
 
-func (b *BaseComponent) Step(delta float64)  {}
-func (b *BaseComponent) OnSceneEnter()       {}
-func (b *BaseComponent) OnSceneExit()        {}
-func (b *BaseComponent) OnGameObjectAttach() {}
-func (b *BaseComponent) OnGameObjectDetach() {}
+func (b *BaseComponent) resetBase() {
+	*b = BaseComponent{}
+}
+
+func (b *BaseComponent) PreStep(delta float64)     {}
+func (b *BaseComponent) Step(delta float64)        {}
+func (b *BaseComponent) PhysicsStep(delta float64) {}
+func (b *BaseComponent) PostStep(delta float64)    {}
+func (b *BaseComponent) OnSceneEnter()             {}
+func (b *BaseComponent) OnSceneExit()              {}
+func (b *BaseComponent) OnGameObjectAttach()       {}
+func (b *BaseComponent) OnGameObjectDetach()       {}
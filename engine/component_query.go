@@ -0,0 +1,109 @@
+package engine
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Gets the first component of type T on the object
+// in step order. T can be a concrete component type
+// such as *PhysicsComponent or an interface
+func GetComponentOf[T any](g *GameObject) (T, bool) {
+	for _, entry := range g.componentOrder {
+		if c, ok := entry.component.(T); ok {
+			return c, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Gets all the components of type T on
+// the object in step order
+func GetComponentsOf[T any](g *GameObject) []T {
+	components := []T{}
+	for _, entry := range g.componentOrder {
+		if c, ok := entry.component.(T); ok {
+			components = append(components, c)
+		}
+	}
+	return components
+}
+
+// Gets the first component of type T on the object.
+// Panics if the object has no component of the type
+func MustGetComponent[T any](g *GameObject) T {
+	c, ok := GetComponentOf[T](g)
+	if !ok {
+		var zero T
+		panic(fmt.Sprintf("game object %d has no component of type %T", g.Id, zero))
+	}
+	return c
+}
+
+// Gets the first component of type T on the object
+// or its descendants, searching breadth first
+func FindComponentInChildren[T any](g *GameObject) (T, bool) {
+	objIter := newBFSIterator(g)
+	for objIter.HasNext() {
+		if c, ok := GetComponentOf[T](objIter.Next()); ok {
+			return c, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Gets all the components of type T in the scene, in no
+// particular order. Components are indexed by type when
+// they enter the scene so this is O(n) where n is the number
+// of components of the type. Querying an interface type checks
+// every indexed component type that implements it
+func QueryComponents[T any](w *GameWorld) []T {
+	components := []T{}
+	queryType := reflect.TypeOf((*T)(nil)).Elem()
+
+	if queryType.Kind() != reflect.Interface {
+		for c := range w.componentIndex[queryType] {
+			components = append(components, c.(T))
+		}
+		return components
+	}
+
+	for cType, cSet := range w.componentIndex {
+		if !cType.Implements(queryType) {
+			continue
+		}
+		for c := range cSet {
+			components = append(components, c.(T))
+		}
+	}
+	return components
+}
+
+// Internal use
+// adds the component to the world type index
+func (w *GameWorld) indexComponent(c Component) {
+	cType := reflect.TypeOf(c)
+	cSet, exists := w.componentIndex[cType]
+	if exists {
+		cSet[c] = true
+	} else {
+		w.componentIndex[cType] = map[Component]bool{
+			c: true,
+		}
+	}
+}
+
+// Internal use
+// removes the component from the world type index
+func (w *GameWorld) unindexComponent(c Component) {
+	cType := reflect.TypeOf(c)
+	cSet, exists := w.componentIndex[cType]
+	if exists {
+		delete(cSet, c)
+		if len(cSet) == 0 {
+			delete(w.componentIndex, cType)
+		}
+	}
+}
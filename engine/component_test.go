@@ -173,3 +173,75 @@ func TestOnAttachCall2(t *testing.T) {
 		t.Error("on scene exit has not been called")
 	}
 }
+
+// Records the order the components are called in
+type OrderComponent struct {
+	BaseComponent
+	Name  string
+	Calls *[]string
+}
+
+func (c *OrderComponent) PreStep(delta float64) {
+	*c.Calls = append(*c.Calls, "pre:"+c.Name)
+}
+
+func (c *OrderComponent) Step(delta float64) {
+	*c.Calls = append(*c.Calls, "step:"+c.Name)
+}
+
+func (c *OrderComponent) PhysicsStep(delta float64) {
+	*c.Calls = append(*c.Calls, "physics:"+c.Name)
+}
+
+func (c *OrderComponent) PostStep(delta float64) {
+	*c.Calls = append(*c.Calls, "post:"+c.Name)
+}
+
+func TestComponentOrder(t *testing.T) {
+	calls := []string{}
+	obj := NewGameObject()
+	obj.AddComponent("movement", &OrderComponent{Name: "movement", Calls: &calls})
+	obj.AddComponentWithPriority("input", &OrderComponent{Name: "input", Calls: &calls}, -1)
+	obj.AddComponent("collision", &OrderComponent{Name: "collision", Calls: &calls})
+
+	w := NewGameWorld()
+	w.Scene.AddChild(obj)
+	w.Step(10)
+
+	expected := []string{
+		"pre:input", "pre:movement", "pre:collision",
+		"step:input", "step:movement", "step:collision",
+		"physics:input", "physics:movement", "physics:collision",
+		"post:input", "post:movement", "post:collision",
+	}
+	if len(calls) != len(expected) {
+		t.Fatal("wrong number of calls", calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Fatal("wrong order", calls)
+		}
+	}
+
+	names := obj.GetComponentNames()
+	if names[0] != "input" || names[1] != "movement" || names[2] != "collision" {
+		t.Error("wrong component names order", names)
+	}
+}
+
+// Components removed by another component
+// during a step are not stepped
+func TestRemoveComponentDuringStep(t *testing.T) {
+	obj := NewGameObject()
+	removed := &StepCounterComponent{}
+	obj.AddComponent("remover", &StepCounterComponent{
+		OnStep: func() {
+			obj.RemoveComponent("removed")
+		},
+	})
+	obj.AddComponent("removed", removed)
+	obj.Step(10)
+	if removed.Steps != 0 {
+		t.Error("removed component should not be stepped")
+	}
+}
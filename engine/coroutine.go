@@ -0,0 +1,215 @@
+package engine
+
+import (
+	"runtime/debug"
+
+	"github.com/ashleycheung/go-game/event"
+	"github.com/ashleycheung/go-game/utils"
+)
+
+// Raised inside a coroutine to unwind it when cancelled
+type coroutineCancelled struct{}
+
+// A scripted sequence which can wait across steps.
+// The function runs on its own goroutine, but only ever
+// while the world goroutine is waiting for it, so it can
+// safely change the world like any other step code
+type Coroutine struct {
+	world *GameWorld
+
+	// Tells the coroutine to continue.
+	// False means it was cancelled
+	resume chan bool
+
+	// Tells the world the coroutine
+	// waited or finished
+	yield chan struct{}
+
+	// The scheduler time to continue at
+	wakeTime float64
+
+	// Whether the coroutine function is
+	// currently running
+	active bool
+
+	paused bool
+
+	cancelled bool
+
+	done bool
+
+	// A panic from inside the coroutine
+	panicValue *utils.PanicError
+
+	// Removes the scene exit listener
+	// of the object the coroutine is bound to
+	unbind func()
+}
+
+// Starts a coroutine. The function runs straight away until
+// its first Wait or Yield and then continues on later steps.
+// Must be called from the world goroutine. A coroutine that
+// never finishes keeps its goroutine until it is cancelled
+func (w *GameWorld) StartCoroutine(fn func(co *Coroutine)) *Coroutine {
+	co := &Coroutine{
+		world:  w,
+		resume: make(chan bool),
+		yield:  make(chan struct{}),
+	}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				if _, isCancel := r.(coroutineCancelled); !isCancel {
+					co.panicValue = &utils.PanicError{Value: r, Stack: debug.Stack()}
+				}
+			}
+			co.done = true
+			co.active = false
+			co.yield <- struct{}{}
+		}()
+		if !<-co.resume {
+			panic(coroutineCancelled{})
+		}
+		fn(co)
+	}()
+	w.coroutines = append(w.coroutines, co)
+	co.step(true)
+	return co
+}
+
+// Continues the coroutine until it next waits or finishes.
+// A panic inside the coroutine is raised again here
+// as a *utils.PanicError
+func (co *Coroutine) step(resume bool) {
+	co.active = resume
+	co.resume <- resume
+	<-co.yield
+	if co.panicValue != nil {
+		panicValue := co.panicValue
+		co.panicValue = nil
+		panic(panicValue)
+	}
+}
+
+// Hands control back to the world until it is resumed
+func (co *Coroutine) suspend() {
+	if !co.active {
+		panic("coroutine: Wait and Yield must be called from inside the coroutine")
+	}
+	if co.cancelled {
+		panic(coroutineCancelled{})
+	}
+	co.active = false
+	co.yield <- struct{}{}
+	if !<-co.resume {
+		panic(coroutineCancelled{})
+	}
+}
+
+// Waits for the given milliseconds of world time
+func (co *Coroutine) Wait(ms float64) {
+	co.wakeTime = co.world.schedulerTime + ms
+	co.suspend()
+}
+
+// Waits until the next step
+func (co *Coroutine) Yield() {
+	co.wakeTime = co.world.schedulerTime
+	co.suspend()
+}
+
+// Waits until the condition is true,
+// checking once every step
+func (co *Coroutine) WaitUntil(cond func() bool) {
+	for !cond() {
+		co.Yield()
+	}
+}
+
+// Stops the coroutine. Inside the coroutine, it stops
+// at its next Wait or Yield. Must be called from the
+// world goroutine or a coroutine
+func (co *Coroutine) Cancel() {
+	if co.done || co.cancelled {
+		return
+	}
+	co.cancelled = true
+	if co.unbind != nil {
+		co.unbind()
+		co.unbind = nil
+	}
+	if !co.active {
+		co.step(false)
+	}
+}
+
+// Cancels the coroutine when the object leaves the scene.
+// If the object isn't in this world, the coroutine is
+// cancelled straight away
+func (co *Coroutine) BindTo(obj *GameObject) *Coroutine {
+	if co.done || co.cancelled {
+		return co
+	}
+	if obj.World != co.world {
+		co.Cancel()
+		return co
+	}
+	if co.unbind != nil {
+		co.unbind()
+	}
+	co.unbind = event.OnOnce(
+		obj.Event,
+		OnSceneExitTopic,
+		func(struct{}) error {
+			co.unbind = nil
+			co.Cancel()
+			return nil
+		},
+	)
+	return co
+}
+
+// Stops the coroutine continuing until resumed.
+// Time spent paused still counts towards a Wait
+func (co *Coroutine) Pause() {
+	co.paused = true
+}
+
+// Continues a paused coroutine
+func (co *Coroutine) Resume() {
+	co.paused = false
+}
+
+// Returns whether the coroutine is paused
+func (co *Coroutine) IsPaused() bool {
+	return co.paused
+}
+
+// Returns whether the coroutine has finished
+// or was cancelled
+func (co *Coroutine) IsDone() bool {
+	return co.done
+}
+
+// Continues the coroutines which are due. Only the given
+// coroutines are run so ones started during the step wait
+// for the next step
+func (w *GameWorld) runCoroutines(current []*Coroutine) {
+	for _, co := range current {
+		if co.done || co.paused || co.wakeTime > w.schedulerTime {
+			continue
+		}
+		co.step(true)
+	}
+
+	remaining := []*Coroutine{}
+	for _, co := range w.coroutines {
+		if !co.done {
+			remaining = append(remaining, co)
+		} else if co.unbind != nil {
+			co.unbind()
+			co.unbind = nil
+		}
+	}
+	w.coroutines = remaining
+}
@@ -0,0 +1,110 @@
+package engine
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ashleycheung/go-game/utils"
+)
+
+func TestCoroutine(t *testing.T) {
+	w := NewGameWorld()
+	steps := []string{}
+	co := w.StartCoroutine(func(co *Coroutine) {
+		steps = append(steps, "start")
+		co.Wait(25)
+		steps = append(steps, "waited")
+		co.Yield()
+		steps = append(steps, "yielded")
+	})
+	if !reflect.DeepEqual(steps, []string{"start"}) {
+		t.Error("coroutine should run until its first wait", steps)
+	}
+
+	w.Step(10)
+	w.Step(10)
+	if len(steps) != 1 {
+		t.Error("coroutine continued early", steps)
+	}
+	w.Step(10)
+	w.Step(10)
+	if !reflect.DeepEqual(steps, []string{"start", "waited", "yielded"}) || !co.IsDone() {
+		t.Error("coroutine should have finished", steps)
+	}
+}
+
+// A coroutine started by a scheduled task
+// continues on the next step
+func TestCoroutineFromTask(t *testing.T) {
+	w := NewGameWorld()
+	steps := []string{}
+	w.After(10, func() {
+		w.StartCoroutine(func(co *Coroutine) {
+			steps = append(steps, "start")
+			co.Yield()
+			steps = append(steps, "yielded")
+		})
+	})
+	w.Step(10)
+	if !reflect.DeepEqual(steps, []string{"start"}) {
+		t.Error("coroutine should wait for the next step", steps)
+	}
+	w.Step(10)
+	if !reflect.DeepEqual(steps, []string{"start", "yielded"}) {
+		t.Error("coroutine should continue on the next step", steps)
+	}
+}
+
+func TestCoroutineCancel(t *testing.T) {
+	w := NewGameWorld()
+	obj := NewGameObject()
+	w.Scene.AddChild(obj)
+
+	waves := 0
+	finished := false
+	co := w.StartCoroutine(func(co *Coroutine) {
+		defer func() { finished = true }()
+		for {
+			waves++
+			co.Wait(100)
+		}
+	}).BindTo(obj)
+
+	w.Step(100)
+	w.Step(100)
+	if waves != 3 {
+		t.Error("expected 3 waves", waves)
+	}
+
+	// Leaving the scene stops it
+	w.Scene.RemoveChild(obj)
+	if !co.IsDone() || !finished {
+		t.Error("coroutine should be cancelled")
+	}
+	w.Step(100)
+	if waves != 3 {
+		t.Error("cancelled coroutine should not continue", waves)
+	}
+}
+
+func TestCoroutinePanic(t *testing.T) {
+	w := NewGameWorld()
+	defer func() {
+		panicErr, ok := recover().(*utils.PanicError)
+		if !ok {
+			t.Fatal("expected the coroutine panic on the world goroutine")
+		}
+		if panicErr.Value != "boom" {
+			t.Error("expected the original panic value", panicErr.Value)
+		}
+		if !strings.Contains(string(panicErr.Stack), "TestCoroutinePanic") {
+			t.Error("expected the stack of the coroutine")
+		}
+	}()
+	w.StartCoroutine(func(co *Coroutine) {
+		co.Yield()
+		panic("boom")
+	})
+	w.Step(10)
+}
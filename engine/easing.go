@@ -0,0 +1,92 @@
+package engine
+
+import "math"
+
+// Maps the progress of a tween from 0 to 1
+// to the progress of the value
+type EaseFunc func(t float64) float64
+
+// Moves at a constant speed
+func EaseLinear(t float64) float64 {
+	return t
+}
+
+// Starts slow and speeds up
+func EaseInQuad(t float64) float64 {
+	return t * t
+}
+
+// Starts fast and slows down
+func EaseOutQuad(t float64) float64 {
+	return 1 - (1-t)*(1-t)
+}
+
+// Starts and ends slow
+func EaseInOutQuad(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return 1 - math.Pow(-2*t+2, 2)/2
+}
+
+func EaseInCubic(t float64) float64 {
+	return t * t * t
+}
+
+func EaseOutCubic(t float64) float64 {
+	return 1 - math.Pow(1-t, 3)
+}
+
+func EaseInOutCubic(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	return 1 - math.Pow(-2*t+2, 3)/2
+}
+
+func EaseInSine(t float64) float64 {
+	return 1 - math.Cos(t*math.Pi/2)
+}
+
+func EaseOutSine(t float64) float64 {
+	return math.Sin(t * math.Pi / 2)
+}
+
+func EaseInOutSine(t float64) float64 {
+	return -(math.Cos(math.Pi*t) - 1) / 2
+}
+
+// Goes slightly past the end and comes back
+func EaseOutBack(t float64) float64 {
+	const c1 = 1.70158
+	const c3 = c1 + 1
+	return 1 + c3*math.Pow(t-1, 3) + c1*math.Pow(t-1, 2)
+}
+
+// Springs around the end before settling
+func EaseOutElastic(t float64) float64 {
+	if t == 0 || t == 1 {
+		return t
+	}
+	const c4 = (2 * math.Pi) / 3
+	return math.Pow(2, -10*t)*math.Sin((t*10-0.75)*c4) + 1
+}
+
+// Bounces against the end like a dropped ball
+func EaseOutBounce(t float64) float64 {
+	const n1 = 7.5625
+	const d1 = 2.75
+	switch {
+	case t < 1/d1:
+		return n1 * t * t
+	case t < 2/d1:
+		t -= 1.5 / d1
+		return n1*t*t + 0.75
+	case t < 2.5/d1:
+		t -= 2.25 / d1
+		return n1*t*t + 0.9375
+	default:
+		t -= 2.625 / d1
+		return n1*t*t + 0.984375
+	}
+}
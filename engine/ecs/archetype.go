@@ -0,0 +1,226 @@
+package ecs
+
+import (
+	"fmt"
+	"sort"
+)
+
+// A dense array of a single component type
+type column interface {
+	// Appends a zero value
+	appendZero()
+	// Appends the value at the row of the
+	// given column which must be the same type
+	appendFrom(src column, row int)
+	// Removes the row by moving the last row into it
+	swapRemove(row int)
+	// Creates an empty column of the same type
+	newEmpty() column
+}
+
+type typedColumn[T any] struct {
+	data []T
+}
+
+func (c *typedColumn[T]) appendZero() {
+	var zero T
+	c.data = append(c.data, zero)
+}
+
+func (c *typedColumn[T]) appendFrom(src column, row int) {
+	c.data = append(c.data, src.(*typedColumn[T]).data[row])
+}
+
+func (c *typedColumn[T]) swapRemove(row int) {
+	last := len(c.data) - 1
+	c.data[row] = c.data[last]
+	// Clear so references can be garbage collected
+	var zero T
+	c.data[last] = zero
+	c.data = c.data[:last]
+}
+
+func (c *typedColumn[T]) newEmpty() column {
+	return &typedColumn[T]{data: []T{}}
+}
+
+// A set of component ids
+type mask []uint64
+
+// Creates a mask of the given component ids
+func newMask(ids []ComponentID) mask {
+	m := mask{}
+	for _, id := range ids {
+		m = m.with(id)
+	}
+	return m
+}
+
+// Returns whether the id is in the mask
+func (m mask) has(id ComponentID) bool {
+	word := int(id) / 64
+	return word < len(m) && m[word]&(1<<(uint(id)%64)) != 0
+}
+
+// Returns a copy of the mask with the id added
+func (m mask) with(id ComponentID) mask {
+	word := int(id) / 64
+	out := make(mask, len(m))
+	copy(out, m)
+	for len(out) <= word {
+		out = append(out, 0)
+	}
+	out[word] |= 1 << (uint(id) % 64)
+	return out
+}
+
+// Returns whether every id in the other mask is in this mask
+func (m mask) containsAll(other mask) bool {
+	for i, word := range other {
+		if word == 0 {
+			continue
+		}
+		if i >= len(m) || m[i]&word != word {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns whether any id in the other mask is in this mask
+func (m mask) containsAny(other mask) bool {
+	for i, word := range other {
+		if i < len(m) && m[i]&word != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Stores all entities which have exactly
+// the same set of component types. Each component
+// type is stored in its own dense column so rows
+// line up with the entities slice
+type archetype struct {
+	// The component ids sorted ascending
+	ids []ComponentID
+
+	mask mask
+
+	// Maps the component id to its column
+	columns map[ComponentID]column
+
+	// The entity in each row
+	entities []Entity
+
+	// Caches the archetype reached by
+	// adding or removing a component
+	addEdges    map[ComponentID]*archetype
+	removeEdges map[ComponentID]*archetype
+}
+
+// Returns the key of an archetype with the given ids
+func archetypeKey(ids []ComponentID) string {
+	return fmt.Sprint(ids)
+}
+
+// Creates an archetype with the given component ids
+func (w *World) newArchetype(ids []ComponentID) *archetype {
+	sorted := make([]ComponentID, len(ids))
+	copy(sorted, ids)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	a := &archetype{
+		ids:         sorted,
+		mask:        newMask(sorted),
+		columns:     map[ComponentID]column{},
+		entities:    []Entity{},
+		addEdges:    map[ComponentID]*archetype{},
+		removeEdges: map[ComponentID]*archetype{},
+	}
+	for _, id := range sorted {
+		a.columns[id] = w.componentTypes[id].newColumn()
+	}
+	w.archetypes = append(w.archetypes, a)
+	w.archetypesByKey[archetypeKey(sorted)] = a
+	return a
+}
+
+// Gets or creates the archetype with the given ids
+func (w *World) getArchetype(ids []ComponentID) *archetype {
+	sorted := make([]ComponentID, len(ids))
+	copy(sorted, ids)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	if a, exists := w.archetypesByKey[archetypeKey(sorted)]; exists {
+		return a
+	}
+	return w.newArchetype(sorted)
+}
+
+// Gets the archetype with the component added
+func (w *World) archetypeWith(a *archetype, id ComponentID) *archetype {
+	if next, exists := a.addEdges[id]; exists {
+		return next
+	}
+	next := w.getArchetype(append(append([]ComponentID{}, a.ids...), id))
+	a.addEdges[id] = next
+	next.removeEdges[id] = a
+	return next
+}
+
+// Gets the archetype with the component removed
+func (w *World) archetypeWithout(a *archetype, id ComponentID) *archetype {
+	if next, exists := a.removeEdges[id]; exists {
+		return next
+	}
+	ids := []ComponentID{}
+	for _, aId := range a.ids {
+		if aId != id {
+			ids = append(ids, aId)
+		}
+	}
+	next := w.getArchetype(ids)
+	a.removeEdges[id] = next
+	next.addEdges[id] = a
+	return next
+}
+
+// Removes the row from the archetype and
+// updates the record of the entity moved into it
+func (w *World) removeRow(a *archetype, row int) {
+	for _, c := range a.columns {
+		c.swapRemove(row)
+	}
+	last := len(a.entities) - 1
+	moved := a.entities[last]
+	a.entities[row] = moved
+	a.entities = a.entities[:last]
+	if row != last {
+		w.records[moved.index()].row = row
+	}
+}
+
+// Moves an entity to another archetype, keeping the
+// components both archetypes have. New components are zero
+func (w *World) moveEntity(e Entity, to *archetype) {
+	record := &w.records[e.index()]
+	from := record.archetype
+	row := record.row
+
+	newRow := len(to.entities)
+	for id, c := range to.columns {
+		if fromColumn, exists := from.columns[id]; exists {
+			c.appendFrom(fromColumn, row)
+		} else {
+			c.appendZero()
+		}
+	}
+	to.entities = append(to.entities, e)
+	w.removeRow(from, row)
+
+	record.archetype = to
+	record.row = newRow
+}
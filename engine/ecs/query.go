@@ -0,0 +1,221 @@
+package ecs
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Creates a query over all entities which have every one of
+// the given components. Matching archetypes are cached and
+// updated as new archetypes are created
+func NewQuery(w *World, ids ...ComponentID) *Query {
+	return &Query{
+		world:   w,
+		ids:     ids,
+		mask:    newMask(ids),
+		matched: []*archetype{},
+	}
+}
+
+// Iterates over entities with a set of components
+type Query struct {
+	world *World
+
+	// The components an entity must have
+	ids  []ComponentID
+	mask mask
+
+	// Components an entity must not have
+	without mask
+
+	// The archetypes that match
+	matched []*archetype
+
+	// The number of world archetypes checked so far
+	checked int
+
+	// Guards matched and checked so the query
+	// can be used by systems running concurrently
+	lock sync.Mutex
+}
+
+// Excludes entities that have any of the given
+// components from the query
+func (q *Query) Without(ids ...ComponentID) *Query {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	for _, id := range ids {
+		q.without = q.without.with(id)
+	}
+	// Recheck every archetype
+	q.matched = []*archetype{}
+	q.checked = 0
+	return q
+}
+
+// Updates the matched archetypes with any created
+// since the last update and returns them
+func (q *Query) update() []*archetype {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	archetypes := q.world.archetypes
+	for ; q.checked < len(archetypes); q.checked++ {
+		a := archetypes[q.checked]
+		if a.mask.containsAll(q.mask) && !a.mask.containsAny(q.without) {
+			q.matched = append(q.matched, a)
+		}
+	}
+	return q.matched
+}
+
+// Returns the number of entities matching the query
+func (q *Query) Count() int {
+	count := 0
+	for _, a := range q.update() {
+		count += len(a.entities)
+	}
+	return count
+}
+
+// A range of rows of a single archetype. All the
+// entities in a chunk have the same components
+type Chunk struct {
+	world     *World
+	archetype *archetype
+	start     int
+	end       int
+}
+
+// Returns the number of entities in the chunk
+func (c *Chunk) Len() int {
+	return c.end - c.start
+}
+
+// Returns the entities in the chunk
+func (c *Chunk) Entities() []Entity {
+	return c.archetype.entities[c.start:c.end]
+}
+
+// Returns the components of type T in the chunk. The
+// slice lines up with Entities and can be written to.
+// Panics if the chunk doesn't have the component
+func Column[T any](c *Chunk) []T {
+	id, exists := ComponentIDOf[T](c.world)
+	if !exists {
+		var zero T
+		panic(fmt.Sprintf("ecs: component %T is not registered", zero))
+	}
+	col, exists := c.archetype.columns[id]
+	if !exists {
+		var zero T
+		panic(fmt.Sprintf("ecs: chunk has no component %T", zero))
+	}
+	return col.(*typedColumn[T]).data[c.start:c.end]
+}
+
+// Returns whether the chunk has the component
+func HasColumn[T any](c *Chunk) bool {
+	id, exists := ComponentIDOf[T](c.world)
+	if !exists {
+		return false
+	}
+	_, exists = c.archetype.columns[id]
+	return exists
+}
+
+// Marks the start of an iteration so structural
+// changes panic until it ends
+func (q *Query) beginIteration() {
+	atomic.AddInt32(&q.world.iterating, 1)
+}
+
+func (q *Query) endIteration() {
+	atomic.AddInt32(&q.world.iterating, -1)
+}
+
+// Calls the function with one chunk per matching archetype
+func (q *Query) Each(fn func(c *Chunk)) {
+	matched := q.update()
+	q.beginIteration()
+	defer q.endIteration()
+	for _, a := range matched {
+		if len(a.entities) == 0 {
+			continue
+		}
+		fn(&Chunk{
+			world:     q.world,
+			archetype: a,
+			start:     0,
+			end:       len(a.entities),
+		})
+	}
+}
+
+// Splits the matching entities into chunks of at most
+// chunkSize and calls the function on them concurrently
+// using the worker pool of the world. Without a pool the
+// chunks are run one by one. Chunks never overlap so writing
+// to the chunk columns is safe, but the function must not
+// write to anything else shared without synchronisation.
+// Blocks until every chunk is done
+func (q *Query) ParEach(chunkSize int, fn func(c *Chunk)) {
+	if chunkSize < 1 {
+		chunkSize = 1
+	}
+	chunks := []*Chunk{}
+	for _, a := range q.update() {
+		for start := 0; start < len(a.entities); start += chunkSize {
+			end := start + chunkSize
+			if end > len(a.entities) {
+				end = len(a.entities)
+			}
+			chunks = append(chunks, &Chunk{
+				world:     q.world,
+				archetype: a,
+				start:     start,
+				end:       end,
+			})
+		}
+	}
+
+	q.beginIteration()
+	defer q.endIteration()
+
+	if q.world.workerPool == nil {
+		for _, c := range chunks {
+			fn(c)
+		}
+		return
+	}
+	q.world.workerPool.ParallelFor(len(chunks), 1, func(start, end int) {
+		for _, c := range chunks[start:end] {
+			fn(c)
+		}
+	})
+}
+
+// Calls the function for every matching entity
+// with a pointer to its component of type A
+func Each1[A any](q *Query, fn func(e Entity, a *A)) {
+	q.Each(func(c *Chunk) {
+		entities := c.Entities()
+		as := Column[A](c)
+		for i := range entities {
+			fn(entities[i], &as[i])
+		}
+	})
+}
+
+// Calls the function for every matching entity with
+// pointers to its components of type A and B
+func Each2[A any, B any](q *Query, fn func(e Entity, a *A, b *B)) {
+	q.Each(func(c *Chunk) {
+		entities := c.Entities()
+		as := Column[A](c)
+		bs := Column[B](c)
+		for i := range entities {
+			fn(entities[i], &as[i], &bs[i])
+		}
+	})
+}
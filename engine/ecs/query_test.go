@@ -0,0 +1,155 @@
+package ecs
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ashleycheung/go-game/utils"
+)
+
+func TestQuery(t *testing.T) {
+	w := NewWorld()
+	posId := Register[Position](w)
+	velId := Register[Velocity](w)
+	healthId := Register[Health](w)
+
+	moving := NewQuery(w, posId, velId)
+	for i := 0; i < 5; i++ {
+		e := w.NewEntity()
+		Set(w, e, Position{})
+		Set(w, e, Velocity{X: 1})
+	}
+	// In a different archetype but still moving
+	e := w.NewEntity()
+	Set(w, e, Position{})
+	Set(w, e, Velocity{X: 1})
+	Set(w, e, Health{})
+	// Not moving
+	Set(w, w.NewEntity(), Position{})
+
+	if moving.Count() != 6 {
+		t.Error("wrong count", moving.Count())
+	}
+
+	Each2(moving, func(e Entity, p *Position, v *Velocity) {
+		p.X += v.X
+	})
+	total := 0.0
+	NewQuery(w, posId).Each(func(c *Chunk) {
+		for _, p := range Column[Position](c) {
+			total += p.X
+		}
+	})
+	if total != 6 {
+		t.Error("positions not updated", total)
+	}
+
+	if NewQuery(w, posId, velId).Without(healthId).Count() != 5 {
+		t.Error("without should exclude entities")
+	}
+}
+
+func TestParEach(t *testing.T) {
+	w := NewWorld()
+	posId := Register[Position](w)
+	velId := Register[Velocity](w)
+	for i := 0; i < 10000; i++ {
+		e := w.NewEntity()
+		Set(w, e, Position{})
+		Set(w, e, Velocity{X: 1, Y: 2})
+	}
+	pool := utils.NewWorkerPool(4)
+	defer pool.Close()
+	w.SetWorkerPool(pool)
+	q := NewQuery(w, posId, velId)
+	q.ParEach(256, func(c *Chunk) {
+		ps := Column[Position](c)
+		vs := Column[Velocity](c)
+		for i := range ps {
+			ps[i].X += vs[i].X
+			ps[i].Y += vs[i].Y
+		}
+	})
+	Each1(q, func(e Entity, p *Position) {
+		if p.X != 1 || p.Y != 2 {
+			t.Fatal("entity not updated", e)
+		}
+	})
+}
+
+func TestSystems(t *testing.T) {
+	w := NewWorld()
+	q := NewQuery(w, Register[Position](w), Register[Velocity](w))
+	e := w.NewEntity()
+	Set(w, e, Position{})
+	Set(w, e, Velocity{X: 1000})
+
+	w.AddSystem(System{
+		Name:  "movement",
+		Query: q,
+		Step: func(q *Query, delta float64) {
+			Each2(q, func(e Entity, p *Position, v *Velocity) {
+				p.X += v.X * delta / 1000
+			})
+		},
+	})
+	w.Step(500)
+	if p, _ := Get[Position](w, e); p.X != 500 {
+		t.Error("system not run", p.X)
+	}
+	w.RemoveSystem("movement")
+	w.Step(500)
+	if p, _ := Get[Position](w, e); p.X != 500 {
+		t.Error("system not removed", p.X)
+	}
+}
+
+func BenchmarkQuery(b *testing.B) {
+	w := NewWorld()
+	q := NewQuery(w, Register[Position](w), Register[Velocity](w))
+	for i := 0; i < 50000; i++ {
+		e := w.NewEntity()
+		Set(w, e, Position{})
+		Set(w, e, Velocity{X: 1})
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.Each(func(c *Chunk) {
+			ps := Column[Position](c)
+			vs := Column[Velocity](c)
+			for i := range ps {
+				ps[i].X += vs[i].X
+			}
+		})
+	}
+}
+
+// Chunks run on the pool of the world so at most the
+// workers and the calling goroutine run at once
+func TestParEachBounded(t *testing.T) {
+	w := NewWorld()
+	posId := Register[Position](w)
+	for i := 0; i < 100; i++ {
+		Set(w, w.NewEntity(), Position{})
+	}
+	pool := utils.NewWorkerPool(2)
+	defer pool.Close()
+	w.SetWorkerPool(pool)
+
+	var running, maxRunning int32
+	NewQuery(w, posId).ParEach(1, func(c *Chunk) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&running, -1)
+	})
+	if maxRunning > 3 {
+		t.Error("expected at most the workers and the caller to run chunks", maxRunning)
+	}
+}
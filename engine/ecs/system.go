@@ -0,0 +1,110 @@
+package ecs
+
+import "github.com/ashleycheung/go-game/utils"
+
+// A system runs every step over the
+// entities that match its query
+type System struct {
+	// Unique name of the system
+	Name string
+
+	// The components the system only reads
+	Reads []ComponentID
+
+	// The components the system writes
+	Writes []ComponentID
+
+	// The entities the system runs over. If nil and
+	// the system declares reads or writes, a query over
+	// entities with all of them is used
+	Query *Query
+
+	// Called every step with the query.
+	// Delta is the time passed in milliseconds
+	Step func(q *Query, delta float64)
+}
+
+// Adds a system which is run each step after all
+// the systems added before it. If a system with the
+// same name exists, it is replaced in place.
+//
+// Systems which declare their reads and writes can run at
+// the same time as earlier systems they don't conflict with
+// when the world has a worker pool. Systems that declare
+// neither always run on their own
+func (w *World) AddSystem(s System) {
+	if s.Query == nil && (len(s.Reads) != 0 || len(s.Writes) != 0) {
+		ids := append(append([]ComponentID{}, s.Reads...), s.Writes...)
+		s.Query = NewQuery(w, ids...)
+	}
+	w.stages = nil
+	for i, existing := range w.systems {
+		if existing.Name == s.Name {
+			newSystems := append([]System{}, w.systems...)
+			newSystems[i] = s
+			w.systems = newSystems
+			return
+		}
+	}
+	w.systems = append(w.systems, s)
+}
+
+// Removes the system of the given name
+func (w *World) RemoveSystem(name string) {
+	newSystems := []System{}
+	for _, s := range w.systems {
+		if s.Name != name {
+			newSystems = append(newSystems, s)
+		}
+	}
+	w.systems = newSystems
+	w.stages = nil
+}
+
+// Returns the names of the systems in run order
+func (w *World) SystemNames() []string {
+	names := make([]string, len(w.systems))
+	for i, s := range w.systems {
+		names[i] = s.Name
+	}
+	return names
+}
+
+// Sets the pool used to run non conflicting systems
+// and the chunks of ParEach at the same time.
+// If nil, they run one by one
+func (w *World) SetWorkerPool(pool *utils.WorkerPool) {
+	w.workerPool = pool
+}
+
+// Groups the systems into stages of systems
+// that can run at the same time
+func (w *World) buildStages(delta *float64) [][]utils.Job[ComponentID] {
+	jobs := make([]utils.Job[ComponentID], len(w.systems))
+	for i, s := range w.systems {
+		system := s
+		jobs[i] = utils.Job[ComponentID]{
+			Name:   system.Name,
+			Reads:  system.Reads,
+			Writes: system.Writes,
+			Run: func() {
+				system.Step(system.Query, *delta)
+			},
+		}
+	}
+	return utils.BuildStages(jobs)
+}
+
+// Runs every system. Systems run in the order they are added
+// unless they can run alongside an earlier system. Changes
+// deferred by the systems are applied after each stage
+func (w *World) Step(delta float64) {
+	if w.stages == nil {
+		w.stages = w.buildStages(&w.stepDelta)
+	}
+	w.stepDelta = delta
+	for _, stage := range w.stages {
+		utils.RunStages([][]utils.Job[ComponentID]{stage}, w.workerPool)
+		w.Flush()
+	}
+}
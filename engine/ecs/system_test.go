@@ -0,0 +1,68 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/ashleycheung/go-game/utils"
+)
+
+func TestParallelSystems(t *testing.T) {
+	w := NewWorld()
+	pool := utils.NewWorkerPool(4)
+	defer pool.Close()
+	w.SetWorkerPool(pool)
+
+	posId := Register[Position](w)
+	velId := Register[Velocity](w)
+	healthId := Register[Health](w)
+	for i := 0; i < 1000; i++ {
+		e := w.NewEntity()
+		Set(w, e, Position{})
+		Set(w, e, Velocity{X: 1})
+		Set(w, e, Health{Value: 10})
+	}
+
+	// Movement and damage don't conflict so run together.
+	// Death reads health so runs after damage
+	w.AddSystem(System{
+		Name:   "movement",
+		Reads:  []ComponentID{velId},
+		Writes: []ComponentID{posId},
+		Step: func(q *Query, delta float64) {
+			Each2(q, func(e Entity, v *Velocity, p *Position) {
+				p.X += v.X
+			})
+		},
+	})
+	w.AddSystem(System{
+		Name:   "damage",
+		Writes: []ComponentID{healthId},
+		Step: func(q *Query, delta float64) {
+			Each1(q, func(e Entity, h *Health) {
+				h.Value -= 10
+			})
+		},
+	})
+	w.AddSystem(System{
+		Name:  "death",
+		Reads: []ComponentID{healthId},
+		Step: func(q *Query, delta float64) {
+			Each1(q, func(e Entity, h *Health) {
+				if h.Value <= 0 {
+					w.Defer(func(w *World) {
+						w.Destroy(e)
+					})
+				}
+			})
+		},
+	})
+
+	if len(w.buildStages(&w.stepDelta)) != 2 {
+		t.Error("movement and damage should share a stage")
+	}
+
+	w.Step(10)
+	if w.EntityCount() != 0 {
+		t.Error("death system should run after damage", w.EntityCount())
+	}
+}
@@ -0,0 +1,296 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ashleycheung/go-game/utils"
+)
+
+// An entity is a handle to a set of components.
+// The lower 32 bits are the index and the upper
+// 32 bits are the generation, so handles to destroyed
+// entities are never mistaken for new ones.
+// The zero entity is never alive
+type Entity uint64
+
+func newEntity(index, generation uint32) Entity {
+	return Entity(uint64(generation)<<32 | uint64(index))
+}
+
+func (e Entity) index() uint32 {
+	return uint32(e)
+}
+
+func (e Entity) generation() uint32 {
+	return uint32(e >> 32)
+}
+
+func (e Entity) String() string {
+	return fmt.Sprintf("Entity(%d:%d)", e.index(), e.generation())
+}
+
+// The id of a registered component type
+type ComponentID int
+
+// A registered component type
+type componentType struct {
+	reflectType reflect.Type
+	newColumn   func() column
+}
+
+// Where an entity is stored
+type entityRecord struct {
+	generation uint32
+	alive      bool
+	archetype  *archetype
+	row        int
+}
+
+// Creates a new empty ecs world
+func NewWorld() *World {
+	w := &World{
+		componentIds:    map[reflect.Type]ComponentID{},
+		componentTypes:  []componentType{},
+		archetypes:      []*archetype{},
+		archetypesByKey: map[string]*archetype{},
+		records:         []entityRecord{},
+		freeIndices:     []uint32{},
+		systems:         []System{},
+		deferred:        []func(w *World){},
+	}
+	w.emptyArchetype = w.newArchetype([]ComponentID{})
+	return w
+}
+
+// Stores entities and their components by archetype.
+// Every entity with the same set of component types is
+// stored together in dense arrays, so iterating over
+// thousands of entities is fast.
+//
+// The world is not safe for concurrent structural changes.
+// Queries can be iterated in parallel, but adding or removing
+// entities and components while iterating panics and should
+// be deferred with Defer instead
+type World struct {
+	// Maps the go type to the component id
+	componentIds map[reflect.Type]ComponentID
+
+	// Indexed by the component id
+	componentTypes []componentType
+
+	// Every archetype created. Archetypes
+	// are never removed
+	archetypes []*archetype
+
+	archetypesByKey map[string]*archetype
+
+	// The archetype of entities with no components
+	emptyArchetype *archetype
+
+	// Indexed by the entity index
+	records []entityRecord
+
+	// Entity indices that can be reused
+	freeIndices []uint32
+
+	// Number of entities alive
+	entityCount int
+
+	// Systems run each step in order
+	systems []System
+
+	// The systems grouped by what can run
+	// at the same time. Nil when out of date
+	stages [][]utils.Job[ComponentID]
+
+	// The delta of the current step
+	// passed to the systems
+	stepDelta float64
+
+	// Runs systems concurrently if not nil
+	workerPool *utils.WorkerPool
+
+	// The number of queries currently iterating.
+	// Structural changes are not allowed while
+	// this is more than 0
+	iterating int32
+
+	// Guards deferred
+	deferredLock sync.Mutex
+
+	// Changes to apply once iteration finishes
+	deferred []func(w *World)
+}
+
+// Registers a component type and returns its id.
+// Registering the same type again returns the same id.
+// New types can't be registered while iterating
+func Register[T any](w *World) ComponentID {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if id, exists := w.componentIds[t]; exists {
+		return id
+	}
+	w.checkNotIterating()
+	id := ComponentID(len(w.componentTypes))
+	w.componentIds[t] = id
+	w.componentTypes = append(w.componentTypes, componentType{
+		reflectType: t,
+		newColumn: func() column {
+			return &typedColumn[T]{data: []T{}}
+		},
+	})
+	return id
+}
+
+// Gets the id of a registered component type
+func ComponentIDOf[T any](w *World) (ComponentID, bool) {
+	id, exists := w.componentIds[reflect.TypeOf((*T)(nil)).Elem()]
+	return id, exists
+}
+
+// Panics if the world is being iterated
+func (w *World) checkNotIterating() {
+	if atomic.LoadInt32(&w.iterating) > 0 {
+		panic("ecs: can't add or remove entities or components while iterating, use Defer instead")
+	}
+}
+
+// Creates a new entity with no components
+func (w *World) NewEntity() Entity {
+	w.checkNotIterating()
+	var index uint32
+	if len(w.freeIndices) > 0 {
+		index = w.freeIndices[len(w.freeIndices)-1]
+		w.freeIndices = w.freeIndices[:len(w.freeIndices)-1]
+	} else {
+		index = uint32(len(w.records))
+		w.records = append(w.records, entityRecord{})
+	}
+	record := &w.records[index]
+	record.generation++
+	record.alive = true
+	record.archetype = w.emptyArchetype
+	record.row = len(w.emptyArchetype.entities)
+
+	e := newEntity(index, record.generation)
+	w.emptyArchetype.entities = append(w.emptyArchetype.entities, e)
+	w.entityCount++
+	return e
+}
+
+// Returns whether the entity has not been destroyed
+func (w *World) IsAlive(e Entity) bool {
+	index := e.index()
+	return int(index) < len(w.records) &&
+		w.records[index].alive &&
+		w.records[index].generation == e.generation()
+}
+
+// Destroys the entity and all its components.
+// Does nothing if the entity is not alive
+func (w *World) Destroy(e Entity) {
+	w.checkNotIterating()
+	if !w.IsAlive(e) {
+		return
+	}
+	record := &w.records[e.index()]
+	w.removeRow(record.archetype, record.row)
+	record.alive = false
+	record.archetype = nil
+	w.freeIndices = append(w.freeIndices, e.index())
+	w.entityCount--
+}
+
+// Returns the number of entities alive
+func (w *World) EntityCount() int {
+	return w.entityCount
+}
+
+// Sets the component of the entity, adding it if
+// the entity doesn't have it yet. The type is
+// registered if it hasn't been, which like adding
+// a component can't be done while iterating
+func Set[T any](w *World, e Entity, value T) {
+	if !w.IsAlive(e) {
+		panic(fmt.Sprintf("ecs: %s is not alive", e))
+	}
+	id := Register[T](w)
+	record := &w.records[e.index()]
+	if !record.archetype.mask.has(id) {
+		w.checkNotIterating()
+		w.moveEntity(e, w.archetypeWith(record.archetype, id))
+	}
+	record.archetype.columns[id].(*typedColumn[T]).data[record.row] = value
+}
+
+// Gets a pointer to the component of the entity.
+// The pointer is only valid until the next structural change
+func Get[T any](w *World, e Entity) (*T, bool) {
+	if !w.IsAlive(e) {
+		return nil, false
+	}
+	id, exists := ComponentIDOf[T](w)
+	if !exists {
+		return nil, false
+	}
+	record := w.records[e.index()]
+	c, exists := record.archetype.columns[id]
+	if !exists {
+		return nil, false
+	}
+	return &c.(*typedColumn[T]).data[record.row], true
+}
+
+// Returns whether the entity has the component
+func Has[T any](w *World, e Entity) bool {
+	_, exists := Get[T](w, e)
+	return exists
+}
+
+// Removes the component from the entity.
+// Does nothing if the entity doesn't have it
+func Remove[T any](w *World, e Entity) {
+	if !w.IsAlive(e) {
+		return
+	}
+	id, exists := ComponentIDOf[T](w)
+	if !exists {
+		return
+	}
+	record := w.records[e.index()]
+	if !record.archetype.mask.has(id) {
+		return
+	}
+	w.checkNotIterating()
+	w.moveEntity(e, w.archetypeWithout(record.archetype, id))
+}
+
+// Queues a change to be applied once nothing is
+// iterating, at the end of the current stage of systems
+// or when Flush is called. Safe to call from multiple goroutines
+func (w *World) Defer(fn func(w *World)) {
+	w.deferredLock.Lock()
+	defer w.deferredLock.Unlock()
+	w.deferred = append(w.deferred, fn)
+}
+
+// Applies all the deferred changes.
+// Changes deferred while flushing are also applied
+func (w *World) Flush() {
+	w.checkNotIterating()
+	for {
+		w.deferredLock.Lock()
+		currDeferred := w.deferred
+		w.deferred = []func(w *World){}
+		w.deferredLock.Unlock()
+		if len(currDeferred) == 0 {
+			return
+		}
+		for _, fn := range currDeferred {
+			fn(w)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package ecs
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/ashleycheung/go-game/utils"
+)
+
+type Position struct {
+	X, Y float64
+}
+
+type Velocity struct {
+	X, Y float64
+}
+
+type Health struct {
+	Value int
+}
+
+func TestEntityLifecycle(t *testing.T) {
+	w := NewWorld()
+	e1 := w.NewEntity()
+	e2 := w.NewEntity()
+	if !w.IsAlive(e1) || !w.IsAlive(e2) {
+		t.Error("entities should be alive")
+	}
+	if w.EntityCount() != 2 {
+		t.Error("wrong entity count")
+	}
+	w.Destroy(e1)
+	if w.IsAlive(e1) {
+		t.Error("entity should be destroyed")
+	}
+
+	// The index is reused with a new generation
+	e3 := w.NewEntity()
+	if e3 == e1 {
+		t.Error("reused entity should have a new generation")
+	}
+	if w.IsAlive(e1) {
+		t.Error("old handle should not be alive")
+	}
+	if w.IsAlive(Entity(0)) {
+		t.Error("zero entity should never be alive")
+	}
+}
+
+func TestComponents(t *testing.T) {
+	w := NewWorld()
+	e1 := w.NewEntity()
+	e2 := w.NewEntity()
+	Set(w, e1, Position{X: 1})
+	Set(w, e1, Velocity{X: 2})
+	Set(w, e2, Position{X: 3})
+
+	pos, ok := Get[Position](w, e1)
+	if !ok || pos.X != 1 {
+		t.Error("position not set")
+	}
+	pos.X = 10
+	if p, _ := Get[Position](w, e1); p.X != 10 {
+		t.Error("pointer should write to the component")
+	}
+	if !Has[Velocity](w, e1) || Has[Velocity](w, e2) {
+		t.Error("wrong velocity components")
+	}
+
+	// Removing keeps the other components
+	Remove[Velocity](w, e1)
+	if Has[Velocity](w, e1) {
+		t.Error("velocity not removed")
+	}
+	if p, _ := Get[Position](w, e1); p.X != 10 {
+		t.Error("position lost when moving archetype")
+	}
+
+	// Destroying an entity moves another into its row
+	w.Destroy(e1)
+	if p, _ := Get[Position](w, e2); p.X != 3 {
+		t.Error("wrong component after destroy")
+	}
+	if _, ok := Get[Health](w, e2); ok {
+		t.Error("unregistered component should not be found")
+	}
+}
+
+func TestStructuralChangeWhileIterating(t *testing.T) {
+	w := NewWorld()
+	e := w.NewEntity()
+	Set(w, e, Position{})
+	q := NewQuery(w, Register[Position](w))
+
+	defer func() {
+		if recover() == nil {
+			t.Error("structural change while iterating should panic")
+		}
+	}()
+	q.Each(func(c *Chunk) {
+		w.NewEntity()
+	})
+}
+
+// Registering a type from a parallel query
+// used to write the type maps before panicking
+func TestRegisterWhileIterating(t *testing.T) {
+	w := NewWorld()
+	for i := 0; i < 100; i++ {
+		Set(w, w.NewEntity(), Position{})
+	}
+	pool := utils.NewWorkerPool(4)
+	defer pool.Close()
+	w.SetWorkerPool(pool)
+	q := NewQuery(w, Register[Position](w))
+	panics := int32(0)
+	lock := sync.Mutex{}
+	q.ParEach(10, func(c *Chunk) {
+		defer func() {
+			if recover() != nil {
+				lock.Lock()
+				panics++
+				lock.Unlock()
+			}
+		}()
+		Set(w, c.Entities()[0], Velocity{})
+	})
+	if panics == 0 {
+		t.Error("setting a new type while iterating should panic")
+	}
+	if _, exists := ComponentIDOf[Velocity](w); exists {
+		t.Error("the type shouldn't be registered")
+	}
+}
+
+func TestDefer(t *testing.T) {
+	w := NewWorld()
+	for i := 0; i < 10; i++ {
+		Set(w, w.NewEntity(), Health{Value: i})
+	}
+	q := NewQuery(w, Register[Health](w))
+	Each1(q, func(e Entity, h *Health) {
+		if h.Value%2 == 0 {
+			w.Defer(func(w *World) {
+				w.Destroy(e)
+			})
+		}
+	})
+	w.Flush()
+	if q.Count() != 5 {
+		t.Error("deferred destroys not applied", q.Count())
+	}
+}
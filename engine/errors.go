@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"log"
+
+	"github.com/ashleycheung/go-game/event"
+	"github.com/ashleycheung/go-game/physics"
+)
+
+// Creates an event manager which calls every listener
+// even if some fail and turns listener panics into errors
+func newEventManager[T comparable]() *event.EventManager[T] {
+	m := event.NewEventManager[T]()
+	m.SetErrorPolicy(event.ContinueAndAggregate)
+	m.SetRecoverPanics(true)
+	return m
+}
+
+// Emits the error as an OnWorldErrorEvent.
+// Logged if nothing listens for it by name
+func (w *GameWorld) ReportError(err error) {
+	if err == nil {
+		return
+	}
+	if w.Event.ExactListenerCount(OnWorldErrorEvent) == 0 {
+		log.Println("game world:", err)
+		return
+	}
+	// Errors of the error listeners aren't reported
+	event.Emit(w.Event, OnWorldErrorTopic, err)
+}
+
+// Reports the error to the world of the object.
+// Logged if the object isn't in a world
+func (g *GameObject) reportError(err error) {
+	if err == nil {
+		return
+	}
+	if g == nil || g.World == nil {
+		log.Println("game object:", err)
+		return
+	}
+	g.World.ReportError(err)
+}
+
+// Reports the errors of the physics world listeners
+func (w *GameWorld) reportPhysicsErrors() {
+	event.On(w.Physics.Event, physics.ErrorTopic, func(err error) error {
+		w.ReportError(err)
+		return nil
+	})
+}
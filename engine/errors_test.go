@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ashleycheung/go-game/event"
+	"github.com/ashleycheung/go-game/physics"
+)
+
+func TestWorldErrorEvent(t *testing.T) {
+	w := NewGameWorld()
+	errs := []error{}
+	w.Event.AddListener(OnWorldErrorEvent, func(e event.Event[WorldEvent]) error {
+		errs = append(errs, e.Data.(error))
+		return nil
+	})
+
+	// A buggy listener shouldn't stop the others
+	obj := NewGameObject()
+	obj.Event.AddListener(OnGameObjectStepEvent, func(e event.Event[GameObjectEvent]) error {
+		panic("buggy listener")
+	})
+	stepped := 0
+	obj.Event.AddListener(OnGameObjectStepEvent, func(e event.Event[GameObjectEvent]) error {
+		stepped++
+		return nil
+	})
+	w.Scene.AddChild(obj)
+	w.Step(16)
+	if stepped != 1 {
+		t.Error("expected the other listener to be called", stepped)
+	}
+	var panicErr *event.ListenerPanicError
+	if len(errs) != 1 || !errors.As(errs[0], &panicErr) {
+		t.Fatal("expected the panic reported", errs)
+	}
+
+	// Physics listener errors are reported too
+	errs = []error{}
+	collider := NewGameObject()
+	pC := NewPhysicsComponent(physics.Circle{Radius: 5})
+	pC.Event.AddListener(OnPhysicsComponentCollideEvent, func(e event.Event[PhysicsComponentEvent]) error {
+		return errors.New("collide failed")
+	})
+	collider.AddComponent("physics", pC)
+	other := NewGameObject()
+	other.AddComponent("physics", NewPhysicsComponent(physics.Circle{Radius: 5}))
+	other.SetPosition(physics.Vector{X: 5})
+	w.Scene.AddChild(collider)
+	w.Scene.AddChild(other)
+	w.Step(16)
+	// The buggy step listener fails again
+	if len(errs) != 2 || !strings.Contains(errs[1].Error(), "collide failed") {
+		t.Error("expected the collision error reported", errs)
+	}
+}
+
+// Errors are still logged when only a
+// catch all listener is on the world
+func TestWorldErrorCatchAll(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	w := NewGameWorld()
+	w.Event.AddCatchAllListener(func(e event.Event[WorldEvent]) error {
+		return nil
+	})
+	w.ReportError(errors.New("step failed"))
+	if !strings.Contains(logged.String(), "step failed") {
+		t.Error("expected the error logged", logged.String())
+	}
+}
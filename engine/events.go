@@ -1,5 +1,10 @@
 package engine
 
+import (
+	"github.com/ashleycheung/go-game/event"
+	"github.com/ashleycheung/go-game/physics"
+)
+
 // Stores all the events in the game engine
 type GameObjectEvent string
 
@@ -23,6 +28,9 @@ const (
 	BeforeGameStepEvent WorldEvent = "beforeGameStepEvent"
 	// Called after the game step finishes
 	AfterGameStepEvent WorldEvent = "afterGameStepEvent"
+	// Called when a listener in the world fails,
+	// including physics listeners. The data is the error
+	OnWorldErrorEvent WorldEvent = "onWorldErrorEvent"
 )
 
 type PhysicsComponentEvent string
@@ -43,3 +51,63 @@ const (
 	OnTimerEndEvent   TimerComponentEvent = "onTimerEndEvent"
 	OnTimerStartEvent TimerComponentEvent = "onTimerStartEvent"
 )
+
+type TweenEvent string
+
+const (
+	// Called when a looping tween starts its next loop.
+	// The data is the number of loops finished
+	OnTweenLoopEvent TweenEvent = "onTweenLoopEvent"
+	// Called when a tween finishes all its loops
+	OnTweenCompleteEvent TweenEvent = "onTweenCompleteEvent"
+)
+
+type StateMachineEvent string
+
+const (
+	// Called when a state is entered.
+	// The data is the name of the state
+	OnStateEnterEvent StateMachineEvent = "onStateEnterEvent"
+	// Called when a state is exited.
+	// The data is the name of the state
+	OnStateExitEvent StateMachineEvent = "onStateExitEvent"
+	// Called when the state machine changes state.
+	// The data is the StateTransition
+	OnStateTransitionEvent StateMachineEvent = "onStateTransitionEvent"
+)
+
+type NavigationAgentEvent string
+
+const (
+	// Called when the agent reaches its target
+	OnNavigationTargetReachedEvent NavigationAgentEvent = "onNavigationTargetReachedEvent"
+	// Called when the agent was blocked and found a new path
+	OnNavigationRepathEvent NavigationAgentEvent = "onNavigationRepathEvent"
+	// Called when no path to the target can be found
+	OnNavigationFailedEvent NavigationAgentEvent = "onNavigationFailedEvent"
+)
+
+// Typed topics of the engine events
+var (
+	OnSceneEnterTopic      = event.NewTopic[GameObjectEvent, struct{}](OnSceneEnterEvent)
+	OnSceneExitTopic       = event.NewTopic[GameObjectEvent, struct{}](OnSceneExitEvent)
+	OnGameObjectStepTopic  = event.NewTopic[GameObjectEvent, struct{}](OnGameObjectStepEvent)
+	BeforeGameStepTopic    = event.NewTopic[WorldEvent, struct{}](BeforeGameStepEvent)
+	AfterGameStepTopic     = event.NewTopic[WorldEvent, struct{}](AfterGameStepEvent)
+	OnWorldErrorTopic      = event.NewTopic[WorldEvent, error](OnWorldErrorEvent)
+	OnTimerStartTopic      = event.NewTopic[TimerComponentEvent, struct{}](OnTimerStartEvent)
+	OnTimerEndTopic        = event.NewTopic[TimerComponentEvent, struct{}](OnTimerEndEvent)
+	OnTweenLoopTopic       = event.NewTopic[TweenEvent, int](OnTweenLoopEvent)
+	OnTweenCompleteTopic   = event.NewTopic[TweenEvent, struct{}](OnTweenCompleteEvent)
+	OnStateEnterTopic      = event.NewTopic[StateMachineEvent, string](OnStateEnterEvent)
+	OnStateExitTopic       = event.NewTopic[StateMachineEvent, string](OnStateExitEvent)
+	OnStateTransitionTopic = event.NewTopic[StateMachineEvent, StateTransition](OnStateTransitionEvent)
+
+	OnPhysicsComponentCollideTopic = event.NewTopic[PhysicsComponentEvent, OnPhysicsComponentCollideData](
+		OnPhysicsComponentCollideEvent,
+	)
+
+	OnNavigationTargetReachedTopic = event.NewTopic[NavigationAgentEvent, physics.Vector](OnNavigationTargetReachedEvent)
+	OnNavigationRepathTopic        = event.NewTopic[NavigationAgentEvent, physics.Vector](OnNavigationRepathEvent)
+	OnNavigationFailedTopic        = event.NewTopic[NavigationAgentEvent, physics.Vector](OnNavigationFailedEvent)
+)
@@ -0,0 +1,277 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Returned by RunContext when the world is already running
+var ErrWorldAlreadyRunning = errors.New("can't run world as it is already running")
+
+// Returned by StepOnce when the world isn't paused
+var ErrWorldNotPaused = errors.New("world is not paused")
+
+// How long a paused loop with no fps waits
+// between processing queued functions
+const pausedPollInterval = 10 * time.Millisecond
+
+// The delta used by StepOnce when the loop has no fps
+const defaultStepDelta = 1000.0 / 60.0
+
+// Configures how the world is run
+type LoopOptions struct {
+	// The number of steps per second.
+	// If 0 or -1, it runs at highest possible fps
+	FPS int
+
+	// Multiplies the delta passed to each step.
+	// 0 keeps the current time scale
+	TimeScale float64
+
+	// The max delta in milliseconds of a single step
+	// before scaling. Stops a slow step from causing
+	// ever larger steps. 0 means no limit
+	MaxDelta float64
+}
+
+// Statistics about the running loop
+type LoopStats struct {
+	// The steps per second measured
+	// over the last second
+	FPS float64
+
+	// The number of steps since the world started running
+	Ticks int
+
+	// The number of steps which took longer than a frame
+	Overruns int
+
+	// The number of steps whose delta was
+	// reduced to the max delta
+	Clamped int
+
+	// The delta in milliseconds of the last step
+	LastDelta float64
+
+	// How long the last step took to process
+	LastStepDuration time.Duration
+}
+
+// Runs the world until Stop is called or the context is
+// done. Returns the context error if the context finished
+// it and ErrWorldAlreadyRunning if the world is running
+func (w *GameWorld) RunContext(ctx context.Context, opts LoopOptions) error {
+	w.lock.Lock()
+	if w.running {
+		w.lock.Unlock()
+		return ErrWorldAlreadyRunning
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	w.running = true
+	w.cancelRun = cancel
+	w.stats = LoopStats{}
+	if opts.TimeScale > 0 {
+		w.timeScale = opts.TimeScale
+	}
+	w.lock.Unlock()
+	defer w.finishRun()
+
+	// The time of a single frame. 0 if unlimited
+	var frameTime time.Duration
+	if opts.FPS > 0 {
+		frameTime = time.Duration(float64(time.Second) / float64(opts.FPS))
+	}
+
+	currTime := w.clock.Now()
+	w.worldStartTime = currTime
+	fpsWindowStart := currTime
+	fpsWindowTicks := 0
+	for runCtx.Err() == nil {
+		w.lock.Lock()
+		paused := w.paused
+		timeScale := w.timeScale
+		stepOnce := paused && w.pendingSteps > 0
+		if stepOnce {
+			w.pendingSteps--
+		}
+		w.lock.Unlock()
+
+		// Calculate delta
+		newTime := w.clock.Now()
+		delta := float64(newTime.Sub(currTime).Microseconds()) / 1000.0
+		currTime = newTime
+
+		if paused && !stepOnce {
+			// Time doesn't pass while paused, but functions
+			// are still called so the world can be changed
+			w.processFunctions()
+			waitTime := frameTime
+			if waitTime == 0 {
+				waitTime = pausedPollInterval
+			}
+			// Waits in real time so a simulated clock
+			// isn't moved forward while paused
+			w.waitPaused(runCtx, waitTime)
+			continue
+		}
+
+		// Single steps always move a whole frame
+		if stepOnce {
+			delta = defaultStepDelta
+			if frameTime != 0 {
+				delta = float64(frameTime.Microseconds()) / 1000.0
+			}
+		}
+
+		clamped := opts.MaxDelta > 0 && delta > opts.MaxDelta
+		if clamped {
+			delta = opts.MaxDelta
+		}
+		delta *= timeScale
+
+		// Step world
+		startProcessTime := w.clock.Now()
+		w.Step(delta)
+		processingTime := w.clock.Since(startProcessTime)
+
+		// Update the stats
+		fpsWindowTicks++
+		w.lock.Lock()
+		w.stats.Ticks++
+		w.stats.LastDelta = delta
+		w.stats.LastStepDuration = processingTime
+		if clamped {
+			w.stats.Clamped++
+		}
+		if frameTime != 0 && processingTime > frameTime {
+			w.stats.Overruns++
+		}
+		if elapsed := w.clock.Since(fpsWindowStart); elapsed >= time.Second {
+			w.stats.FPS = float64(fpsWindowTicks) / elapsed.Seconds()
+			fpsWindowStart = w.clock.Now()
+			fpsWindowTicks = 0
+		}
+		w.lock.Unlock()
+
+		// Sleep and wait for next tick
+		// or until stopped
+		if frameTime != 0 {
+			w.wait(runCtx, frameTime-processingTime)
+		}
+	}
+
+	// Stopped with Stop rather than the context
+	if ctx.Err() == nil {
+		return nil
+	}
+	return ctx.Err()
+}
+
+// Waits for the duration on the world clock, until the
+// context is done or until the paused loop is woken
+func (w *GameWorld) wait(ctx context.Context, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	waitTimer := w.clock.NewTimer(d)
+	defer waitTimer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-w.wake:
+	case <-waitTimer.C():
+	}
+}
+
+// Same as wait but in real time
+func (w *GameWorld) waitPaused(ctx context.Context, d time.Duration) {
+	waitTimer := time.NewTimer(d)
+	defer waitTimer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-w.wake:
+	case <-waitTimer.C:
+	}
+}
+
+// Wakes the loop if it is waiting
+func (w *GameWorld) wakeLoop() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Pauses the world. Steps stop until Resume
+// is called, but queued functions are still called.
+// Safe to call from any goroutine
+func (w *GameWorld) Pause() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.paused = true
+}
+
+// Resumes a paused world. Safe to call from any goroutine
+func (w *GameWorld) Resume() {
+	w.lock.Lock()
+	w.paused = false
+	w.pendingSteps = 0
+	w.lock.Unlock()
+	w.wakeLoop()
+}
+
+// Returns whether the world is paused
+func (w *GameWorld) IsPaused() bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.paused
+}
+
+// Steps a paused world by a single frame. If the world
+// is running, the step happens on the world goroutine,
+// otherwise it happens straight away.
+// Returns ErrWorldNotPaused if the world isn't paused
+func (w *GameWorld) StepOnce() error {
+	w.lock.Lock()
+	if !w.paused {
+		w.lock.Unlock()
+		return ErrWorldNotPaused
+	}
+	if !w.running {
+		timeScale := w.timeScale
+		w.lock.Unlock()
+		w.Step(defaultStepDelta * timeScale)
+		return nil
+	}
+	w.pendingSteps++
+	w.lock.Unlock()
+	w.wakeLoop()
+	return nil
+}
+
+// Sets the multiplier of the delta passed to each step.
+// Less than 1 is slow motion and more than 1 is fast forward.
+// Negative values are treated as 0
+func (w *GameWorld) SetTimeScale(scale float64) {
+	if scale < 0 {
+		scale = 0
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.timeScale = scale
+}
+
+// Returns the multiplier of the delta passed to each step
+func (w *GameWorld) GetTimeScale() float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.timeScale
+}
+
+// Returns statistics about the current
+// or last run. Safe to call from any goroutine
+func (w *GameWorld) Stats() LoopStats {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.stats
+}
@@ -0,0 +1,201 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ashleycheung/go-game/clock"
+	"github.com/ashleycheung/go-game/event"
+)
+
+// Records the deltas of every step
+type deltaRecorder struct {
+	lock   sync.Mutex
+	deltas []float64
+}
+
+func (r *deltaRecorder) step(delta float64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.deltas = append(r.deltas, delta)
+}
+
+func (r *deltaRecorder) get() []float64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]float64{}, r.deltas...)
+}
+
+// Waits for the condition to be true or fails the test
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRunContext(t *testing.T) {
+	w := NewGameWorld()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := w.RunContext(ctx, LoopOptions{FPS: 100})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("expected the context error, got", err)
+	}
+	if w.IsRunning() {
+		t.Error("world should have stopped")
+	}
+	if w.Stats().Ticks == 0 {
+		t.Error("world should have stepped")
+	}
+}
+
+func TestPauseAndStepOnce(t *testing.T) {
+	w := NewGameWorld()
+	recorder := &deltaRecorder{}
+	w.AddSystem(System{Name: "record", Step: recorder.step})
+
+	if err := w.StepOnce(); !errors.Is(err, ErrWorldNotPaused) {
+		t.Error("expected ErrWorldNotPaused, got", err)
+	}
+
+	w.Pause()
+	done := make(chan error)
+	go func() {
+		done <- w.RunContext(context.Background(), LoopOptions{FPS: 100, TimeScale: 0.5})
+	}()
+	waitFor(t, w.IsRunning)
+	time.Sleep(30 * time.Millisecond)
+	if len(recorder.get()) != 0 {
+		t.Error("paused world should not step", recorder.get())
+	}
+
+	// Functions are still called while paused
+	if err := w.Do(func() {}); err != nil {
+		t.Error(err)
+	}
+
+	if err := w.StepOnce(); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return len(recorder.get()) == 1 })
+	time.Sleep(30 * time.Millisecond)
+	deltas := recorder.get()
+	if len(deltas) != 1 || deltas[0] != 5 {
+		t.Error("expected a single step of half a frame", deltas)
+	}
+
+	w.Resume()
+	waitFor(t, func() bool { return len(recorder.get()) > 3 })
+	w.Stop()
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMaxDelta(t *testing.T) {
+	w := NewGameWorld()
+	recorder := &deltaRecorder{}
+	w.AddSystem(System{Name: "slow", Step: func(delta float64) {
+		recorder.step(delta)
+		time.Sleep(20 * time.Millisecond)
+	}})
+	done := make(chan error)
+	go func() {
+		done <- w.RunContext(context.Background(), LoopOptions{FPS: 100, MaxDelta: 5})
+	}()
+	waitFor(t, func() bool { return len(recorder.get()) > 3 })
+	w.Stop()
+	<-done
+
+	for _, delta := range recorder.get() {
+		if delta > 5 {
+			t.Error("delta should be clamped", delta)
+		}
+	}
+	stats := w.Stats()
+	if stats.Clamped == 0 || stats.Overruns == 0 {
+		t.Error("expected clamped and overrun steps", stats)
+	}
+}
+
+func TestAlreadyRunning(t *testing.T) {
+	w := NewGameWorld()
+	stopped := runWorld(t, w)
+	err := w.RunContext(context.Background(), LoopOptions{})
+	if !errors.Is(err, ErrWorldAlreadyRunning) {
+		t.Error("expected ErrWorldAlreadyRunning, got", err)
+	}
+	w.Stop()
+	<-stopped
+}
+
+func TestSimulatedMatch(t *testing.T) {
+	w := NewGameWorld()
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	w.SetClock(clock.NewManual(start))
+
+	// A 10 minute match timer
+	obj := NewGameObject()
+	timer := NewTimerComponent()
+	timer.Duration = float64((10 * time.Minute).Milliseconds())
+	obj.AddComponent("timer", timer)
+	w.Scene.AddChild(obj)
+	timer.Start()
+	timer.Event.AddListener(OnTimerEndEvent, func(e event.Event[TimerComponentEvent]) error {
+		w.Stop()
+		return nil
+	})
+
+	realStart := time.Now()
+	if err := w.RunContext(context.Background(), LoopOptions{FPS: 60}); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(realStart) > 10*time.Second {
+		t.Error("simulated match should not take real time", time.Since(realStart))
+	}
+	if worldTime := w.GetWorldTime(); worldTime < 10*60*1000 || worldTime > 10*60*1000+100 {
+		t.Error("expected 10 minutes to pass", worldTime)
+	}
+	if ticks := w.Stats().Ticks; ticks < 35990 || ticks > 36010 {
+		t.Error("expected 60 steps a second", ticks)
+	}
+}
+
+// A paused world on a manual clock shouldn't move
+// the simulated time forward while it waits
+func TestPausedManualClock(t *testing.T) {
+	w := NewGameWorld()
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	manual := clock.NewManual(start)
+	w.SetClock(manual)
+	recorder := &deltaRecorder{}
+	w.AddSystem(System{Name: "record", Step: recorder.step})
+
+	w.Pause()
+	done := make(chan error)
+	go func() {
+		done <- w.RunContext(context.Background(), LoopOptions{})
+	}()
+	waitFor(t, w.IsRunning)
+	time.Sleep(30 * time.Millisecond)
+	if err := w.Do(func() {}); err != nil {
+		t.Error(err)
+	}
+	if now := manual.Now(); !now.Equal(start) {
+		t.Error("paused world should not advance the clock", now.Sub(start))
+	}
+	if len(recorder.get()) != 0 {
+		t.Error("paused world should not step", recorder.get())
+	}
+	w.Stop()
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+}
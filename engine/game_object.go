@@ -11,8 +11,11 @@ func NewGameObject() *GameObject {
 	obj := &GameObject{}
 	obj.groupsSet = map[string]bool{}
 	obj.Children = []*GameObject{}
-	obj.Event = event.NewEventManager[GameObjectEvent]()
+	obj.Event = newEventManager[GameObjectEvent]()
 	obj.components = map[string]Component{}
+	obj.componentOrder = []*componentEntry{}
+	obj.transform = NewTransform()
+	obj.globalTransformDirty = true
 	return obj
 }
 
@@ -45,16 +48,86 @@ type GameObject struct {
 	// to the component itself.
 	// Components should be added by reference
 	components map[string]Component
+
+	// The components in the order they are stepped
+	componentOrder []*componentEntry
+
+	// The transform relative to the parent
+	transform Transform
+
+	// Cached transform relative to the root
+	// of the tree
+	globalTransform Transform
+
+	// Whether the global transform needs
+	// to be recalculated
+	globalTransformDirty bool
+
+	// Whether the position was set. Unpositioned
+	// objects are moved to their physics body
+	positionSet bool
+
+	// Whether the object is waiting to be
+	// removed at the end of the world step
+	queuedForFree bool
+
+	// The event being dispatched while
+	// the listeners are called
+	propagation *Propagation
+}
+
+// A component stored in the game object
+type componentEntry struct {
+	name      string
+	component Component
+	// Lower priorities are stepped first
+	priority int
+}
+
+// Called every step by the game before
+// any game object is stepped
+func (g *GameObject) PreStep(delta float64) {
+	g.forEachComponent(func(c Component) {
+		c.PreStep(delta)
+	})
 }
 
 // This is called every step by the game
 func (g *GameObject) Step(delta float64) {
-	g.Event.EmitEvent(event.Event[GameObjectEvent]{
-		Name: OnGameObjectStepEvent,
-	})
+	g.reportError(event.Emit(g.Event, OnGameObjectStepTopic, struct{}{}))
 	// Call components
-	for _, c := range g.components {
+	g.forEachComponent(func(c Component) {
 		c.Step(delta)
+	})
+}
+
+// Called every step by the game after every
+// game object has stepped and before the physics
+// world is stepped
+func (g *GameObject) PhysicsStep(delta float64) {
+	g.forEachComponent(func(c Component) {
+		c.PhysicsStep(delta)
+	})
+}
+
+// Called every step by the game after
+// the physics world is stepped
+func (g *GameObject) PostStep(delta float64) {
+	g.forEachComponent(func(c Component) {
+		c.PostStep(delta)
+	})
+}
+
+// Calls the function on every component in step order.
+// Components removed while iterating are skipped
+func (g *GameObject) forEachComponent(fn func(c Component)) {
+	// Adding and removing components replaces
+	// the slice so this is safe to range over
+	for _, entry := range g.componentOrder {
+		if entry.component.GetGameObject() != g {
+			continue
+		}
+		fn(entry.component)
 	}
 }
 
@@ -69,6 +142,7 @@ func (g *GameObject) HasId() bool {
 // and give it an id if it doesn't exist
 func (g *GameObject) AddChild(o *GameObject) error {
 	o.Parent = g
+	o.invalidateGlobalTransform()
 	// If this game object is already
 	// in the world add the given game object
 	// and all its children
@@ -81,6 +155,8 @@ func (g *GameObject) AddChild(o *GameObject) error {
 				return fmt.Errorf("obj is already in the world %v", nextObj)
 			}
 			nextObj.World = g.World
+			// Posted events are emitted at the end of the step
+			nextObj.Event.SetQueue(g.World.eventQueue)
 
 			g.World.idIncrement++
 			// Set the id and world
@@ -92,15 +168,14 @@ func (g *GameObject) AddChild(o *GameObject) error {
 			}
 
 			// Call enter event
-			nextObj.Event.EmitEvent(event.Event[GameObjectEvent]{
-				Name: OnSceneEnterEvent,
-			})
+			g.reportError(event.Emit(nextObj.Event, OnSceneEnterTopic, struct{}{}))
 
 			// Call all components on enter
 			if g.IsInScene() {
-				for _, c := range nextObj.components {
+				nextObj.forEachComponent(func(c Component) {
+					g.World.indexComponent(c)
 					c.OnSceneEnter()
-				}
+				})
 			}
 		}
 	}
@@ -119,15 +194,14 @@ func (g *GameObject) RemoveChild(o *GameObject) {
 			nextObj := objIter.Next()
 
 			if g.IsInScene() {
-				for _, c := range nextObj.components {
+				nextObj.forEachComponent(func(c Component) {
 					c.OnSceneExit()
-				}
+					g.World.unindexComponent(c)
+				})
 			}
 
 			// Call exit event
-			nextObj.Event.EmitEvent(event.Event[GameObjectEvent]{
-				Name: OnSceneExitEvent,
-			})
+			g.reportError(event.Emit(nextObj.Event, OnSceneExitTopic, struct{}{}))
 
 			// Remove groups from world cache
 			for _, groupName := range nextObj.GetGroups() {
@@ -136,6 +210,7 @@ func (g *GameObject) RemoveChild(o *GameObject) {
 
 			// Remove world reference
 			nextObj.World = nil
+			nextObj.Event.SetQueue(nil)
 		}
 	}
 	// Remove the child
@@ -147,6 +222,54 @@ func (g *GameObject) RemoveChild(o *GameObject) {
 	}
 	g.Children = newChildren
 	o.Parent = nil
+	o.invalidateGlobalTransform()
+}
+
+// Queues the object to be removed from its parent at the
+// end of the current world step. Once queued, the object and
+// its children are no longer stepped. If the object is not in
+// the world, it is removed from its parent straight away
+func (g *GameObject) QueueFree() {
+	if g.World == nil {
+		g.free()
+		return
+	}
+	if g.queuedForFree {
+		return
+	}
+	g.queuedForFree = true
+	g.World.freeQueue = append(g.World.freeQueue, g)
+}
+
+// Removes the object from its parent. If called while
+// the world is stepping, such as inside a component step
+// or a collision listener, the removal is deferred to the
+// end of the step
+func (g *GameObject) Destroy() {
+	if g.World != nil && g.World.stepping {
+		g.QueueFree()
+		return
+	}
+	g.free()
+}
+
+// Returns whether the object or any of its
+// ancestors are waiting to be removed
+func (g *GameObject) IsQueuedForFree() bool {
+	for o := g; o != nil; o = o.Parent {
+		if o.queuedForFree {
+			return true
+		}
+	}
+	return false
+}
+
+// Removes the object from its parent
+func (g *GameObject) free() {
+	g.queuedForFree = false
+	if g.Parent != nil {
+		g.Parent.RemoveChild(g)
+	}
 }
 
 // Gets all the children of the given object
@@ -156,13 +279,47 @@ func (g *GameObject) GetChildren() []*GameObject {
 
 // Adds a component with the given name.
 // If the component with name already exists,
-// it is overriden
+// it is overriden. Components are stepped in
+// the order they are added
 func (g *GameObject) AddComponent(name string, component Component) {
+	g.AddComponentWithPriority(name, component, 0)
+}
+
+// Adds a component with the given name and priority.
+// Components with a lower priority are stepped first.
+// Components with the same priority are stepped in the
+// order they are added. If the component with name already
+// exists, it is removed and replaced
+func (g *GameObject) AddComponentWithPriority(name string, component Component, priority int) {
+	g.RemoveComponent(name)
 	g.components[name] = component
+
+	// Insert into the step order after all
+	// components with the same priority
+	entry := &componentEntry{
+		name:      name,
+		component: component,
+		priority:  priority,
+	}
+	newOrder := make([]*componentEntry, 0, len(g.componentOrder)+1)
+	inserted := false
+	for _, e := range g.componentOrder {
+		if !inserted && e.priority > priority {
+			newOrder = append(newOrder, entry)
+			inserted = true
+		}
+		newOrder = append(newOrder, e)
+	}
+	if !inserted {
+		newOrder = append(newOrder, entry)
+	}
+	g.componentOrder = newOrder
+
 	component.setGameObject(g)
 	component.baseOnGameObjectAttach()
 	component.OnGameObjectAttach()
 	if g.IsInScene() {
+		g.World.indexComponent(component)
 		component.OnSceneEnter()
 	}
 }
@@ -175,8 +332,17 @@ func (g *GameObject) RemoveComponent(name string) {
 		comp.baseOnGameObjectDetach()
 		if g.IsInScene() {
 			comp.OnSceneExit()
+			g.World.unindexComponent(comp)
 		}
 		delete(g.components, name)
+		// Remove from the step order
+		newOrder := make([]*componentEntry, 0, len(g.componentOrder))
+		for _, e := range g.componentOrder {
+			if e.name != name {
+				newOrder = append(newOrder, e)
+			}
+		}
+		g.componentOrder = newOrder
 		comp.setGameObject(nil)
 	}
 }
@@ -190,6 +356,26 @@ func (g *GameObject) GetComponent(name string) (c Component) {
 	return c
 }
 
+// Gets the priority of the component of the given name.
+// Returns 0 if it doesnt exist
+func (g *GameObject) GetComponentPriority(name string) int {
+	for _, e := range g.componentOrder {
+		if e.name == name {
+			return e.priority
+		}
+	}
+	return 0
+}
+
+// Gets the names of all the components in step order
+func (g *GameObject) GetComponentNames() []string {
+	names := make([]string, len(g.componentOrder))
+	for i, e := range g.componentOrder {
+		names[i] = e.name
+	}
+	return names
+}
+
 // Adds object to a given group
 func (g *GameObject) AddToGroup(groupName string) {
 	g.groupsSet[groupName] = true
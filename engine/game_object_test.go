@@ -1,6 +1,12 @@
 package engine
 
-import "testing"
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ashleycheung/go-game/event"
+	"github.com/ashleycheung/go-game/physics"
+)
 
 // Tests that an object not in a world
 // can be added as children
@@ -76,3 +82,110 @@ func TestGameObjectGroups(t *testing.T) {
 		t.Error("did not remove object")
 	}
 }
+
+type Damageable interface {
+	Damage(amount int)
+}
+
+func (c *HealthComponent) Damage(amount int) {
+	c.Health -= amount
+}
+
+func TestTypedComponentLookup(t *testing.T) {
+	obj := NewGameObject()
+	obj.AddComponent("timer1", NewTimerComponent())
+	obj.AddComponent("health", &HealthComponent{Health: 10})
+	obj.AddComponent("timer2", NewTimerComponent())
+
+	if _, ok := GetComponentOf[*PhysicsComponent](obj); ok {
+		t.Error("object has no physics component")
+	}
+	if h, ok := GetComponentOf[*HealthComponent](obj); !ok || h.Health != 10 {
+		t.Error("health component not found")
+	}
+	if len(GetComponentsOf[*TimerComponent](obj)) != 2 {
+		t.Error("should find both timers")
+	}
+	MustGetComponent[Damageable](obj).Damage(5)
+	if MustGetComponent[*HealthComponent](obj).Health != 5 {
+		t.Error("interface lookup should find the health component")
+	}
+
+	child := NewGameObject()
+	child.AddComponent("physics", NewPhysicsComponent(physics.Circle{Radius: 5}))
+	obj.AddChild(child)
+	if _, ok := FindComponentInChildren[*PhysicsComponent](obj); !ok {
+		t.Error("physics component in child not found")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("should panic when missing")
+		}
+	}()
+	MustGetComponent[*PhysicsComponent](obj)
+}
+
+func TestQueryComponents(t *testing.T) {
+	w := NewGameWorld()
+	obj1 := NewGameObject()
+	obj1.AddComponent("health", &HealthComponent{})
+	obj2 := NewGameObject()
+	obj2.AddComponent("health", &HealthComponent{})
+	obj1.AddChild(obj2)
+
+	if len(QueryComponents[*HealthComponent](w)) != 0 {
+		t.Error("components not in scene should not be found")
+	}
+	w.Scene.AddChild(obj1)
+	if len(QueryComponents[*HealthComponent](w)) != 2 {
+		t.Error("should find both health components")
+	}
+	if len(QueryComponents[Damageable](w)) != 2 {
+		t.Error("should find components by interface")
+	}
+	obj2.RemoveComponent("health")
+	if len(QueryComponents[*HealthComponent](w)) != 1 {
+		t.Error("removed component should not be found")
+	}
+	w.Scene.RemoveChild(obj1)
+	if len(QueryComponents[*HealthComponent](w)) != 0 {
+		t.Error("components removed from scene should not be found")
+	}
+}
+
+// Tests that posted events are emitted
+// at the end of the world step
+func TestGameObjectPostEvent(t *testing.T) {
+	world := NewGameWorld()
+	obj := NewGameObject()
+	world.Scene.AddChild(obj)
+
+	order := []string{}
+	damageTopic := event.NewTopic[GameObjectEvent, int]("damage")
+	event.On(obj.Event, damageTopic, func(amount int) error {
+		order = append(order, fmt.Sprint("damage ", amount))
+		return nil
+	})
+	event.On(world.Event, AfterGameStepTopic, func(struct{}) error {
+		order = append(order, "after step")
+		return nil
+	})
+	event.On(obj.Event, OnGameObjectStepTopic, func(struct{}) error {
+		return event.Post(obj.Event, damageTopic, 5)
+	})
+
+	world.Step(16)
+	if fmt.Sprint(order) != "[after step damage 5]" {
+		t.Error("expected the damage after the step", order)
+	}
+
+	// Events of removed objects
+	// are emitted by their own queue
+	world.Scene.RemoveChild(obj)
+	event.Post(obj.Event, damageTopic, 1)
+	world.Step(16)
+	if world.GetEventQueue().Len() != 0 || obj.Event.GetQueue().Len() != 1 {
+		t.Error("expected the event posted to the object queue")
+	}
+}
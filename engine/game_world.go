@@ -1,11 +1,18 @@
 package engine
 
 import (
+	"context"
 	"fmt"
+	"reflect"
+	"sync"
 	"time"
 
+	"github.com/ashleycheung/go-game/clock"
+	"github.com/ashleycheung/go-game/engine/ecs"
 	"github.com/ashleycheung/go-game/event"
+	"github.com/ashleycheung/go-game/navigation"
 	"github.com/ashleycheung/go-game/physics"
+	"github.com/ashleycheung/go-game/utils"
 )
 
 // Represents a game world
@@ -17,6 +24,15 @@ type GameWorld struct {
 	// Game world specific events
 	Event *event.EventManager[WorldEvent]
 
+	// Receives the events dispatched from
+	// objects in the world after they reach
+	// the scene
+	ObjectEvent *event.EventManager[GameObjectEvent]
+
+	// The event being dispatched while the
+	// object event listeners are called
+	propagation *Propagation
+
 	// The scene is the root
 	// of the game tree
 	Scene *GameObject
@@ -24,9 +40,37 @@ type GameWorld struct {
 	// Physics world
 	Physics *physics.World
 
+	// Entity component system storage for large
+	// numbers of simple entities. Its systems are
+	// stepped after the game objects
+	ECS *ecs.World
+
 	// Whether the world is currently running
 	running bool
 
+	// Stops the current run
+	cancelRun context.CancelFunc
+
+	// Whether the loop is paused
+	paused bool
+
+	// Multiplies the delta passed to each step
+	timeScale float64
+
+	// Steps requested with StepOnce while paused
+	pendingSteps int
+
+	// Wakes a paused loop
+	wake chan struct{}
+
+	// Statistics of the current run
+	stats LoopStats
+
+	// Guards running, cancelRun, the loop state,
+	// funcQueue and doQueue which are used by
+	// other goroutines
+	lock sync.Mutex
+
 	// Maps the group name to a set of
 	// game objects
 	groupsMap map[string]map[*GameObject]bool
@@ -35,29 +79,117 @@ type GameWorld struct {
 	// at the next tick
 	funcQueue []func()
 
+	// Functions from Do waiting for the next tick
+	doQueue []*doCommand
+
+	// Functions sent from other goroutines
+	commands chan func()
+
 	// The time the world started
 	worldStartTime time.Time
+
+	// The source of time used by the world
+	clock clock.Clock
+
+	// The milliseconds of step delta passed
+	// since the world was created
+	schedulerTime float64
+
+	// Tasks from After and Every
+	scheduledTasks []*ScheduledTask
+
+	taskIncrement int
+
+	// Coroutines which haven't finished
+	coroutines []*Coroutine
+
+	// Tweens which are playing
+	tweens []*TweenPlayer
+
+	// Actions and conditions behaviour
+	// trees can use when loaded from json
+	behaviourActions    map[string]func(ctx *BehaviourContext) NodeStatus
+	behaviourConditions map[string]func(ctx *BehaviourContext) bool
+
+	// Used for path queries. Nil if not built
+	navMesh *navigation.NavMesh
+
+	// Whether the world is in the middle of a step
+	stepping bool
+
+	// Objects to remove at the end of the step
+	freeQueue []*GameObject
+
+	// World systems run each step
+	systems []System
+
+	// The systems grouped by what can run
+	// at the same time. Nil when out of date
+	systemStages [][]utils.Job[string]
+
+	// The delta of the current step
+	// passed to the systems
+	systemDelta float64
+
+	// Runs work concurrently if not nil
+	workerPool *utils.WorkerPool
+
+	// Maps the component type to the set of
+	// components of that type in the scene
+	componentIndex map[reflect.Type]map[Component]bool
+
+	// Maps the prefab name to the prefab
+	prefabs map[string]*Prefab
+
+	// Maps the component type name used
+	// in scene files to its factory
+	componentTypes map[string]ComponentFactory
+
+	// Holds events posted to the world and its
+	// objects until the end of the step
+	eventQueue *event.Queue
 }
 
+// The max events posted to the world
+// and its objects waiting for a step
+const EventQueueLimit = 1024
+
 // Gets the world time which is basically the milliseconds
 // since the start
 func (w *GameWorld) GetWorldTime() float64 {
-	return float64(time.Since(w.worldStartTime).Milliseconds())
+	return float64(w.clock.Since(w.worldStartTime).Milliseconds())
+}
+
+// Sets the source of time used to run the world and
+// its physics world. A manual clock runs the world
+// as fast as possible with simulated time.
+// Must not be called while the world is running
+func (w *GameWorld) SetClock(c clock.Clock) {
+	w.clock = c
+	w.Physics.SetClock(c)
+}
+
+// Gets the source of time used to run the world
+func (w *GameWorld) GetClock() clock.Clock {
+	return w.clock
 }
 
-// Queues a function to be called at the start
-// of the next tick. This is needed to prevent
-// concurrent write errors
-func (w *GameWorld) QueueFunction(f func()) {
-	w.funcQueue = append(w.funcQueue, f)
+// Returns the queue events posted to the world, its
+// objects and its physics bodies wait in until the
+// end of the step
+func (w *GameWorld) GetEventQueue() *event.Queue {
+	return w.eventQueue
 }
 
-// Process the functions in the queue
-func (w *GameWorld) processFunctions() {
-	currQueue := w.funcQueue
-	w.funcQueue = []func(){}
-	for _, f := range currQueue {
-		f()
+// Removes all the objects queued to be freed.
+// Objects queued while removing are also removed
+func (w *GameWorld) processFreeQueue() {
+	for len(w.freeQueue) != 0 {
+		currQueue := w.freeQueue
+		w.freeQueue = []*GameObject{}
+		for _, obj := range currQueue {
+			obj.free()
+		}
 	}
 }
 
@@ -75,6 +207,32 @@ func (w *GameWorld) GetGroupObjects(groupName string) []*GameObject {
 	return groupObjects
 }
 
+// Registers a prefab under the given name.
+// If a prefab with the name exists, it is overriden
+func (w *GameWorld) RegisterPrefab(name string, prefab *Prefab) {
+	w.prefabs[name] = prefab
+}
+
+// Gets the prefab of the given name. Returns nil if it doesnt exist
+func (w *GameWorld) GetPrefab(name string) *Prefab {
+	return w.prefabs[name]
+}
+
+// Creates a new game object from the prefab of the
+// given name. The object is not added to the scene.
+// Overrides can be nil
+func (w *GameWorld) Instantiate(name string, overrides *PrefabOverrides) (*GameObject, error) {
+	prefab, exists := w.prefabs[name]
+	if !exists {
+		return nil, fmt.Errorf("prefab %s does not exist", name)
+	}
+	obj, err := prefab.Instantiate(overrides)
+	if err != nil {
+		return nil, fmt.Errorf("prefab %s: %w", name, err)
+	}
+	return obj, nil
+}
+
 // Internal use
 // called by the object itself to add to world
 func (w *GameWorld) addObjectToGroup(obj *GameObject, groupName string) {
@@ -99,71 +257,107 @@ func (w *GameWorld) removeObjectFromGroup(obj *GameObject, groupName string) {
 
 // Game world step
 func (w *GameWorld) Step(delta float64) {
+	w.stepping = true
+	defer func() {
+		w.stepping = false
+	}()
 	// Start step
-	w.Event.EmitEvent(event.Event[WorldEvent]{
-		Name: BeforeGameStepEvent,
-	})
+	w.ReportError(event.Emit(w.Event, BeforeGameStepTopic, struct{}{}))
 	// Process functions
 	w.processFunctions()
+	w.runScheduler(delta)
+	w.runTweens(delta)
 	// Increment scene
+	w.Scene.PreStep(delta)
 	w.Scene.Step(delta)
+	w.ECS.Step(delta)
+	w.runSystems(delta)
+	w.Scene.PhysicsStep(delta)
 	// Update physics
+	physicsComponents := w.physicsComponents()
+	w.syncTransformsToBodies(physicsComponents)
 	w.Physics.Step(delta)
+	w.syncBodiesToTransforms(physicsComponents)
+	w.Scene.PostStep(delta)
 	// Emit step finish event
-	w.Event.EmitEvent(event.Event[WorldEvent]{
-		Name: AfterGameStepEvent,
-	})
+	w.ReportError(event.Emit(w.Event, AfterGameStepTopic, struct{}{}))
+	// Emit posted events. Events posted by
+	// their listeners wait for the next step
+	w.ReportError(w.eventQueue.Flush())
+	// Remove freed objects
+	w.processFreeQueue()
 }
 
 // Runs the world at the given fps.
 // If fps is -1, it runs at highest
-// possible fps
+// possible fps. Blocks until Stop is called
 func (w *GameWorld) Run(fps int) {
-	currTime := time.Now()
-	w.worldStartTime = time.Now()
-	if w.running {
-		fmt.Println("can't run world as it is already running")
-		return
+	if err := w.RunContext(context.Background(), LoopOptions{FPS: fps}); err != nil {
+		fmt.Println(err)
 	}
+}
 
-	w.running = true
-	for w.running {
-		// Calculate delta
-		newTime := time.Now()
-		dif := newTime.Sub(currTime)
-		delta := float64(dif.Microseconds()) / 1000.0
-		currTime = newTime
-
-		startProcessTime := time.Now()
-
-		// Step world
-		w.Step(delta)
-
-		// The time it took to process the world
-		processingTime := time.Since(startProcessTime)
-
-		// Sleep and wait for next tick
-		if fps != -1 {
-			time.Sleep(
-				(time.Duration(1000.0/float64(fps)))*time.Millisecond - processingTime,
-			)
-		}
+// Marks the world as no longer running and fails
+// any Do calls still waiting for a tick
+func (w *GameWorld) finishRun() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.running = false
+	w.cancelRun()
+	w.cancelRun = nil
+	for _, cmd := range w.doQueue {
+		cmd.done <- ErrWorldStopped
 	}
+	w.doQueue = []*doCommand{}
 }
 
-// Stops the game world
+// Stops the game world. Safe to
+// call from any goroutine
 func (w *GameWorld) Stop() {
-	w.running = false
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.cancelRun != nil {
+		w.cancelRun()
+	}
+}
+
+// Returns whether the world is running. Safe
+// to call from any goroutine
+func (w *GameWorld) IsRunning() bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.running
 }
 
 // Creates a new game world
 func NewGameWorld() *GameWorld {
 	w := &GameWorld{
-		Event:     event.NewEventManager[WorldEvent](),
-		funcQueue: []func(){},
+		Event:       newEventManager[WorldEvent](),
+		ObjectEvent: newEventManager[GameObjectEvent](),
+		funcQueue:   []func(){},
+		doQueue:     []*doCommand{},
+		timeScale:   1,
+		clock:       clock.NewReal(),
+		wake:        make(chan struct{}, 1),
+		commands:    make(chan func(), commandBufferSize),
+		freeQueue:   []*GameObject{},
+		eventQueue:  event.NewQueue(EventQueueLimit),
 	}
+	w.Event.SetQueue(w.eventQueue)
+	w.ObjectEvent.SetQueue(w.eventQueue)
 	w.groupsMap = map[string]map[*GameObject]bool{}
+	w.componentIndex = map[reflect.Type]map[Component]bool{}
+	w.prefabs = map[string]*Prefab{}
+	w.componentTypes = defaultComponentTypes()
+	w.behaviourActions = map[string]func(ctx *BehaviourContext) NodeStatus{}
+	w.behaviourConditions = map[string]func(ctx *BehaviourContext) bool{}
+	w.componentTypes["behaviourTree"] = w.behaviourTreeComponentFactory
 	w.Scene = NewScene(w)
 	w.Physics = physics.NewWorld()
+	// Collision events wait for the end of the
+	// step so listeners see the synced transforms
+	w.Physics.SetEventQueue(w.eventQueue)
+	w.reportPhysicsErrors()
+	w.ECS = ecs.NewWorld()
 	return w
 }
@@ -0,0 +1,147 @@
+package engine
+
+import (
+	"github.com/ashleycheung/go-game/navigation"
+	"github.com/ashleycheung/go-game/physics"
+)
+
+// Builds a navigation mesh of the bounds minus the static
+// bodies in the physics world and uses it for path queries.
+// Obstacles carved into a previous mesh are not kept
+func (w *GameWorld) BuildNavMesh(config navigation.NavMeshConfig) *navigation.NavMesh {
+	w.navMesh = navigation.NewNavMeshFromWorld(w.Physics, config)
+	for _, c := range QueryComponents[*NavMeshObstacleComponent](w) {
+		c.carve()
+	}
+	return w.navMesh
+}
+
+// Sets the navigation mesh used for path queries
+func (w *GameWorld) SetNavMesh(m *navigation.NavMesh) {
+	w.navMesh = m
+}
+
+// Gets the navigation mesh used for path queries.
+// Nil if none has been built
+func (w *GameWorld) GetNavMesh() *navigation.NavMesh {
+	return w.navMesh
+}
+
+// Finds a path between the points on the navigation mesh.
+// Returns false if there is no path or no navigation mesh
+func (w *GameWorld) FindPath(start, end physics.Vector) ([]physics.Vector, bool) {
+	if w.navMesh == nil {
+		return nil, false
+	}
+	return w.navMesh.FindPath(start, end)
+}
+
+// Returns whether the point is on the navigation mesh
+func (w *GameWorld) IsWalkable(point physics.Vector) bool {
+	return w.navMesh != nil && w.navMesh.IsWalkable(point)
+}
+
+// Returns the nearest point on the navigation mesh
+func (w *GameWorld) NearestWalkablePoint(point physics.Vector) (physics.Vector, bool) {
+	if w.navMesh == nil {
+		return point, false
+	}
+	_, nearest, found := w.navMesh.NearestPolygon(point)
+	return nearest, found
+}
+
+// Creates a component which carves the shape out of the
+// world navigation mesh. If the shape is nil, the shape of
+// the physics component of the object is used
+func NewNavMeshObstacleComponent(shape physics.Shape) *NavMeshObstacleComponent {
+	return &NavMeshObstacleComponent{
+		Shape:         shape,
+		MoveThreshold: 1,
+	}
+}
+
+// Carves the object out of the world navigation mesh while
+// it is in the scene. Used for obstacles that move or
+// appear, such as doors and crates
+type NavMeshObstacleComponent struct {
+	BaseComponent
+
+	// The shape carved. Nil uses the physics body shape
+	Shape physics.Shape
+
+	// How far the object needs to move
+	// before the carving is updated
+	MoveThreshold float64
+
+	// The mesh carved into
+	mesh *navigation.NavMesh
+
+	obstacleId int
+
+	carvedPosition physics.Vector
+}
+
+// Returns the shape to carve. Nil if there is none
+func (n *NavMeshObstacleComponent) shape() physics.Shape {
+	if n.Shape != nil {
+		return n.Shape
+	}
+	if pC, exists := GetComponentOf[*PhysicsComponent](n.GetGameObject()); exists {
+		return pC.Body.Shape
+	}
+	return nil
+}
+
+// Carves into the current world mesh
+func (n *NavMeshObstacleComponent) carve() {
+	n.uncarve()
+	obj := n.GetGameObject()
+	shape := n.shape()
+	if obj.World.navMesh == nil || shape == nil {
+		return
+	}
+	n.mesh = obj.World.navMesh
+	n.carvedPosition = obj.GetGlobalPosition()
+	n.obstacleId = n.mesh.AddObstacle(n.carvedPosition, shape)
+}
+
+// Removes the carving
+func (n *NavMeshObstacleComponent) uncarve() {
+	if n.mesh != nil {
+		n.mesh.RemoveObstacle(n.obstacleId)
+		n.mesh = nil
+	}
+}
+
+// Makes a copy of the obstacle settings.
+// The copy isn't carved into any mesh
+func (n *NavMeshObstacleComponent) Clone() Component {
+	clone := NewNavMeshObstacleComponent(n.Shape)
+	clone.MoveThreshold = n.MoveThreshold
+	return clone
+}
+
+func (n *NavMeshObstacleComponent) OnSceneEnter() {
+	n.carve()
+}
+
+func (n *NavMeshObstacleComponent) OnSceneExit() {
+	n.uncarve()
+}
+
+// Overrides
+func (n *NavMeshObstacleComponent) Step(delta float64) {
+	obj := n.GetGameObject()
+	if n.mesh != obj.World.navMesh {
+		n.carve()
+		return
+	}
+	if n.mesh == nil {
+		return
+	}
+	pos := obj.GetGlobalPosition()
+	if pos.DistanceTo(n.carvedPosition) >= n.MoveThreshold {
+		n.carvedPosition = pos
+		n.mesh.MoveObstacle(n.obstacleId, pos)
+	}
+}
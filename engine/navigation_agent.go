@@ -0,0 +1,196 @@
+package engine
+
+import (
+	"github.com/ashleycheung/go-game/event"
+	"github.com/ashleycheung/go-game/navigation"
+	"github.com/ashleycheung/go-game/physics"
+)
+
+// Creates a navigation agent which moves at the
+// given speed in units per second
+func NewNavigationAgentComponent(pathfinder navigation.Pathfinder, speed float64) *NavigationAgentComponent {
+	return &NavigationAgentComponent{
+		Event:          newEventManager[NavigationAgentEvent](),
+		Pathfinder:     pathfinder,
+		Speed:          speed,
+		ArriveDistance: 2,
+		BlockedTime:    500,
+	}
+}
+
+// Moves the object to a target by steering the body of
+// its physics component along a path. If the agent stops
+// making progress, it finds a new path
+type NavigationAgentComponent struct {
+	BaseComponent
+
+	// Navigation events
+	Event *event.EventManager[NavigationAgentEvent]
+
+	// Finds the paths to follow. If nil, the
+	// world navigation mesh is used
+	Pathfinder navigation.Pathfinder
+
+	// The speed in units per second
+	Speed float64
+
+	// How close the agent needs to be
+	// to a point to have reached it
+	ArriveDistance float64
+
+	// The milliseconds the agent can move less than a
+	// quarter of its expected distance before finding
+	// a new path
+	BlockedTime float64
+
+	target     physics.Vector
+	navigating bool
+
+	path      []physics.Vector
+	pathIndex int
+
+	// Used to find when the agent is blocked
+	blockedTimer   float64
+	lastCheckedPos physics.Vector
+	hasCheckedPos  bool
+}
+
+// Finds a path to the target and starts following it.
+// Returns false if there is no path. If the object has no
+// body or isn't in a world yet, the target is kept and the
+// path is found on its first step, so true is returned
+func (n *NavigationAgentComponent) SetTarget(target physics.Vector) bool {
+	n.target = target
+	n.navigating = true
+	n.hasCheckedPos = false
+	n.blockedTimer = 0
+	return n.findPath()
+}
+
+// Stops moving to the target
+func (n *NavigationAgentComponent) ClearTarget() {
+	n.navigating = false
+	n.path = nil
+	n.pathIndex = 0
+	if body := n.body(); body != nil {
+		body.Velocity = physics.NewZeroVector()
+	}
+}
+
+// Returns the current target
+func (n *NavigationAgentComponent) GetTarget() physics.Vector {
+	return n.target
+}
+
+// Returns whether the agent is moving to a target
+func (n *NavigationAgentComponent) IsNavigating() bool {
+	return n.navigating
+}
+
+// Returns the points of the path left to walk
+func (n *NavigationAgentComponent) GetPath() []physics.Vector {
+	if !n.navigating {
+		return []physics.Vector{}
+	}
+	return append([]physics.Vector{}, n.path[n.pathIndex:]...)
+}
+
+// Returns the body being steered. Nil if the
+// object has no physics component
+func (n *NavigationAgentComponent) body() *physics.Body {
+	obj := n.GetGameObject()
+	if obj == nil {
+		return nil
+	}
+	pC, exists := GetComponentOf[*PhysicsComponent](obj)
+	if !exists {
+		return nil
+	}
+	return pC.Body
+}
+
+// Finds a new path from the current position. If there is
+// no body or nothing to search with yet, the path is cleared
+// so it is found on a later step
+func (n *NavigationAgentComponent) findPath() bool {
+	body := n.body()
+	if body == nil || (n.Pathfinder == nil && n.GetGameObject().World == nil) {
+		n.path = nil
+		n.pathIndex = 0
+		return true
+	}
+	var path []physics.Vector
+	var found bool
+	if n.Pathfinder != nil {
+		path, found = n.Pathfinder.FindPath(body.Position, n.target)
+	} else {
+		path, found = n.GetGameObject().World.FindPath(body.Position, n.target)
+	}
+	if !found {
+		n.ClearTarget()
+		n.GetGameObject().reportError(event.Emit(n.Event, OnNavigationFailedTopic, n.target))
+		return false
+	}
+	n.path = path
+	n.pathIndex = 0
+	return true
+}
+
+// Overrides
+func (n *NavigationAgentComponent) Step(delta float64) {
+	if !n.navigating || delta <= 0 {
+		return
+	}
+	body := n.body()
+	if body == nil {
+		return
+	}
+	// Targets set before the object was in a
+	// world are searched for on the first step
+	if n.path == nil && (!n.findPath() || n.path == nil) {
+		return
+	}
+	pos := body.Position
+
+	// Skip the points already reached
+	for n.pathIndex < len(n.path) && pos.DistanceTo(n.path[n.pathIndex]) <= n.ArriveDistance {
+		n.pathIndex++
+	}
+	if n.pathIndex == len(n.path) {
+		n.ClearTarget()
+		n.GetGameObject().reportError(event.Emit(n.Event, OnNavigationTargetReachedTopic, n.target))
+		return
+	}
+
+	// Move to the next point without overshooting it
+	toNext := n.path[n.pathIndex].Subtract(pos)
+	speed := n.Speed
+	if maxSpeed := toNext.Magnitude() / (delta / 1000); maxSpeed < speed {
+		speed = maxSpeed
+	}
+	body.Velocity = toNext.Normalize().Scale(speed)
+
+	// Check if blocked
+	if !n.hasCheckedPos {
+		n.lastCheckedPos = pos
+		n.hasCheckedPos = true
+	}
+	n.blockedTimer += delta
+	if n.blockedTimer >= n.BlockedTime {
+		expected := n.Speed * n.blockedTimer / 1000
+		if pos.DistanceTo(n.lastCheckedPos) < expected/4 && n.findPath() {
+			n.GetGameObject().reportError(event.Emit(n.Event, OnNavigationRepathTopic, n.target))
+		}
+		n.blockedTimer = 0
+		n.lastCheckedPos = pos
+	}
+}
+
+// Makes a copy of the agent settings.
+// The copy has no target
+func (n *NavigationAgentComponent) Clone() Component {
+	clone := NewNavigationAgentComponent(n.Pathfinder, n.Speed)
+	clone.ArriveDistance = n.ArriveDistance
+	clone.BlockedTime = n.BlockedTime
+	return clone
+}
@@ -0,0 +1,126 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/ashleycheung/go-game/event"
+	"github.com/ashleycheung/go-game/navigation"
+	"github.com/ashleycheung/go-game/physics"
+)
+
+// Creates a static wall in the world
+func addWall(w *GameWorld, position physics.Vector, size physics.Vector) *GameObject {
+	wall := NewGameObject()
+	wallPhysics := NewPhysicsComponent(physics.Rectangle{Size: size})
+	wallPhysics.Body.Static = true
+	wallPhysics.Body.Position = position
+	wall.AddComponent("physics", wallPhysics)
+	w.Scene.AddChild(wall)
+	return wall
+}
+
+// Steps the world until the agent reaches its target
+func navigate(w *GameWorld, agent *NavigationAgentComponent, maxSteps int) bool {
+	reached := false
+	agent.Event.AddOneTimeListener(OnNavigationTargetReachedEvent, func(e event.Event[NavigationAgentEvent]) error {
+		reached = true
+		return nil
+	})
+	for i := 0; i < maxSteps && !reached; i++ {
+		w.Step(16)
+	}
+	return reached
+}
+
+func TestNavigationAgent(t *testing.T) {
+	w := NewGameWorld()
+	bounds := physics.BBox{BottomRight: physics.Vector{X: 100, Y: 100}}
+	addWall(w, physics.Vector{X: 50, Y: 40}, physics.Vector{X: 10, Y: 80})
+	grid := navigation.NewGridFromWorld(w.Physics, bounds, 5, 5)
+
+	obj := NewGameObject()
+	obj.AddComponent("physics", NewPhysicsComponent(physics.Circle{Radius: 4}))
+	agent := NewNavigationAgentComponent(grid, 100)
+	obj.AddComponent("agent", agent)
+	obj.SetPosition(physics.Vector{X: 20, Y: 20})
+	w.Scene.AddChild(obj)
+
+	target := physics.Vector{X: 80, Y: 20}
+	if !agent.SetTarget(target) {
+		t.Fatal("expected a path")
+	}
+	if !navigate(w, agent, 500) {
+		t.Fatal("agent did not reach the target", obj.GetGlobalPosition())
+	}
+	if obj.GetGlobalPosition().DistanceTo(target) > agent.ArriveDistance {
+		t.Error("expected agent at the target", obj.GetGlobalPosition())
+	}
+	if agent.IsNavigating() {
+		t.Error("agent should stop at the target")
+	}
+
+	if agent.SetTarget(physics.Vector{X: 50, Y: 40}) {
+		t.Error("expected no path into the wall")
+	}
+}
+
+func TestNavigationAgentRepath(t *testing.T) {
+	w := NewGameWorld()
+	bounds := physics.BBox{BottomRight: physics.Vector{X: 100, Y: 100}}
+	grid := navigation.NewGrid(bounds, 5)
+
+	obj := NewGameObject()
+	obj.AddComponent("physics", NewPhysicsComponent(physics.Circle{Radius: 4}))
+	agent := NewNavigationAgentComponent(navigation.PathfinderFunc(grid.FindPathJPS), 100)
+	obj.AddComponent("agent", agent)
+	obj.SetPosition(physics.Vector{X: 20, Y: 50})
+	w.Scene.AddChild(obj)
+
+	target := physics.Vector{X: 80, Y: 50}
+	agent.SetTarget(target)
+
+	// A wall appears in the way after the path was found
+	wallPos := physics.Vector{X: 50, Y: 50}
+	wallSize := physics.Vector{X: 10, Y: 40}
+	addWall(w, wallPos, wallSize)
+	grid.BlockShape(wallPos, physics.Rectangle{Size: wallSize}, 5)
+
+	repaths := 0
+	agent.Event.AddListener(OnNavigationRepathEvent, func(e event.Event[NavigationAgentEvent]) error {
+		repaths++
+		return nil
+	})
+	if !navigate(w, agent, 1000) {
+		t.Fatal("agent did not reach the target", obj.GetGlobalPosition())
+	}
+	if repaths == 0 {
+		t.Error("expected the agent to find a new path when blocked")
+	}
+}
+
+// A target set before the object is in a world
+// is searched for once it is added
+func TestNavigationAgentTargetBeforeWorld(t *testing.T) {
+	w := NewGameWorld()
+	addWall(w, physics.Vector{X: 50, Y: 40}, physics.Vector{X: 10, Y: 80})
+	w.BuildNavMesh(navigation.NavMeshConfig{
+		Bounds:      physics.BBox{BottomRight: physics.Vector{X: 100, Y: 100}},
+		CellSize:    5,
+		AgentRadius: 5,
+	})
+
+	obj := NewGameObject()
+	obj.AddComponent("physics", NewPhysicsComponent(physics.Circle{Radius: 4}))
+	agent := NewNavigationAgentComponent(nil, 100)
+	obj.AddComponent("agent", agent)
+	obj.SetPosition(physics.Vector{X: 20, Y: 20})
+
+	target := physics.Vector{X: 80, Y: 20}
+	if !agent.SetTarget(target) || !agent.IsNavigating() {
+		t.Fatal("expected the target kept until the object is in a world")
+	}
+	w.Scene.AddChild(obj)
+	if !navigate(w, agent, 500) {
+		t.Fatal("agent did not reach the target", obj.GetGlobalPosition())
+	}
+}
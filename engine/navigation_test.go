@@ -0,0 +1,99 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/ashleycheung/go-game/navigation"
+	"github.com/ashleycheung/go-game/physics"
+)
+
+func TestWorldFindPath(t *testing.T) {
+	w := NewGameWorld()
+	if _, found := w.FindPath(physics.Vector{}, physics.Vector{X: 10}); found {
+		t.Error("expected no path without a navigation mesh")
+	}
+
+	addWall(w, physics.Vector{X: 50, Y: 40}, physics.Vector{X: 10, Y: 80})
+	w.BuildNavMesh(navigation.NavMeshConfig{
+		Bounds:      physics.BBox{BottomRight: physics.Vector{X: 100, Y: 100}},
+		CellSize:    5,
+		AgentRadius: 5,
+	})
+	if w.IsWalkable(physics.Vector{X: 50, Y: 40}) {
+		t.Error("expected the wall to not be walkable")
+	}
+	if !w.IsWalkable(physics.Vector{X: 20, Y: 20}) {
+		t.Error("expected open space to be walkable")
+	}
+	nearest, found := w.NearestWalkablePoint(physics.Vector{X: 50, Y: 40})
+	if !found || !w.IsWalkable(nearest) {
+		t.Error("expected a walkable point near the wall", nearest)
+	}
+
+	path, found := w.FindPath(physics.Vector{X: 20, Y: 20}, physics.Vector{X: 80, Y: 20})
+	if !found {
+		t.Fatal("expected a path around the wall")
+	}
+	if path[len(path)-1] != (physics.Vector{X: 80, Y: 20}) {
+		t.Error("expected the path to end at the target", path)
+	}
+	for _, p := range path {
+		if !w.IsWalkable(p) {
+			t.Error("expected walkable waypoints", path)
+		}
+	}
+}
+
+func TestNavMeshObstacle(t *testing.T) {
+	w := NewGameWorld()
+	// A corridor between two walls
+	addWall(w, physics.Vector{X: 50, Y: 20}, physics.Vector{X: 10, Y: 40})
+	addWall(w, physics.Vector{X: 50, Y: 80}, physics.Vector{X: 10, Y: 40})
+	w.BuildNavMesh(navigation.NavMeshConfig{
+		Bounds:      physics.BBox{BottomRight: physics.Vector{X: 100, Y: 100}},
+		CellSize:    5,
+		AgentRadius: 2,
+	})
+	start := physics.Vector{X: 20, Y: 50}
+	end := physics.Vector{X: 80, Y: 50}
+	if _, found := w.FindPath(start, end); !found {
+		t.Fatal("expected a path through the corridor")
+	}
+
+	// Block the corridor
+	crate := NewGameObject()
+	crate.AddComponent("physics", NewPhysicsComponent(physics.Rectangle{
+		Size: physics.Vector{X: 10, Y: 20},
+	}))
+	crate.AddComponent("obstacle", NewNavMeshObstacleComponent(nil))
+	crate.SetPosition(physics.Vector{X: 50, Y: 50})
+	w.Scene.AddChild(crate)
+	if _, found := w.FindPath(start, end); found {
+		t.Error("expected the crate to block the corridor")
+	}
+
+	// Move the crate out of the way
+	crate.SetPosition(physics.Vector{X: 20, Y: 90})
+	w.Step(16)
+	if _, found := w.FindPath(start, end); !found {
+		t.Error("expected a path once the crate moved")
+	}
+	if w.IsWalkable(crate.GetGlobalPosition()) {
+		t.Error("expected the crate to be carved where it moved")
+	}
+
+	// A copy of the carved obstacle isn't carved
+	obstacle, _ := GetComponentOf[*NavMeshObstacleComponent](crate)
+	clone, err := CloneComponent(obstacle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cloned := clone.(*NavMeshObstacleComponent); cloned.mesh != nil || cloned.obstacleId != 0 {
+		t.Error("expected the copy to not be carved")
+	}
+
+	w.Scene.RemoveChild(crate)
+	if !w.IsWalkable(physics.Vector{X: 20, Y: 90}) {
+		t.Error("expected the carving removed with the crate")
+	}
+}
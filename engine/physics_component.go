@@ -1,20 +1,41 @@
 package engine
 
 import (
+	"sort"
+
 	"github.com/ashleycheung/go-game/event"
 	"github.com/ashleycheung/go-game/physics"
 )
 
-// Manages physics
+// Manages physics.
+// The body position is kept in sync with the global
+// position of the game object. Changes to either are
+// applied to the other each step
 type PhysicsComponent struct {
 	BaseComponent
 	Body  *physics.Body
 	Event *event.EventManager[PhysicsComponentEvent]
+
+	// The global position of the object
+	// when last synced
+	syncedObjectPosition physics.Vector
+
+	// The position of the body when last synced
+	syncedBodyPosition physics.Vector
 }
 
 func (pC *PhysicsComponent) OnSceneEnter() {
 	obj := pC.GetGameObject()
 	obj.World.Physics.AddBody(pC.Body)
+	// If the object hasn't been positioned
+	// it is moved to the body. Otherwise the
+	// body is moved to the object
+	if !obj.isPositioned() {
+		obj.SetGlobalPosition(pC.Body.Position)
+	} else {
+		pC.Body.Position = obj.GetGlobalPosition()
+	}
+	pC.markSynced()
 }
 
 func (pC *PhysicsComponent) OnSceneExit() {
@@ -22,30 +43,112 @@ func (pC *PhysicsComponent) OnSceneExit() {
 	obj.World.Physics.RemoveBody(pC.Body)
 }
 
+// Applies any changes to the object position to the
+// body, or any changes to the body position to the object.
+// If both changed, the object position wins
+func (pC *PhysicsComponent) syncToBody() {
+	obj := pC.GetGameObject()
+	if obj.GetGlobalPosition() != pC.syncedObjectPosition {
+		pC.Body.Position = obj.GetGlobalPosition()
+	} else if pC.Body.Position != pC.syncedBodyPosition {
+		obj.SetGlobalPosition(pC.Body.Position)
+	}
+	pC.markSynced()
+}
+
+// Moves the object to the body position
+func (pC *PhysicsComponent) syncFromBody() {
+	pC.GetGameObject().SetGlobalPosition(pC.Body.Position)
+	pC.markSynced()
+}
+
+// Stores the current positions as synced
+func (pC *PhysicsComponent) markSynced() {
+	pC.syncedObjectPosition = pC.GetGameObject().GetGlobalPosition()
+	pC.syncedBodyPosition = pC.Body.Position
+}
+
+// Gets all the physics components in the world
+// sorted so parents come before their children
+func (w *GameWorld) physicsComponents() []*PhysicsComponent {
+	components := []*PhysicsComponent{}
+	depths := map[*PhysicsComponent]int{}
+	for _, b := range w.Physics.Bodies() {
+		pC, ok := b.Metadata.(*PhysicsComponent)
+		if !ok || pC.GetGameObject() == nil {
+			continue
+		}
+		depth := 0
+		for o := pC.GetGameObject(); o.Parent != nil; o = o.Parent {
+			depth++
+		}
+		depths[pC] = depth
+		components = append(components, pC)
+	}
+	sort.SliceStable(components, func(i, j int) bool {
+		return depths[components[i]] < depths[components[j]]
+	})
+	return components
+}
+
+// Syncs the object positions to the physics bodies.
+// Called before the physics step with the
+// components from physicsComponents
+func (w *GameWorld) syncTransformsToBodies(components []*PhysicsComponent) {
+	for _, pC := range components {
+		pC.syncToBody()
+	}
+}
+
+// Syncs the physics bodies to the object positions.
+// Called after the physics step. Parents are synced
+// first so children with bodies stay where their
+// body is
+func (w *GameWorld) syncBodiesToTransforms(components []*PhysicsComponent) {
+	for _, pC := range components {
+		// Removed by a listener during the physics step
+		if pC.GetGameObject() == nil || w.Physics.GetBody(pC.Body.Id) != pC.Body {
+			continue
+		}
+		pC.syncFromBody()
+	}
+}
+
 // Creates new physics component
 func NewPhysicsComponent(shape physics.Shape) *PhysicsComponent {
 	component := &PhysicsComponent{
 		Body:  physics.NewBody(shape),
-		Event: event.NewEventManager[PhysicsComponentEvent](),
+		Event: newEventManager[PhysicsComponentEvent](),
 	}
 	// Stores the component in the body
 	// as metadata
 	component.Body.Metadata = component
 
 	// Call collision
-	component.Body.GetEvent().AddListener(
-		physics.BodyCollideEvent,
-		func(e event.Event[physics.PhysicsBodyEvent]) error {
-			component.Event.EmitEvent(event.Event[PhysicsComponentEvent]{
-				Name: OnPhysicsComponentCollideEvent,
+	event.On(
+		component.Body.GetEvent(),
+		physics.BodyCollideTopic,
+		func(data physics.BodyCollideEventData) error {
+			return event.Emit(component.Event, OnPhysicsComponentCollideTopic, OnPhysicsComponentCollideData{
 				// Add the target body's physic component
 				// which is stored in the meta data
-				Data: OnPhysicsComponentCollideData{
-					Target: e.Data.(physics.BodyCollideEventData).TargetBody.Metadata.(*PhysicsComponent),
-				},
+				Target: data.TargetBody.Metadata.(*PhysicsComponent),
 			})
-			return nil
 		},
 	)
 	return component
 }
+
+// Makes a copy of the component with a new body
+// that has the same properties
+func (pC *PhysicsComponent) Clone() Component {
+	clone := NewPhysicsComponent(pC.Body.Shape)
+	clone.Body.Mass = pC.Body.Mass
+	clone.Body.Position = pC.Body.Position
+	clone.Body.Velocity = pC.Body.Velocity
+	clone.Body.Acceleration = pC.Body.Acceleration
+	clone.Body.DragCoefficient = pC.Body.DragCoefficient
+	clone.Body.Sensor = pC.Body.Sensor
+	clone.Body.Static = pC.Body.Static
+	return clone
+}
@@ -3,6 +3,7 @@ package engine
 import (
 	"testing"
 
+	"github.com/ashleycheung/go-game/event"
 	"github.com/ashleycheung/go-game/physics"
 )
 
@@ -23,3 +24,37 @@ func TestPhysicsObject(t *testing.T) {
 		t.Error("body not removed")
 	}
 }
+
+// Collision listeners run after the bodies are
+// synced so a position they set isn't overwritten
+func TestPhysicsComponentCollideSetPosition(t *testing.T) {
+	w := NewGameWorld()
+	o1 := NewGameObject()
+	c1 := NewPhysicsComponent(physics.Circle{Radius: 5})
+	c1.Body.Sensor = true
+	o1.AddComponent("physics", c1)
+	o2 := NewGameObject()
+	c2 := NewPhysicsComponent(physics.Circle{Radius: 5})
+	c2.Body.Static = true
+	o2.AddComponent("physics", c2)
+	w.Scene.AddChild(o1)
+	w.Scene.AddChild(o2)
+
+	collisions := 0
+	event.On(c1.Event, OnPhysicsComponentCollideTopic, func(data OnPhysicsComponentCollideData) error {
+		collisions++
+		o1.SetPosition(physics.Vector{X: 100, Y: 100})
+		return nil
+	})
+	w.Step(16)
+	if collisions != 1 {
+		t.Fatalf("expected 1 collision, got %d", collisions)
+	}
+	if o1.GetPosition() != (physics.Vector{X: 100, Y: 100}) {
+		t.Errorf("position set by the listener was overwritten, got %v", o1.GetPosition())
+	}
+	w.Step(16)
+	if collisions != 1 {
+		t.Errorf("expected the moved object to stop colliding, got %d collisions", collisions)
+	}
+}
@@ -0,0 +1,307 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Creates a new empty prefab
+func NewPrefab() *Prefab {
+	return &Prefab{
+		Components: []PrefabComponent{},
+		Groups:     []string{},
+		Children:   []*Prefab{},
+	}
+}
+
+// A named template of a game object subtree.
+// Instantiating a prefab creates a new game object
+// with copies of the template components
+type Prefab struct {
+	// The components of the root object
+	// in the order they are added
+	Components []PrefabComponent
+
+	// The groups the root object is a part of
+	Groups []string
+
+	// Templates of the children of the root object
+	Children []*Prefab
+}
+
+// A component stored in a prefab
+type PrefabComponent struct {
+	// Name the component is added under
+	Name string
+	// The template component. This is never
+	// attached to a game object, only copies are
+	Component Component
+	// The step priority of the component
+	Priority int
+}
+
+// Overrides applied to the root object
+// when instantiating a prefab
+type PrefabOverrides struct {
+	// Replaces the template component with the same name,
+	// keeping its priority. Components with a new name are
+	// added. Each object gets its own copy so the overrides
+	// can be used for many objects
+	Components map[string]Component
+
+	// Extra groups to add the object to
+	Groups []string
+
+	// Called with the root object once it is created
+	Configure func(obj *GameObject)
+}
+
+// Adds a template component to the prefab
+func (p *Prefab) AddComponent(name string, component Component) {
+	p.AddComponentWithPriority(name, component, 0)
+}
+
+// Adds a template component with the given step priority
+func (p *Prefab) AddComponentWithPriority(name string, component Component, priority int) {
+	p.Components = append(p.Components, PrefabComponent{
+		Name:      name,
+		Component: component,
+		Priority:  priority,
+	})
+}
+
+// Adds a group to the prefab
+func (p *Prefab) AddToGroup(groupName string) {
+	p.Groups = append(p.Groups, groupName)
+}
+
+// Adds a child template to the prefab
+func (p *Prefab) AddChild(child *Prefab) {
+	p.Children = append(p.Children, child)
+}
+
+// Creates a new game object subtree from the prefab.
+// The object is not added to the world. Overrides
+// can be nil
+func (p *Prefab) Instantiate(overrides *PrefabOverrides) (*GameObject, error) {
+	obj := NewGameObject()
+	templateNames := map[string]bool{}
+	for _, pC := range p.Components {
+		templateNames[pC.Name] = true
+		template := pC.Component
+		// Overriden components are added instead
+		if overrides != nil {
+			if override, exists := overrides.Components[pC.Name]; exists {
+				template = override
+			}
+		}
+		c, err := CloneComponent(template)
+		if err != nil {
+			return nil, fmt.Errorf("prefab component %s: %w", pC.Name, err)
+		}
+		obj.AddComponentWithPriority(pC.Name, c, pC.Priority)
+	}
+	for _, group := range p.Groups {
+		obj.AddToGroup(group)
+	}
+
+	// Create children
+	for _, childPrefab := range p.Children {
+		child, err := childPrefab.Instantiate(nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := obj.AddChild(child); err != nil {
+			return nil, err
+		}
+	}
+
+	// Apply overrides
+	if overrides != nil {
+		// Sort names so overrides are added in a consistent order
+		names := make([]string, 0, len(overrides.Components))
+		for name := range overrides.Components {
+			// Already added in place of the template
+			if !templateNames[name] {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			c, err := CloneComponent(overrides.Components[name])
+			if err != nil {
+				return nil, fmt.Errorf("prefab override component %s: %w", name, err)
+			}
+			obj.AddComponent(name, c)
+		}
+		for _, group := range overrides.Groups {
+			obj.AddToGroup(group)
+		}
+		if overrides.Configure != nil {
+			overrides.Configure(obj)
+		}
+	}
+	return obj, nil
+}
+
+// Creates a prefab from an existing game object and
+// its children. The components are copied so changing
+// the object afterwards does not change the prefab
+func NewPrefabFromObject(obj *GameObject) (*Prefab, error) {
+	p := NewPrefab()
+
+	for _, entry := range obj.componentOrder {
+		c, err := CloneComponent(entry.component)
+		if err != nil {
+			return nil, fmt.Errorf("component %s: %w", entry.name, err)
+		}
+		p.AddComponentWithPriority(entry.name, c, entry.priority)
+	}
+
+	groups := obj.GetGroups()
+	sort.Strings(groups)
+	for _, group := range groups {
+		p.AddToGroup(group)
+	}
+
+	for _, child := range obj.Children {
+		childPrefab, err := NewPrefabFromObject(child)
+		if err != nil {
+			return nil, err
+		}
+		p.AddChild(childPrefab)
+	}
+	return p, nil
+}
+
+// Makes a copy of a component which is not attached
+// to any game object. If the component implements
+// CloneableComponent, its Clone method is used. Otherwise
+// the fields are copied, with maps and slices duplicated.
+// Components holding pointers or channels, such as an event
+// manager, return an error as the copies would share them
+func CloneComponent(c Component) (Component, error) {
+	if cloneable, ok := c.(CloneableComponent); ok {
+		return cloneable.Clone(), nil
+	}
+
+	v := reflect.ValueOf(c)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can't copy component of type %T", c)
+	}
+	for i := 0; i < v.Elem().NumField(); i++ {
+		field := v.Elem().Type().Field(i)
+		// The base is reset on the copy
+		if field.Type == reflect.TypeOf(BaseComponent{}) {
+			continue
+		}
+		if err := checkCopyable(v.Elem().Field(i), field.IsExported()); err != nil {
+			return nil, fmt.Errorf(
+				"can't copy field %s of component of type %T, implement CloneableComponent: %w",
+				field.Name, c, err,
+			)
+		}
+	}
+	clone := reflect.New(v.Elem().Type())
+	clone.Elem().Set(copyValue(v.Elem()))
+
+	cloneComp := clone.Interface().(Component)
+	cloneComp.resetBase()
+	return cloneComp, nil
+}
+
+// Returned when copying a component would share state
+var ErrComponentNotCopyable = errors.New("value would be shared between copies")
+
+// Returns an error if copyValue would share the value
+// between copies. Unexported values are never duplicated
+func checkCopyable(v reflect.Value, exported bool) error {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		if !v.IsNil() {
+			return ErrComponentNotCopyable
+		}
+	case reflect.Interface:
+		if !v.IsNil() {
+			// Values in interfaces are copied as they are
+			return checkCopyable(v.Elem(), false)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			exportedField := exported && v.Type().Field(i).IsExported()
+			if err := checkCopyable(v.Field(i), exportedField); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		if !exported {
+			return ErrComponentNotCopyable
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			if err := checkCopyable(iter.Key(), exported); err != nil {
+				return err
+			}
+			if err := checkCopyable(iter.Value(), exported); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		if !exported {
+			return ErrComponentNotCopyable
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := checkCopyable(v.Index(i), exported); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Copies a value, duplicating any maps and
+// slices so they are not shared
+func copyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			// Unexported fields can't be set so they are
+			// left as a shallow copy
+			if !out.Field(i).CanSet() {
+				continue
+			}
+			out.Field(i).Set(copyValue(v.Field(i)))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), copyValue(iter.Value()))
+		}
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(copyValue(v.Index(i)))
+		}
+		return out
+	}
+	return v
+}
@@ -0,0 +1,205 @@
+package engine
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ashleycheung/go-game/event"
+	"github.com/ashleycheung/go-game/physics"
+)
+
+type HealthComponent struct {
+	BaseComponent
+	Health    int
+	Modifiers []int
+}
+
+func TestPrefabInstantiate(t *testing.T) {
+	w := NewGameWorld()
+
+	prefab := NewPrefab()
+	prefab.AddComponent("health", &HealthComponent{Health: 10, Modifiers: []int{1}})
+	prefab.AddComponent("physics", NewPhysicsComponent(physics.Circle{Radius: 5}))
+	prefab.AddToGroup("enemy")
+	prefab.AddChild(NewPrefab())
+	w.RegisterPrefab("enemy", prefab)
+
+	obj1, err := w.Instantiate("enemy", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj2, err := w.Instantiate("enemy", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Scene.AddChild(obj1)
+	w.Scene.AddChild(obj2)
+
+	h1 := obj1.GetComponent("health").(*HealthComponent)
+	h2 := obj2.GetComponent("health").(*HealthComponent)
+	if h1 == h2 {
+		t.Fatal("components should be copied")
+	}
+	h1.Health = 5
+	h1.Modifiers[0] = 5
+	if h2.Health != 10 || h2.Modifiers[0] != 1 {
+		t.Error("components should not share state")
+	}
+	if h1.GetGameObject() != obj1 {
+		t.Error("component not attached to the instance")
+	}
+	if len(obj1.GetChildren()) != 1 {
+		t.Error("children not instantiated")
+	}
+	if len(w.GetGroupObjects("enemy")) != 2 {
+		t.Error("groups not copied")
+	}
+	if len(w.Physics.Bodies()) != 2 {
+		t.Error("physics bodies should not be shared")
+	}
+}
+
+func TestPrefabOverrides(t *testing.T) {
+	w := NewGameWorld()
+	prefab := NewPrefab()
+	prefab.AddComponent("health", &HealthComponent{Health: 10})
+	w.RegisterPrefab("enemy", prefab)
+
+	configured := false
+	obj, err := w.Instantiate("enemy", &PrefabOverrides{
+		Components: map[string]Component{
+			"health": &HealthComponent{Health: 50},
+		},
+		Groups: []string{"boss"},
+		Configure: func(obj *GameObject) {
+			configured = true
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.GetComponent("health").(*HealthComponent).Health != 50 {
+		t.Error("component not overriden")
+	}
+	if !obj.InGroup("boss") {
+		t.Error("group not added")
+	}
+	if !configured {
+		t.Error("configure not called")
+	}
+
+	if _, err := w.Instantiate("missing", nil); err == nil {
+		t.Error("missing prefab should error")
+	}
+}
+
+func TestPrefabOverridesReused(t *testing.T) {
+	w := NewGameWorld()
+	prefab := NewPrefab()
+	prefab.AddComponentWithPriority("health", &HealthComponent{Health: 10}, 5)
+	prefab.AddComponentWithPriority("timer", NewTimerComponent(), 10)
+	w.RegisterPrefab("enemy", prefab)
+
+	overrides := &PrefabOverrides{
+		Components: map[string]Component{
+			"health": &HealthComponent{Health: 50},
+			"shield": &HealthComponent{Health: 20},
+		},
+	}
+	obj1, err := w.Instantiate("enemy", overrides)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj2, err := w.Instantiate("enemy", overrides)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"health", "shield"} {
+		c1 := obj1.GetComponent(name)
+		c2 := obj2.GetComponent(name)
+		if c1 == c2 || c1 == overrides.Components[name] {
+			t.Error("expected each object to get its own override", name)
+		}
+		if c1.GetGameObject() != obj1 || c2.GetGameObject() != obj2 {
+			t.Error("expected the overrides attached to their objects", name)
+		}
+	}
+	if obj1.GetComponentPriority("health") != 5 {
+		t.Error("expected the override to keep the template priority", obj1.GetComponentPriority("health"))
+	}
+	if strings.Join(obj1.GetComponentNames(), ",") != "shield,health,timer" {
+		t.Error("expected the template step order kept", obj1.GetComponentNames())
+	}
+}
+
+func TestLoadPrefabs(t *testing.T) {
+	w := NewGameWorld()
+	err := w.LoadPrefabs(strings.NewReader(`{
+		"prefabs": {
+			"enemy": {
+				"groups": ["enemy"],
+				"components": [
+					{
+						"name": "physics",
+						"type": "physics",
+						"data": {"shape": {"type": "circle", "radius": 5}, "mass": 3}
+					},
+					{"name": "timer", "type": "timer", "data": {"duration": 100}}
+				],
+				"children": [{"groups": ["weapon"]}]
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, err := w.Instantiate("enemy", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pC := obj.GetComponent("physics").(*PhysicsComponent)
+	if pC.Body.Mass != 3 || pC.Body.Shape.(physics.Circle).Radius != 5 {
+		t.Error("physics component not loaded")
+	}
+	if obj.GetComponent("timer").(*TimerComponent).Duration != 100 {
+		t.Error("timer component not loaded")
+	}
+	if !obj.GetChildren()[0].InGroup("weapon") {
+		t.Error("children not loaded")
+	}
+
+	err = w.LoadPrefabs(strings.NewReader(`{
+		"prefabs": {"bad": {"components": [{"name": "x", "type": "unknown"}]}}
+	}`))
+	if err == nil {
+		t.Error("unknown component type should error")
+	}
+}
+
+type EventfulComponent struct {
+	BaseComponent
+	Event *event.EventManager[string]
+}
+
+// Components which would share an event manager
+// between copies can't be copied without Clone
+func TestCloneComponentShared(t *testing.T) {
+	c := &EventfulComponent{Event: event.NewEventManager[string]()}
+	if _, err := CloneComponent(c); !errors.Is(err, ErrComponentNotCopyable) {
+		t.Error("expected the component to not be copyable", err)
+	}
+
+	prefab := NewPrefab()
+	prefab.AddComponent("eventful", c)
+	w := NewGameWorld()
+	w.RegisterPrefab("eventful", prefab)
+	if _, err := w.Instantiate("eventful", nil); !errors.Is(err, ErrComponentNotCopyable) {
+		t.Error("expected instantiating to fail", err)
+	}
+
+	// Nothing is shared without the manager
+	if _, err := CloneComponent(&EventfulComponent{}); err != nil {
+		t.Error(err)
+	}
+}
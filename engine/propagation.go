@@ -0,0 +1,220 @@
+package engine
+
+import (
+	"errors"
+
+	"github.com/ashleycheung/go-game/event"
+)
+
+// Where a dispatched event is in its trip
+// through the tree. Phases are flags so they
+// can be combined to choose where events go
+type Phase int
+
+const (
+	// From the scene down to the parent of the target
+	CapturePhase Phase = 1 << iota
+	// On the object the event was dispatched on
+	TargetPhase
+	// From the parent of the target up to the scene
+	BubblePhase
+	// Down through every descendant of the target
+	BroadcastPhase
+	// On the object event bus of the world
+	WorldPhase
+
+	// Every phase
+	AllPhases = CapturePhase | TargetPhase | BubblePhase | BroadcastPhase | WorldPhase
+)
+
+// An event dispatched through the tree.
+// Returned by GetPropagation while the
+// listeners of an object are called
+type Propagation struct {
+	// The event dispatched
+	Event event.Event[GameObjectEvent]
+
+	// The object the event was dispatched on
+	Target *GameObject
+
+	// The object whose listeners are being called.
+	// Nil on the world bus
+	Current *GameObject
+
+	// The phase being called
+	Phase Phase
+
+	stopped bool
+}
+
+// Stops the event reaching any more objects. The
+// remaining listeners of the current object are called
+func (p *Propagation) Stop() {
+	p.stopped = true
+}
+
+// Returns whether Stop was called
+func (p *Propagation) IsStopped() bool {
+	return p.stopped
+}
+
+// Listens to a dispatched topic with the propagation
+type PropagationListener[P any] func(p *Propagation, payload P) error
+
+// Emits the event on the object then sends it through the
+// tree in the phases given. The target phase is always
+// called. Capture listeners are called from the scene down,
+// bubble listeners from the parent up and broadcast listeners
+// on every descendant. If the object is in a world, the
+// event ends on the world bus unless stopped. Listeners
+// added with AddListener are called in every phase
+// they are reached. Returns the listener errors joined
+func (g *GameObject) DispatchEvent(e event.Event[GameObjectEvent], phases Phase) error {
+	p := &Propagation{Event: e, Target: g}
+	// The tree is read up front so listeners
+	// moving objects don't change the path
+	ancestors := []*GameObject{}
+	for a := g.Parent; a != nil; a = a.Parent {
+		ancestors = append(ancestors, a)
+	}
+	descendants := []*GameObject{}
+	if phases&BroadcastPhase != 0 {
+		for _, c := range g.Children {
+			iter := newBFSIterator(c)
+			for iter.HasNext() {
+				descendants = append(descendants, iter.Next())
+			}
+		}
+	}
+	world := g.World
+
+	errs := []error{}
+	visit := func(obj *GameObject, phase Phase) bool {
+		p.Current = obj
+		p.Phase = phase
+		errs = append(errs, obj.emitPropagation(p))
+		return !p.stopped
+	}
+
+	if phases&CapturePhase != 0 {
+		for i := len(ancestors) - 1; i >= 0; i-- {
+			if !visit(ancestors[i], CapturePhase) {
+				return errors.Join(errs...)
+			}
+		}
+	}
+	if !visit(g, TargetPhase) {
+		return errors.Join(errs...)
+	}
+	if phases&BubblePhase != 0 {
+		for _, a := range ancestors {
+			if !visit(a, BubblePhase) {
+				return errors.Join(errs...)
+			}
+		}
+	}
+	for _, d := range descendants {
+		if !visit(d, BroadcastPhase) {
+			return errors.Join(errs...)
+		}
+	}
+	if world != nil && phases&WorldPhase != 0 {
+		p.Current = nil
+		p.Phase = WorldPhase
+		previous := world.propagation
+		world.propagation = p
+		errs = append(errs, world.ObjectEvent.EmitEvent(e))
+		world.propagation = previous
+	}
+	return errors.Join(errs...)
+}
+
+// Emits the propagated event on the object
+func (g *GameObject) emitPropagation(p *Propagation) error {
+	// Restored so dispatching from
+	// a listener doesn't lose the outer event
+	previous := g.propagation
+	g.propagation = p
+	defer func() {
+		g.propagation = previous
+	}()
+	return g.Event.EmitEvent(p.Event)
+}
+
+// Returns the event being dispatched while the listeners
+// of the object are called. Nil if the event was emitted
+// on the object without being dispatched
+func (g *GameObject) GetPropagation() *Propagation {
+	return g.propagation
+}
+
+// Returns the event being dispatched while
+// the listeners of the world bus are called
+func (w *GameWorld) GetPropagation() *Propagation {
+	return w.propagation
+}
+
+// Dispatches the topic with the payload from the object
+func Dispatch[P any](g *GameObject, topic event.Topic[GameObjectEvent, P], payload P, phases Phase) error {
+	return g.DispatchEvent(topic.Event(payload), phases)
+}
+
+// Adds a listener of the dispatched topic on the object which
+// is only called in the given phases. Emits which weren't
+// dispatched are treated as the target phase
+func OnDispatch[P any](
+	g *GameObject,
+	topic event.Topic[GameObjectEvent, P],
+	phases Phase,
+	listener PropagationListener[P],
+) func() {
+	return event.On(g.Event, topic, func(payload P) error {
+		p := g.propagation
+		// Emitted by a listener of another dispatched event
+		if p == nil || p.Event.Name != topic.Name {
+			p = &Propagation{
+				Event:   topic.Event(payload),
+				Target:  g,
+				Current: g,
+				Phase:   TargetPhase,
+			}
+		}
+		if p.Phase&phases == 0 {
+			return nil
+		}
+		return listener(p, payload)
+	})
+}
+
+// Adds a listener on the world bus of the topic
+// dispatched from any object in the world. Events
+// emitted straight on the bus have no target
+func OnWorldDispatch[P any](
+	w *GameWorld,
+	topic event.Topic[GameObjectEvent, P],
+	listener PropagationListener[P],
+) func() {
+	return event.On(w.ObjectEvent, topic, func(payload P) error {
+		p := w.propagation
+		if p == nil || p.Event.Name != topic.Name {
+			p = &Propagation{Event: topic.Event(payload), Phase: WorldPhase}
+		}
+		return listener(p, payload)
+	})
+}
+
+// Adds a listener on the world bus of the topic dispatched
+// from objects in the group when it is dispatched
+func OnGroupDispatch[P any](
+	w *GameWorld,
+	group string,
+	topic event.Topic[GameObjectEvent, P],
+	listener PropagationListener[P],
+) func() {
+	return OnWorldDispatch(w, topic, func(p *Propagation, payload P) error {
+		if p.Target == nil || !p.Target.InGroup(group) {
+			return nil
+		}
+		return listener(p, payload)
+	})
+}
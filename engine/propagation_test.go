@@ -0,0 +1,149 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ashleycheung/go-game/event"
+)
+
+var damageTopic = event.NewTopic[GameObjectEvent, int]("damage")
+
+// Creates a ship with a turret added to the world
+func newShipWithTurret(world *GameWorld) (ship, turret *GameObject) {
+	ship = NewGameObject()
+	ship.AddToGroup("ship")
+	turret = NewGameObject()
+	turret.AddToGroup("turret")
+	ship.AddChild(turret)
+	world.Scene.AddChild(ship)
+	return ship, turret
+}
+
+func TestDispatchBubbles(t *testing.T) {
+	world := NewGameWorld()
+	ship, turret := newShipWithTurret(world)
+
+	order := []string{}
+	shipHealth := 100
+	OnDispatch(ship, damageTopic, BubblePhase, func(p *Propagation, amount int) error {
+		if p.Target != turret || p.Current != ship {
+			t.Error("expected the turret as the target", p.Target)
+		}
+		shipHealth -= amount
+		order = append(order, "ship")
+		return nil
+	})
+	// Plain listeners are called too
+	event.On(turret.Event, damageTopic, func(amount int) error {
+		order = append(order, "turret")
+		return nil
+	})
+	score := 0
+	OnWorldDispatch(world, damageTopic, func(p *Propagation, amount int) error {
+		if p.Phase != WorldPhase || p.Target != turret {
+			t.Error("expected the world phase", p.Phase)
+		}
+		score += amount
+		order = append(order, "world")
+		return nil
+	})
+
+	if err := Dispatch(turret, damageTopic, 10, BubblePhase|WorldPhase); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(order) != "[turret ship world]" {
+		t.Error("expected the event to bubble to the world", order)
+	}
+	if shipHealth != 90 || score != 10 {
+		t.Error("expected the ship and score to see the damage", shipHealth, score)
+	}
+
+	// Only sent to the target
+	Dispatch(turret, damageTopic, 10, TargetPhase)
+	if shipHealth != 90 || score != 10 {
+		t.Error("expected the event not to propagate", shipHealth, score)
+	}
+}
+
+func TestDispatchCaptureAndStop(t *testing.T) {
+	world := NewGameWorld()
+	ship, turret := newShipWithTurret(world)
+
+	order := []string{}
+	record := func(name string) PropagationListener[int] {
+		return func(p *Propagation, amount int) error {
+			order = append(order, fmt.Sprint(name, " ", p.Phase))
+			return nil
+		}
+	}
+	OnDispatch(world.Scene, damageTopic, CapturePhase, record("scene"))
+	OnDispatch(ship, damageTopic, CapturePhase|BubblePhase, record("ship"))
+	OnDispatch(turret, damageTopic, TargetPhase, record("turret"))
+	OnDispatch(world.Scene, damageTopic, BubblePhase, record("scene"))
+
+	Dispatch(turret, damageTopic, 1, AllPhases)
+	expected := fmt.Sprint([]string{
+		fmt.Sprint("scene ", CapturePhase),
+		fmt.Sprint("ship ", CapturePhase),
+		fmt.Sprint("turret ", TargetPhase),
+		fmt.Sprint("ship ", BubblePhase),
+		fmt.Sprint("scene ", BubblePhase),
+	})
+	if fmt.Sprint(order) != expected {
+		t.Error("expected capture then target then bubble", order)
+	}
+
+	// Shields stop the damage reaching the turret
+	order = []string{}
+	OnDispatch(ship, damageTopic, CapturePhase, func(p *Propagation, amount int) error {
+		p.Stop()
+		return errors.New("shielded")
+	})
+	err := Dispatch(turret, damageTopic, 1, AllPhases)
+	if len(order) != 2 {
+		t.Error("expected the event stopped at the ship", order)
+	}
+	if err == nil {
+		t.Error("expected the listener error")
+	}
+}
+
+func TestDispatchBroadcast(t *testing.T) {
+	world := NewGameWorld()
+	ship, turret := newShipWithTurret(world)
+	barrel := NewGameObject()
+	turret.AddChild(barrel)
+
+	reached := []*GameObject{}
+	for _, obj := range []*GameObject{ship, turret, barrel} {
+		obj := obj
+		OnDispatch(obj, OnSceneExitTopic, BroadcastPhase, func(p *Propagation, payload struct{}) error {
+			reached = append(reached, obj)
+			return nil
+		})
+	}
+	ship.DispatchEvent(OnSceneExitTopic.Event(struct{}{}), BroadcastPhase)
+	if len(reached) != 2 || reached[0] != turret || reached[1] != barrel {
+		t.Error("expected the descendants in order", reached)
+	}
+}
+
+func TestGroupDispatch(t *testing.T) {
+	world := NewGameWorld()
+	ship, turret := newShipWithTurret(world)
+
+	turretDamage := 0
+	OnGroupDispatch(world, "turret", damageTopic, func(p *Propagation, amount int) error {
+		turretDamage += amount
+		return nil
+	})
+	Dispatch(turret, damageTopic, 3, WorldPhase)
+	Dispatch(ship, damageTopic, 5, WorldPhase)
+	// Not dispatched so has no target
+	event.Emit(world.ObjectEvent, damageTopic, 7)
+	if turretDamage != 3 {
+		t.Error("expected only the turret damage", turretDamage)
+	}
+}
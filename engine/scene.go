@@ -3,6 +3,7 @@ package engine
 func NewScene(world *GameWorld) *GameObject {
 	obj := NewGameObject()
 	obj.World = world
+	obj.Event.SetQueue(world.eventQueue)
 	obj.AddComponent("scene", &SceneComponent{
 		obj: obj,
 	})
@@ -12,16 +13,79 @@ func NewScene(world *GameWorld) *GameObject {
 type SceneComponent struct {
 	BaseComponent
 	obj *GameObject
+
+	// The objects in the scene when the
+	// world step started. Used by every
+	// step phase
+	stepObjects []*GameObject
+}
+
+// Takes a snapshot of the objects in the scene
+// and runs the pre step of each of them. The
+// snapshot is used until the post step so objects
+// added during a step are first stepped on the next step
+func (s *SceneComponent) PreStep(delta float64) {
+	s.stepObjects = s.sceneObjects()
+	s.forEachStepObject(func(obj *GameObject) {
+		obj.PreStep(delta)
+	})
 }
 
 // Iterates through the children
 // and steps them
 func (s *SceneComponent) Step(delta float64) {
+	s.forEachStepObject(func(obj *GameObject) {
+		obj.Step(delta)
+	})
+}
+
+// Runs the physics step of each child
+func (s *SceneComponent) PhysicsStep(delta float64) {
+	s.forEachStepObject(func(obj *GameObject) {
+		obj.PhysicsStep(delta)
+	})
+}
+
+// Runs the post step of each child
+// and clears the snapshot
+func (s *SceneComponent) PostStep(delta float64) {
+	s.forEachStepObject(func(obj *GameObject) {
+		obj.PostStep(delta)
+	})
+	s.stepObjects = nil
+}
+
+// Calls the function on every object in the snapshot.
+// If there is no snapshot, the current objects are used
+func (s *SceneComponent) forEachStepObject(fn func(obj *GameObject)) {
+	objects := s.stepObjects
+	if objects == nil {
+		objects = s.sceneObjects()
+	}
+	world := s.obj.World
+	for _, obj := range objects {
+		// Skip objects removed or freed
+		// earlier in the step
+		if obj.World != world {
+			continue
+		}
+		if len(world.freeQueue) != 0 && obj.IsQueuedForFree() {
+			continue
+		}
+		fn(obj)
+	}
+}
+
+// Returns all the objects currently
+// in the scene excluding the scene itself
+func (s *SceneComponent) sceneObjects() []*GameObject {
+	objects := []*GameObject{}
 	objIter := newBFSIterator(s.obj)
 	for objIter.HasNext() {
 		nextObj := objIter.Next()
 		if nextObj != s.obj {
-			nextObj.Step(delta)
+			objects = append(objects, nextObj)
 		}
 	}
+	return objects
 }
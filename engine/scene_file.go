@@ -0,0 +1,184 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ashleycheung/go-game/physics"
+)
+
+// Creates a component from the data
+// given in a scene file
+type ComponentFactory func(data json.RawMessage) (Component, error)
+
+// The format of a scene file
+type sceneFile struct {
+	// Maps the prefab name to the prefab
+	Prefabs map[string]prefabData `json:"prefabs"`
+}
+
+type prefabData struct {
+	Groups     []string        `json:"groups"`
+	Components []componentData `json:"components"`
+	Children   []prefabData    `json:"children"`
+}
+
+type componentData struct {
+	// Name the component is added under
+	Name string `json:"name"`
+	// The registered component type
+	Type string `json:"type"`
+	// Passed to the component factory
+	Data json.RawMessage `json:"data"`
+}
+
+// Registers a component type so it can be
+// used in scene files
+func (w *GameWorld) RegisterComponentType(typeName string, factory ComponentFactory) {
+	w.componentTypes[typeName] = factory
+}
+
+// Loads all the prefabs from a json scene file
+// and registers them in the world. For example
+//
+//	{
+//	  "prefabs": {
+//	    "enemy": {
+//	      "groups": ["enemy"],
+//	      "components": [
+//	        {
+//	          "name": "physics",
+//	          "type": "physics",
+//	          "data": {"shape": {"type": "circle", "radius": 5}}
+//	        }
+//	      ],
+//	      "children": []
+//	    }
+//	  }
+//	}
+func (w *GameWorld) LoadPrefabs(r io.Reader) error {
+	file := sceneFile{}
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return fmt.Errorf("load prefabs: %w", err)
+	}
+	// Build all prefabs before registering so
+	// a bad file registers nothing
+	prefabs := map[string]*Prefab{}
+	for name, data := range file.Prefabs {
+		prefab, err := w.buildPrefab(data)
+		if err != nil {
+			return fmt.Errorf("load prefabs: prefab %s: %w", name, err)
+		}
+		prefabs[name] = prefab
+	}
+	for name, prefab := range prefabs {
+		w.RegisterPrefab(name, prefab)
+	}
+	return nil
+}
+
+// Converts the scene file data into a prefab
+func (w *GameWorld) buildPrefab(data prefabData) (*Prefab, error) {
+	prefab := NewPrefab()
+	for _, group := range data.Groups {
+		prefab.AddToGroup(group)
+	}
+	for _, cData := range data.Components {
+		factory, exists := w.componentTypes[cData.Type]
+		if !exists {
+			return nil, fmt.Errorf("unknown component type %s", cData.Type)
+		}
+		c, err := factory(cData.Data)
+		if err != nil {
+			return nil, fmt.Errorf("component %s: %w", cData.Name, err)
+		}
+		prefab.AddComponent(cData.Name, c)
+	}
+	for _, childData := range data.Children {
+		child, err := w.buildPrefab(childData)
+		if err != nil {
+			return nil, err
+		}
+		prefab.AddChild(child)
+	}
+	return prefab, nil
+}
+
+// Unmarshals the component data.
+// Missing data is treated as empty
+func unmarshalComponentData(data json.RawMessage, v any) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+// The component types every world supports
+func defaultComponentTypes() map[string]ComponentFactory {
+	return map[string]ComponentFactory{
+		"physics": physicsComponentFactory,
+		"timer":   timerComponentFactory,
+	}
+}
+
+// The scene file data of a physics component
+type physicsComponentData struct {
+	Shape struct {
+		Type   physics.ShapeType `json:"type"`
+		Radius float64           `json:"radius"`
+		Size   physics.Vector    `json:"size"`
+	} `json:"shape"`
+	Mass            *float64       `json:"mass"`
+	Position        physics.Vector `json:"position"`
+	Velocity        physics.Vector `json:"velocity"`
+	DragCoefficient *float64       `json:"dragCoefficient"`
+	Sensor          bool           `json:"sensor"`
+	Static          bool           `json:"static"`
+}
+
+func physicsComponentFactory(data json.RawMessage) (Component, error) {
+	pData := physicsComponentData{}
+	if err := unmarshalComponentData(data, &pData); err != nil {
+		return nil, err
+	}
+	var shape physics.Shape
+	switch pData.Shape.Type {
+	case physics.CircleType:
+		shape = physics.Circle{Radius: pData.Shape.Radius}
+	case physics.RectangleType:
+		shape = physics.Rectangle{Size: pData.Shape.Size}
+	default:
+		return nil, fmt.Errorf("unsupported shape type %s", pData.Shape.Type)
+	}
+	c := NewPhysicsComponent(shape)
+	// Only override the defaults if given
+	if pData.Mass != nil {
+		c.Body.Mass = *pData.Mass
+	}
+	if pData.DragCoefficient != nil {
+		c.Body.DragCoefficient = *pData.DragCoefficient
+	}
+	c.Body.Position = pData.Position
+	c.Body.Velocity = pData.Velocity
+	c.Body.Sensor = pData.Sensor
+	c.Body.Static = pData.Static
+	return c, nil
+}
+
+// The scene file data of a timer component
+type timerComponentData struct {
+	Duration float64 `json:"duration"`
+	Loop     bool    `json:"loop"`
+}
+
+func timerComponentFactory(data json.RawMessage) (Component, error) {
+	tData := timerComponentData{}
+	if err := unmarshalComponentData(data, &tData); err != nil {
+		return nil, err
+	}
+	c := NewTimerComponent()
+	c.Duration = tData.Duration
+	c.Loop = tData.Loop
+	return c, nil
+}
@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/ashleycheung/go-game/event"
+	"github.com/ashleycheung/go-game/physics"
+)
+
+// Counts the number of steps and runs
+// the given function each step
+type StepCounterComponent struct {
+	BaseComponent
+	Steps  int
+	OnStep func()
+}
+
+func (c *StepCounterComponent) Step(delta float64) {
+	c.Steps++
+	if c.OnStep != nil {
+		c.OnStep()
+	}
+}
+
+// Objects added during a step are first stepped on the next step
+func TestSceneAddChildDuringStep(t *testing.T) {
+	w := NewGameWorld()
+	spawned := &StepCounterComponent{}
+
+	spawner := NewGameObject()
+	spawner.AddComponent("counter", &StepCounterComponent{
+		OnStep: func() {
+			if spawned.GetGameObject() != nil {
+				return
+			}
+			obj := NewGameObject()
+			obj.AddComponent("counter", spawned)
+			w.Scene.AddChild(obj)
+		},
+	})
+	w.Scene.AddChild(spawner)
+
+	w.Step(10)
+	if spawned.Steps != 0 {
+		t.Error("object added during step should not be stepped")
+	}
+	w.Step(10)
+	if spawned.Steps != 1 {
+		t.Error("object should be stepped on the next step")
+	}
+}
+
+// Objects removed during a step are not stepped
+func TestSceneRemoveChildDuringStep(t *testing.T) {
+	w := NewGameWorld()
+	victim := NewGameObject()
+	victimCounter := &StepCounterComponent{}
+	victim.AddComponent("counter", victimCounter)
+
+	killer := NewGameObject()
+	killer.AddComponent("counter", &StepCounterComponent{
+		OnStep: func() {
+			if victim.Parent != nil {
+				w.Scene.RemoveChild(victim)
+			}
+		},
+	})
+	w.Scene.AddChild(killer)
+	w.Scene.AddChild(victim)
+
+	w.Step(10)
+	if victimCounter.Steps != 0 {
+		t.Error("removed object should not be stepped")
+	}
+}
+
+func TestQueueFree(t *testing.T) {
+	w := NewGameWorld()
+	enemy := NewGameObject()
+	enemyCounter := &StepCounterComponent{}
+	enemy.AddComponent("counter", enemyCounter)
+	weapon := NewGameObject()
+	weaponCounter := &StepCounterComponent{}
+	weapon.AddComponent("counter", weaponCounter)
+	enemy.AddChild(weapon)
+
+	killer := NewGameObject()
+	killer.AddComponent("counter", &StepCounterComponent{
+		OnStep: func() {
+			enemy.Destroy()
+		},
+	})
+	w.Scene.AddChild(killer)
+	w.Scene.AddChild(enemy)
+
+	w.Step(10)
+	if enemyCounter.Steps != 0 || weaponCounter.Steps != 0 {
+		t.Error("freed objects should not be stepped")
+	}
+	if enemy.IsInScene() || weapon.IsInScene() {
+		t.Error("object should be removed at the end of the step")
+	}
+	if len(w.Scene.GetChildren()) != 1 {
+		t.Error("object not removed from parent")
+	}
+}
+
+// Destroying an object in a collision
+// listener is deferred until the step ends
+func TestDestroyInCollision(t *testing.T) {
+	w := NewGameWorld()
+	enemy := NewGameObject()
+	enemy.AddComponent("physics", NewPhysicsComponent(physics.Circle{Radius: 5}))
+	bullet := NewGameObject()
+	bulletPhysics := NewPhysicsComponent(physics.Circle{Radius: 5})
+	bullet.AddComponent("physics", bulletPhysics)
+	bulletPhysics.Event.AddListener(
+		OnPhysicsComponentCollideEvent,
+		func(e event.Event[PhysicsComponentEvent]) error {
+			if !e.Data.(OnPhysicsComponentCollideData).Target.GetGameObject().IsInScene() {
+				t.Error("object should still be in the scene during the step")
+			}
+			e.Data.(OnPhysicsComponentCollideData).Target.GetGameObject().Destroy()
+			return nil
+		},
+	)
+	w.Scene.AddChild(enemy)
+	w.Scene.AddChild(bullet)
+
+	w.Step(10)
+	if enemy.IsInScene() {
+		t.Error("enemy should be destroyed")
+	}
+	if len(w.Physics.Bodies()) != 1 {
+		t.Error("enemy body should be removed")
+	}
+
+	// Outside of a step, destroy is immediate
+	bullet.Destroy()
+	if bullet.IsInScene() {
+		t.Error("bullet should be destroyed")
+	}
+}
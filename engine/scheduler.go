@@ -0,0 +1,190 @@
+package engine
+
+import (
+	"sort"
+
+	"github.com/ashleycheung/go-game/event"
+)
+
+// A function scheduled on the world to be called
+// after a delay, and optionally repeated
+type ScheduledTask struct {
+	world *GameWorld
+
+	fn func()
+
+	// The scheduler time the task is next due
+	dueTime float64
+
+	// The time between repeats in milliseconds.
+	// Only used if repeat is true
+	interval float64
+
+	repeat bool
+
+	// The time left when paused
+	pausedTimeLeft float64
+
+	paused bool
+
+	cancelled bool
+
+	// Orders tasks due at the same time
+	order int
+
+	// Removes the scene exit listener
+	// of the object the task is bound to
+	unbind func()
+}
+
+// Calls the function once after the given milliseconds.
+// Tasks are called at the start of a step in the order
+// they are due. Delays follow the world time scale and stop
+// while the world is paused
+func (w *GameWorld) After(ms float64, fn func()) *ScheduledTask {
+	return w.schedule(ms, fn, false)
+}
+
+// Calls the function every given milliseconds until
+// cancelled. The function is called at most once a step,
+// so an interval shorter than a step is called every step
+func (w *GameWorld) Every(ms float64, fn func()) *ScheduledTask {
+	return w.schedule(ms, fn, true)
+}
+
+func (w *GameWorld) schedule(ms float64, fn func(), repeat bool) *ScheduledTask {
+	w.taskIncrement++
+	t := &ScheduledTask{
+		world:    w,
+		fn:       fn,
+		dueTime:  w.schedulerTime + ms,
+		interval: ms,
+		repeat:   repeat,
+		order:    w.taskIncrement,
+	}
+	w.scheduledTasks = append(w.scheduledTasks, t)
+	return t
+}
+
+// Cancels the task when the object leaves the scene.
+// If the object isn't in this world, the task is
+// cancelled straight away
+func (t *ScheduledTask) BindTo(obj *GameObject) *ScheduledTask {
+	if t.cancelled {
+		return t
+	}
+	if obj.World != t.world {
+		t.Cancel()
+		return t
+	}
+	if t.unbind != nil {
+		t.unbind()
+	}
+	t.unbind = event.OnOnce(
+		obj.Event,
+		OnSceneExitTopic,
+		func(struct{}) error {
+			t.unbind = nil
+			t.Cancel()
+			return nil
+		},
+	)
+	return t
+}
+
+// Stops the task from being called again
+func (t *ScheduledTask) Cancel() {
+	t.cancelled = true
+	if t.unbind != nil {
+		t.unbind()
+		t.unbind = nil
+	}
+}
+
+// Returns whether the task was cancelled
+// or has finished
+func (t *ScheduledTask) IsCancelled() bool {
+	return t.cancelled
+}
+
+// Stops the delay counting down until resumed
+func (t *ScheduledTask) Pause() {
+	if t.paused || t.cancelled {
+		return
+	}
+	t.pausedTimeLeft = t.GetTimeLeft()
+	t.paused = true
+}
+
+// Continues counting down a paused task
+func (t *ScheduledTask) Resume() {
+	if !t.paused {
+		return
+	}
+	t.paused = false
+	t.dueTime = t.world.schedulerTime + t.pausedTimeLeft
+}
+
+// Returns whether the task is paused
+func (t *ScheduledTask) IsPaused() bool {
+	return t.paused
+}
+
+// Returns the milliseconds until the task is next called
+func (t *ScheduledTask) GetTimeLeft() float64 {
+	if t.paused {
+		return t.pausedTimeLeft
+	}
+	timeLeft := t.dueTime - t.world.schedulerTime
+	if timeLeft < 0 {
+		return 0
+	}
+	return timeLeft
+}
+
+// Moves the scheduler time forward and calls
+// the tasks and coroutines that are due
+func (w *GameWorld) runScheduler(delta float64) {
+	w.schedulerTime += delta
+
+	// Coroutines started by the tasks wait for the next step
+	coroutines := w.coroutines
+
+	// Tasks scheduled while running wait for the next step
+	due := []*ScheduledTask{}
+	remaining := []*ScheduledTask{}
+	for _, t := range w.scheduledTasks {
+		if t.cancelled {
+			continue
+		}
+		remaining = append(remaining, t)
+		if !t.paused && t.dueTime <= w.schedulerTime {
+			due = append(due, t)
+		}
+	}
+	w.scheduledTasks = remaining
+	sort.SliceStable(due, func(i, j int) bool {
+		if due[i].dueTime != due[j].dueTime {
+			return due[i].dueTime < due[j].dueTime
+		}
+		return due[i].order < due[j].order
+	})
+
+	for _, t := range due {
+		// Cancelled by an earlier task
+		if t.cancelled || t.paused {
+			continue
+		}
+		if t.repeat {
+			t.dueTime += t.interval
+			if t.dueTime <= w.schedulerTime {
+				t.dueTime = w.schedulerTime + t.interval
+			}
+		} else {
+			t.Cancel()
+		}
+		t.fn()
+	}
+
+	w.runCoroutines(coroutines)
+}
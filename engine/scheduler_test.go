@@ -0,0 +1,69 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAfterAndEvery(t *testing.T) {
+	w := NewGameWorld()
+	calls := []string{}
+	w.After(30, func() { calls = append(calls, "after") })
+	every := w.Every(20, func() { calls = append(calls, "every") })
+
+	for i := 0; i < 5; i++ {
+		w.Step(10)
+	}
+	// every at 20 and 40, after at 30
+	expected := []string{"every", "after", "every"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Error("expected", expected, "got", calls)
+	}
+
+	every.Cancel()
+	w.Step(100)
+	if len(calls) != 3 {
+		t.Error("cancelled task should not be called", calls)
+	}
+}
+
+func TestScheduledTaskPause(t *testing.T) {
+	w := NewGameWorld()
+	called := false
+	task := w.After(50, func() { called = true })
+	w.Step(20)
+	task.Pause()
+	w.Step(100)
+	if called || task.GetTimeLeft() != 30 {
+		t.Error("paused task should not count down", task.GetTimeLeft())
+	}
+	task.Resume()
+	w.Step(20)
+	if called {
+		t.Error("task called early")
+	}
+	w.Step(10)
+	if !called || !task.IsCancelled() {
+		t.Error("task should be called once done")
+	}
+}
+
+func TestScheduledTaskBindTo(t *testing.T) {
+	w := NewGameWorld()
+	obj := NewGameObject()
+	w.Scene.AddChild(obj)
+
+	count := 0
+	task := w.Every(10, func() { count++ }).BindTo(obj)
+	w.Step(10)
+	w.Scene.RemoveChild(obj)
+	w.Step(10)
+	if count != 1 || !task.IsCancelled() {
+		t.Error("task should be cancelled when the object leaves", count)
+	}
+
+	// Objects not in the world cancel straight away
+	if !w.After(10, func() {}).BindTo(NewGameObject()).IsCancelled() {
+		t.Error("expected the task to be cancelled")
+	}
+}
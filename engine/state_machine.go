@@ -0,0 +1,349 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ashleycheung/go-game/event"
+)
+
+// Used as the from state of a transition
+// which can happen from any state
+const AnyState = "*"
+
+// The default number of transitions kept in the history
+const DefaultStateHistorySize = 32
+
+// Returned when a state doesn't exist
+var ErrUnknownState = errors.New("unknown state")
+
+// Returned when the guards of a transition fail
+var ErrTransitionBlocked = errors.New("transition blocked by guard")
+
+// A state of a state machine
+type State struct {
+	// Unique name of the state
+	Name string
+
+	// The name of the parent state. While in this state
+	// the parent is also active. Empty if top level
+	Parent string
+
+	// The substate entered when this state is
+	// transitioned to. Empty if it has no substates
+	Initial string
+
+	// Called when the state is entered
+	OnEnter func(sm *StateMachineComponent)
+
+	// Called when the state is exited
+	OnExit func(sm *StateMachineComponent)
+
+	// Called every step while the state is active.
+	// Parents step before their substates
+	OnStep func(sm *StateMachineComponent, delta float64)
+}
+
+// A transition between two states
+type Transition struct {
+	// The state to transition from. Also matches any
+	// substates of it. AnyState matches every state
+	From string
+
+	// The state to transition to
+	To string
+
+	// Returns whether the transition can happen.
+	// A nil guard always passes
+	Guard func(sm *StateMachineComponent) bool
+}
+
+// A transition which happened
+type StateTransition struct {
+	From string
+	To   string
+	// The time of the state machine in milliseconds
+	Time float64
+}
+
+// Creates a new state machine component
+func NewStateMachineComponent() *StateMachineComponent {
+	return &StateMachineComponent{
+		Event:       newEventManager[StateMachineEvent](),
+		states:      map[string]*State{},
+		transitions: []Transition{},
+		history:     []StateTransition{},
+		HistorySize: DefaultStateHistorySize,
+	}
+}
+
+// Runs logic based on the current state. Transitions
+// added with AddTransition are checked at the start of
+// each step and the first one whose guard passes happens.
+// States can have substates, in which case the parent
+// state is active while any of its substates are
+type StateMachineComponent struct {
+	BaseComponent
+
+	// State machine events
+	Event *event.EventManager[StateMachineEvent]
+
+	// The max number of transitions kept in the history
+	HistorySize int
+
+	states map[string]*State
+
+	// The first state entered
+	initial string
+
+	// Checked in order each step
+	transitions []Transition
+
+	// The active leaf state. Empty if not started
+	current string
+
+	// The total time stepped
+	time float64
+
+	// The time the current state was entered
+	stateStartTime float64
+
+	history []StateTransition
+}
+
+// Adds a state. A state with the same name is replaced.
+// The first state added is the initial state
+func (sm *StateMachineComponent) AddState(s State) *StateMachineComponent {
+	state := s
+	sm.states[s.Name] = &state
+	if sm.initial == "" {
+		sm.initial = s.Name
+	}
+	return sm
+}
+
+// Adds a transition which happens automatically
+// when its guard passes
+func (sm *StateMachineComponent) AddTransition(t Transition) *StateMachineComponent {
+	sm.transitions = append(sm.transitions, t)
+	return sm
+}
+
+// Sets the state entered on the first step
+func (sm *StateMachineComponent) SetInitialState(name string) {
+	sm.initial = name
+}
+
+// Returns the active leaf state.
+// Empty if the state machine hasn't started
+func (sm *StateMachineComponent) GetState() string {
+	return sm.current
+}
+
+// Returns whether the state or one of its
+// substates is active
+func (sm *StateMachineComponent) IsInState(name string) bool {
+	for _, s := range sm.activeStates() {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the milliseconds since the current state was entered
+func (sm *StateMachineComponent) GetTimeInState() float64 {
+	return sm.time - sm.stateStartTime
+}
+
+// Returns the recent transitions, oldest first
+func (sm *StateMachineComponent) GetHistory() []StateTransition {
+	return append([]StateTransition{}, sm.history...)
+}
+
+// Returns the state and its ancestors, root first
+func (sm *StateMachineComponent) ancestry(name string) []*State {
+	chain := []*State{}
+	for name != "" {
+		s, exists := sm.states[name]
+		if !exists {
+			break
+		}
+		chain = append([]*State{s}, chain...)
+		name = s.Parent
+		// Stop cycles
+		if len(chain) > len(sm.states) {
+			break
+		}
+	}
+	return chain
+}
+
+// Returns the active states, root first
+func (sm *StateMachineComponent) activeStates() []*State {
+	return sm.ancestry(sm.current)
+}
+
+// Returns whether the transition applies to the current state
+func (sm *StateMachineComponent) matchesCurrent(t Transition) bool {
+	return t.From == AnyState || sm.IsInState(t.From)
+}
+
+// Returns whether a transition to the state is allowed.
+// If transitions to the state are registered from the
+// current state, one of their guards must pass
+func (sm *StateMachineComponent) CanTransitionTo(name string) bool {
+	if _, exists := sm.states[name]; !exists {
+		return false
+	}
+	registered := false
+	for _, t := range sm.transitions {
+		if t.To != name || !sm.matchesCurrent(t) {
+			continue
+		}
+		registered = true
+		if t.Guard == nil || t.Guard(sm) {
+			return true
+		}
+	}
+	return !registered
+}
+
+// Transitions to the state. If the state has substates,
+// its initial substate is entered. Returns ErrUnknownState
+// if the state doesn't exist and ErrTransitionBlocked if
+// the guards of registered transitions fail
+func (sm *StateMachineComponent) TransitionTo(name string) error {
+	if _, exists := sm.states[name]; !exists {
+		return fmt.Errorf("%w: %s", ErrUnknownState, name)
+	}
+	if !sm.CanTransitionTo(name) {
+		return fmt.Errorf("%w: %s to %s", ErrTransitionBlocked, sm.current, name)
+	}
+	sm.changeState(name)
+	return nil
+}
+
+// Transitions to the state ignoring any guards
+func (sm *StateMachineComponent) ForceState(name string) error {
+	if _, exists := sm.states[name]; !exists {
+		return fmt.Errorf("%w: %s", ErrUnknownState, name)
+	}
+	sm.changeState(name)
+	return nil
+}
+
+// Exits the active states that aren't ancestors
+// of the new state and enters the new ones
+func (sm *StateMachineComponent) changeState(name string) {
+	// Descend to the initial leaf state
+	for depth := 0; depth < len(sm.states); depth++ {
+		s := sm.states[name]
+		if s.Initial == "" || s.Initial == name {
+			break
+		}
+		if _, exists := sm.states[s.Initial]; !exists {
+			break
+		}
+		name = s.Initial
+	}
+
+	from := sm.current
+	oldChain := sm.activeStates()
+	newChain := sm.ancestry(name)
+
+	// Find how many states are shared. Transitioning to
+	// the same state exits and enters it again
+	shared := 0
+	for shared < len(oldChain) && shared < len(newChain) &&
+		oldChain[shared] == newChain[shared] &&
+		oldChain[shared].Name != name {
+		shared++
+	}
+
+	// Exit from the leaf up
+	for i := len(oldChain) - 1; i >= shared; i-- {
+		s := oldChain[i]
+		if s.OnExit != nil {
+			s.OnExit(sm)
+		}
+		sm.GetGameObject().reportError(event.Emit(sm.Event, OnStateExitTopic, s.Name))
+	}
+
+	sm.current = name
+	sm.stateStartTime = sm.time
+	transition := StateTransition{From: from, To: name, Time: sm.time}
+	sm.history = append(sm.history, transition)
+	if sm.HistorySize > 0 && len(sm.history) > sm.HistorySize {
+		sm.history = append([]StateTransition{}, sm.history[len(sm.history)-sm.HistorySize:]...)
+	}
+	sm.GetGameObject().reportError(event.Emit(sm.Event, OnStateTransitionTopic, transition))
+
+	// Enter from the root down
+	for i := shared; i < len(newChain); i++ {
+		s := newChain[i]
+		if s.OnEnter != nil {
+			s.OnEnter(sm)
+		}
+		sm.GetGameObject().reportError(event.Emit(sm.Event, OnStateEnterTopic, s.Name))
+		// Entering changed the state
+		if sm.current != name {
+			return
+		}
+	}
+}
+
+// Overrides
+func (sm *StateMachineComponent) Step(delta float64) {
+	sm.time += delta
+	if sm.current == "" {
+		if _, exists := sm.states[sm.initial]; !exists {
+			return
+		}
+		sm.changeState(sm.initial)
+	}
+
+	// Take the first transition that passes
+	for _, t := range sm.transitions {
+		if !sm.matchesCurrent(t) {
+			continue
+		}
+		// Otherwise it would enter the state again every step
+		if t.From == AnyState && sm.IsInState(t.To) {
+			continue
+		}
+		if _, exists := sm.states[t.To]; !exists {
+			continue
+		}
+		if t.Guard == nil || t.Guard(sm) {
+			sm.changeState(t.To)
+			break
+		}
+	}
+
+	// Step the active states. Stops if
+	// a state transitions while stepping
+	current := sm.current
+	for _, s := range sm.activeStates() {
+		if s.OnStep != nil {
+			s.OnStep(sm, delta)
+		}
+		if sm.current != current {
+			return
+		}
+	}
+}
+
+// Makes a copy of the states and transitions.
+// The copy starts in the initial state
+func (sm *StateMachineComponent) Clone() Component {
+	clone := NewStateMachineComponent()
+	clone.HistorySize = sm.HistorySize
+	clone.initial = sm.initial
+	for name, s := range sm.states {
+		state := *s
+		clone.states[name] = &state
+	}
+	clone.transitions = append(clone.transitions, sm.transitions...)
+	return clone
+}
@@ -0,0 +1,117 @@
+package engine
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ashleycheung/go-game/event"
+)
+
+// Records the enter and exit calls of states
+func recordState(calls *[]string, s State) State {
+	name := s.Name
+	s.OnEnter = func(sm *StateMachineComponent) {
+		*calls = append(*calls, "enter "+name)
+	}
+	s.OnExit = func(sm *StateMachineComponent) {
+		*calls = append(*calls, "exit "+name)
+	}
+	return s
+}
+
+func TestStateMachine(t *testing.T) {
+	calls := []string{}
+	health := 100
+	sm := NewStateMachineComponent()
+	sm.AddState(recordState(&calls, State{Name: "alive", Initial: "idle"}))
+	sm.AddState(recordState(&calls, State{Name: "idle", Parent: "alive"}))
+	sm.AddState(recordState(&calls, State{Name: "chase", Parent: "alive"}))
+	sm.AddState(recordState(&calls, State{Name: "dead"}))
+	sm.SetInitialState("alive")
+	sm.AddTransition(Transition{
+		From:  AnyState,
+		To:    "dead",
+		Guard: func(sm *StateMachineComponent) bool { return health <= 0 },
+	})
+
+	obj := NewGameObject()
+	obj.AddComponent("ai", sm)
+	obj.Step(10)
+	if sm.GetState() != "idle" || !sm.IsInState("alive") {
+		t.Error("expected to start in idle", sm.GetState())
+	}
+
+	transitions := []StateTransition{}
+	sm.Event.AddListener(OnStateTransitionEvent, func(e event.Event[StateMachineEvent]) error {
+		transitions = append(transitions, e.Data.(StateTransition))
+		return nil
+	})
+
+	// Moving between substates keeps the parent
+	calls = []string{}
+	if err := sm.TransitionTo("chase"); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(calls, []string{"exit idle", "enter chase"}) {
+		t.Error("unexpected calls", calls)
+	}
+
+	calls = []string{}
+	health = 0
+	obj.Step(10)
+	if sm.GetState() != "dead" {
+		t.Error("expected the guarded transition to dead", sm.GetState())
+	}
+	if !reflect.DeepEqual(calls, []string{"exit chase", "exit alive", "enter dead"}) {
+		t.Error("unexpected calls", calls)
+	}
+	if len(transitions) != 2 || transitions[1].From != "chase" || transitions[1].To != "dead" {
+		t.Error("expected transition events", transitions)
+	}
+
+	history := sm.GetHistory()
+	if len(history) != 3 || history[0].To != "idle" || history[2].To != "dead" {
+		t.Error("unexpected history", history)
+	}
+}
+
+func TestStateMachineGuards(t *testing.T) {
+	stepped := []string{}
+	canAttack := false
+	sm := NewStateMachineComponent()
+	sm.AddState(State{Name: "idle"})
+	sm.AddState(State{Name: "attack", OnStep: func(sm *StateMachineComponent, delta float64) {
+		stepped = append(stepped, "attack")
+	}})
+	sm.AddTransition(Transition{
+		From:  "idle",
+		To:    "attack",
+		Guard: func(sm *StateMachineComponent) bool { return canAttack },
+	})
+	sm.Step(10)
+
+	if err := sm.TransitionTo("attack"); !errors.Is(err, ErrTransitionBlocked) {
+		t.Error("expected the guard to block", err)
+	}
+	if err := sm.TransitionTo("missing"); !errors.Is(err, ErrUnknownState) {
+		t.Error("expected unknown state", err)
+	}
+
+	canAttack = true
+	sm.Step(10)
+	if sm.GetState() != "attack" || len(stepped) != 1 {
+		t.Error("expected to transition and step attack", sm.GetState(), stepped)
+	}
+	sm.Step(15)
+	if sm.GetTimeInState() != 15 {
+		t.Error("expected 15ms in state", sm.GetTimeInState())
+	}
+
+	// Clones start again in the initial state
+	clone := sm.Clone().(*StateMachineComponent)
+	clone.Step(10)
+	if clone.GetState() != "attack" || len(clone.GetHistory()) != 2 {
+		t.Error("clone should start from the initial state", clone.GetHistory())
+	}
+}
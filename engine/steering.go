@@ -0,0 +1,126 @@
+package engine
+
+import "github.com/ashleycheung/go-game/steering"
+
+// Creates a steering component with the max speed in units
+// per second and max force in units per second squared
+func NewSteeringComponent(maxSpeed, maxForce float64) *SteeringComponent {
+	return &SteeringComponent{
+		MaxSpeed:     maxSpeed,
+		MaxForce:     maxForce,
+		behaviours:   []steering.WeightedBehaviour{},
+		behaviourIds: []int{},
+	}
+}
+
+// Sets the velocity of the body of the physics component
+// each step from a weighted blend of steering behaviours.
+// If avoidance is set, the blended velocity is then
+// adjusted to avoid nearby bodies
+type SteeringComponent struct {
+	BaseComponent
+
+	// The max speed in units per second
+	MaxSpeed float64
+
+	// The max change in velocity in units per second squared
+	MaxForce float64
+
+	// The radius kept clear of other bodies.
+	// 0 uses the radius of the body's shape
+	Radius float64
+
+	// Avoids nearby bodies. Nil doesn't avoid
+	Avoidance *steering.Avoidance
+
+	// Finds nearby bodies. Nil uses the
+	// quad tree of the physics world
+	Neighbours steering.NeighbourFinder
+
+	behaviours []steering.WeightedBehaviour
+
+	// The id of each behaviour
+	behaviourIds []int
+
+	behaviourIncrement int
+}
+
+// Adds a behaviour with the weight it contributes.
+// Returns a function which removes it
+func (s *SteeringComponent) AddBehaviour(b steering.Behaviour, weight float64) func() {
+	s.behaviourIncrement++
+	id := s.behaviourIncrement
+	s.behaviours = append(s.behaviours, steering.WeightedBehaviour{
+		Behaviour: b,
+		Weight:    weight,
+	})
+	s.behaviourIds = append(s.behaviourIds, id)
+	return func() {
+		for i, behaviourId := range s.behaviourIds {
+			if behaviourId == id {
+				s.behaviours = append(s.behaviours[:i:i], s.behaviours[i+1:]...)
+				s.behaviourIds = append(s.behaviourIds[:i:i], s.behaviourIds[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Removes every behaviour
+func (s *SteeringComponent) ClearBehaviours() {
+	s.behaviours = []steering.WeightedBehaviour{}
+	s.behaviourIds = []int{}
+}
+
+// Returns the behaviours with their weights
+func (s *SteeringComponent) GetBehaviours() []steering.WeightedBehaviour {
+	return append([]steering.WeightedBehaviour{}, s.behaviours...)
+}
+
+// Sets the avoidance of nearby bodies. Nil disables it
+func (s *SteeringComponent) SetAvoidance(a *steering.Avoidance) *SteeringComponent {
+	s.Avoidance = a
+	return s
+}
+
+// Overrides
+func (s *SteeringComponent) Step(delta float64) {
+	obj := s.GetGameObject()
+	pC, exists := GetComponentOf[*PhysicsComponent](obj)
+	if !exists || delta <= 0 {
+		return
+	}
+	agent := steering.NewAgent(pC.Body, s.MaxSpeed, s.MaxForce)
+	if s.Radius > 0 {
+		agent.Radius = s.Radius
+	}
+	ctx := &steering.Context{
+		Agent:      agent,
+		Delta:      delta,
+		Neighbours: s.Neighbours,
+	}
+	if ctx.Neighbours == nil && obj.World != nil {
+		ctx.Neighbours = steering.NewWorldNeighbours(obj.World.Physics)
+	}
+
+	velocity := agent.ApplyForce(steering.Blend(ctx, s.behaviours), delta)
+	if s.Avoidance != nil {
+		velocity = s.Avoidance.Avoid(ctx, velocity)
+	}
+	pC.Body.Velocity = velocity
+}
+
+// Makes a copy with copies of the behaviours
+func (s *SteeringComponent) Clone() Component {
+	clone := NewSteeringComponent(s.MaxSpeed, s.MaxForce)
+	clone.Radius = s.Radius
+	clone.Neighbours = s.Neighbours
+	if s.Avoidance != nil {
+		avoidance := *s.Avoidance
+		clone.Avoidance = &avoidance
+	}
+	for _, wb := range s.behaviours {
+		clone.AddBehaviour(wb.Behaviour.Clone(), wb.Weight)
+	}
+	return clone
+}
@@ -0,0 +1,116 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/ashleycheung/go-game/event"
+	"github.com/ashleycheung/go-game/physics"
+	"github.com/ashleycheung/go-game/steering"
+)
+
+// Two rows of agents swap sides and
+// counts the collisions between them
+func crossSwarms(avoidance bool) (collisions int, arrived int) {
+	w := NewGameWorld()
+	type agent struct {
+		obj    *GameObject
+		target physics.Vector
+	}
+	agents := []agent{}
+	for i := 0; i < 6; i++ {
+		for side := 0; side < 2; side++ {
+			start := physics.Vector{X: 0, Y: float64(i) * 30}
+			target := physics.Vector{X: 300, Y: float64(i) * 30}
+			if side == 1 {
+				start, target = target, start
+			}
+			obj := NewGameObject()
+			pC := NewPhysicsComponent(physics.Circle{Radius: 5})
+			pC.Event.AddListener(OnPhysicsComponentCollideEvent, func(e event.Event[PhysicsComponentEvent]) error {
+				collisions++
+				return nil
+			})
+			obj.AddComponent("physics", pC)
+			s := NewSteeringComponent(100, 400)
+			s.AddBehaviour(steering.NewArrive(target, 20), 1)
+			if avoidance {
+				s.SetAvoidance(steering.NewAvoidance(60, 1000))
+			}
+			obj.AddComponent("steering", s)
+			obj.SetPosition(start)
+			w.Scene.AddChild(obj)
+			agents = append(agents, agent{obj: obj, target: target})
+		}
+	}
+	for i := 0; i < 400; i++ {
+		w.Step(16)
+	}
+	for _, a := range agents {
+		if a.obj.GetGlobalPosition().DistanceTo(a.target) < 5 {
+			arrived++
+		}
+	}
+	return collisions, arrived
+}
+
+func TestSteeringComponentAvoidance(t *testing.T) {
+	// Head on agents push against each other
+	collisions, _ := crossSwarms(false)
+	if collisions == 0 {
+		t.Fatal("expected the swarms to collide without avoidance")
+	}
+
+	avoidCollisions, avoidArrived := crossSwarms(true)
+	if avoidCollisions >= collisions/4 {
+		t.Error("expected avoidance to prevent most collisions", avoidCollisions, collisions)
+	}
+	if avoidArrived != 12 {
+		t.Error("expected every agent to arrive", avoidArrived)
+	}
+}
+
+func TestSteeringComponentClone(t *testing.T) {
+	follow := steering.NewPathFollow([]physics.Vector{{X: 10}}, 2)
+	s := NewSteeringComponent(100, 400).SetAvoidance(steering.NewAvoidance(50, 500))
+	removeFollow := s.AddBehaviour(follow, 2)
+	clone := s.Clone().(*SteeringComponent)
+	behaviours := clone.GetBehaviours()
+	if len(behaviours) != 1 || behaviours[0].Weight != 2 {
+		t.Fatal("expected the behaviours to be copied", behaviours)
+	}
+	if behaviours[0].Behaviour == steering.Behaviour(follow) {
+		t.Error("expected the behaviour to be cloned")
+	}
+	if clone.Avoidance == s.Avoidance || clone.Avoidance.TimeHorizon != 500 {
+		t.Error("expected the avoidance to be copied")
+	}
+	removeFollow()
+	if len(s.GetBehaviours()) != 0 || len(clone.GetBehaviours()) != 1 {
+		t.Error("expected the behaviour to be removed")
+	}
+}
+
+// Functions can't be compared so behaviours
+// are removed by the function AddBehaviour returns
+func TestSteeringComponentRemoveFunc(t *testing.T) {
+	s := NewSteeringComponent(100, 400)
+	f := steering.BehaviourFunc(func(ctx *steering.Context) physics.Vector {
+		return physics.NewZeroVector()
+	})
+	removeF := s.AddBehaviour(f, 1)
+	removeSeek := s.AddBehaviour(steering.NewSeek(physics.Vector{X: 10}), 1)
+	removeF()
+	behaviours := s.GetBehaviours()
+	if len(behaviours) != 1 || behaviours[0].Weight != 1 {
+		t.Fatal("expected only the function removed", behaviours)
+	}
+	if _, isSeek := behaviours[0].Behaviour.(*steering.Seek); !isSeek {
+		t.Error("expected the seek kept", behaviours)
+	}
+	// Removing again does nothing
+	removeF()
+	removeSeek()
+	if len(s.GetBehaviours()) != 0 {
+		t.Error("expected every behaviour removed")
+	}
+}
@@ -0,0 +1,100 @@
+package engine
+
+import "github.com/ashleycheung/go-game/utils"
+
+// A world level system which runs every step after
+// the game objects and ecs systems have stepped.
+// Resources are any names the systems agree on, such
+// as "enemies" or "score"
+type System struct {
+	// Unique name of the system
+	Name string
+
+	// Resources the system only reads
+	Reads []string
+
+	// Resources the system writes
+	Writes []string
+
+	// Called every step.
+	// Delta is the time passed in milliseconds
+	Step func(delta float64)
+}
+
+// Adds a system which is run each step after all the systems
+// added before it. If a system with the same name exists,
+// it is replaced in place.
+//
+// When the world has workers, systems that declare their
+// reads and writes run at the same time as earlier systems
+// they don't conflict with. Systems that declare neither
+// always run on their own
+func (w *GameWorld) AddSystem(s System) {
+	w.systemStages = nil
+	for i, existing := range w.systems {
+		if existing.Name == s.Name {
+			newSystems := append([]System{}, w.systems...)
+			newSystems[i] = s
+			w.systems = newSystems
+			return
+		}
+	}
+	w.systems = append(w.systems, s)
+}
+
+// Removes the system of the given name
+func (w *GameWorld) RemoveSystem(name string) {
+	newSystems := []System{}
+	for _, s := range w.systems {
+		if s.Name != name {
+			newSystems = append(newSystems, s)
+		}
+	}
+	w.systems = newSystems
+	w.systemStages = nil
+}
+
+// Sets the number of workers used to run world systems,
+// ecs systems and the physics narrow phase concurrently.
+// A value of 1 or less runs everything on the world goroutine
+func (w *GameWorld) SetWorkers(workers int) {
+	if w.workerPool != nil {
+		w.workerPool.Close()
+		w.workerPool = nil
+	}
+	if workers > 1 {
+		w.workerPool = utils.NewWorkerPool(workers)
+	}
+	w.ECS.SetWorkerPool(w.workerPool)
+	w.Physics.SetWorkerPool(w.workerPool)
+}
+
+// Returns the number of workers. Returns 1
+// if everything runs on the world goroutine
+func (w *GameWorld) Workers() int {
+	if w.workerPool == nil {
+		return 1
+	}
+	return w.workerPool.Workers()
+}
+
+// Runs all the world systems
+func (w *GameWorld) runSystems(delta float64) {
+	if w.systemStages == nil {
+		jobs := make([]utils.Job[string], len(w.systems))
+		for i, s := range w.systems {
+			system := s
+			jobs[i] = utils.Job[string]{
+				Name:   system.Name,
+				Reads:  system.Reads,
+				Writes: system.Writes,
+				Run: func() {
+					system.Step(w.systemDelta)
+				},
+			}
+		}
+		w.systemStages = utils.BuildStages(jobs)
+	}
+	w.systemDelta = delta
+	utils.RunStages(w.systemStages, w.workerPool)
+}
@@ -0,0 +1,37 @@
+package engine
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestWorldSystems(t *testing.T) {
+	w := NewGameWorld()
+	w.SetWorkers(4)
+	defer w.SetWorkers(1)
+
+	var lock sync.Mutex
+	order := []string{}
+	record := func(name string) func(delta float64) {
+		return func(delta float64) {
+			lock.Lock()
+			defer lock.Unlock()
+			order = append(order, name)
+		}
+	}
+	w.AddSystem(System{Name: "spawn", Writes: []string{"enemies"}, Step: record("spawn")})
+	w.AddSystem(System{Name: "score", Writes: []string{"score"}, Step: record("score")})
+	w.AddSystem(System{Name: "ai", Reads: []string{"enemies"}, Step: record("ai")})
+
+	w.Step(10)
+	if len(order) != 3 || order[2] != "ai" {
+		t.Error("ai should run after spawn", order)
+	}
+
+	w.RemoveSystem("ai")
+	order = []string{}
+	w.Step(10)
+	if len(order) != 2 {
+		t.Error("system not removed", order)
+	}
+}
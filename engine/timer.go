@@ -5,16 +5,20 @@ import "github.com/ashleycheung/go-game/event"
 // Creates a new timer component
 func NewTimerComponent() *TimerComponent {
 	return &TimerComponent{
-		Event: event.NewEventManager[TimerComponentEvent](),
+		Event: newEventManager[TimerComponentEvent](),
 	}
 }
 
+// Counts time using the step delta, so it follows
+// the world clock, time scale and pausing
 type TimerComponent struct {
 	BaseComponent
 	// Timer events
 	Event *event.EventManager[TimerComponentEvent]
 	// Whether running or not
 	isRunning bool
+	// Whether paused while running
+	paused bool
 	// Time passed since start
 	// in milliseconds
 	timePassed float64
@@ -30,17 +34,16 @@ type TimerComponent struct {
 // If the timer is already started, it restarts it
 func (tC *TimerComponent) Start() {
 	tC.isRunning = true
+	tC.paused = false
 	tC.timePassed = 0
 	// Emit event
-	tC.Event.EmitEvent(event.Event[TimerComponentEvent]{
-		Name: OnTimerStartEvent,
-	})
+	tC.GetGameObject().reportError(event.Emit(tC.Event, OnTimerStartTopic, struct{}{}))
 }
 
 // Overrides
 func (tC *TimerComponent) Step(delta float64) {
 	// Update timer if running
-	if tC.isRunning {
+	if tC.IsRunning() {
 		tC.timePassed += delta
 		// Check if duration reached
 		if tC.timePassed >= tC.Duration {
@@ -50,14 +53,56 @@ func (tC *TimerComponent) Step(delta float64) {
 				tC.isRunning = false
 			}
 			// Emit event
-			tC.Event.EmitEvent(event.Event[TimerComponentEvent]{
-				Name: OnTimerEndEvent,
-			})
+			tC.GetGameObject().reportError(event.Emit(tC.Event, OnTimerEndTopic, struct{}{}))
 		}
 	}
 }
 
-// Returns whether the timer is running or not
+// Returns whether the timer is running or not.
+// A paused timer is not running
 func (tC *TimerComponent) IsRunning() bool {
-	return tC.isRunning
+	return tC.isRunning && !tC.paused
+}
+
+// Stops the timer without emitting the end event
+func (tC *TimerComponent) Stop() {
+	tC.isRunning = false
+	tC.paused = false
+	tC.timePassed = 0
+}
+
+// Pauses the timer until resumed.
+// Does nothing if the timer isn't started
+func (tC *TimerComponent) Pause() {
+	if tC.isRunning {
+		tC.paused = true
+	}
+}
+
+// Continues a paused timer
+func (tC *TimerComponent) Resume() {
+	tC.paused = false
+}
+
+// Returns whether the timer is paused
+func (tC *TimerComponent) IsPaused() bool {
+	return tC.paused
+}
+
+// Returns the milliseconds left until the
+// timer ends. Returns 0 if not started
+func (tC *TimerComponent) GetTimeLeft() float64 {
+	if !tC.isRunning {
+		return 0
+	}
+	return tC.Duration - tC.timePassed
+}
+
+// Makes a copy of the timer settings.
+// The copy is not running
+func (tC *TimerComponent) Clone() Component {
+	clone := NewTimerComponent()
+	clone.Duration = tC.Duration
+	clone.Loop = tC.Loop
+	return clone
 }
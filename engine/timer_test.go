@@ -0,0 +1,40 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/ashleycheung/go-game/event"
+)
+
+func TestTimerPause(t *testing.T) {
+	timer := NewTimerComponent()
+	timer.Duration = 100
+	timer.Start()
+	timer.Step(40)
+	timer.Pause()
+	timer.Step(100)
+	if !timer.IsPaused() || timer.IsRunning() {
+		t.Error("timer should be paused")
+	}
+	if timer.GetTimeLeft() != 60 {
+		t.Error("expected 60ms left", timer.GetTimeLeft())
+	}
+
+	ended := false
+	timer.Event.AddListener(OnTimerEndEvent, func(e event.Event[TimerComponentEvent]) error {
+		ended = true
+		return nil
+	})
+	timer.Resume()
+	timer.Step(60)
+	if !ended || timer.IsRunning() {
+		t.Error("timer should have ended")
+	}
+
+	timer.Start()
+	timer.Stop()
+	timer.Step(200)
+	if timer.IsRunning() || timer.GetTimeLeft() != 0 {
+		t.Error("stopped timer should not run")
+	}
+}
@@ -0,0 +1,175 @@
+package engine
+
+import "github.com/ashleycheung/go-game/physics"
+
+// Creates the identity transform
+func NewTransform() Transform {
+	return Transform{
+		Position: physics.NewZeroVector(),
+		Rotation: 0,
+		Scale:    physics.Vector{X: 1, Y: 1},
+	}
+}
+
+// The position, rotation and scale of a game object
+type Transform struct {
+	// The position of the object
+	Position physics.Vector `json:"position"`
+
+	// The rotation of the object in radians
+	Rotation float64 `json:"rotation"`
+
+	// The scale of the object
+	// on each axis
+	Scale physics.Vector `json:"scale"`
+}
+
+// Converts a point in the local space of
+// this transform to the parent space
+func (t Transform) TransformPoint(point physics.Vector) physics.Vector {
+	return t.Position.Add(point.ElementMultiply(t.Scale).Rotate(t.Rotation))
+}
+
+// Converts a point in the parent space to the
+// local space of this transform. Axes with a zero
+// scale are left at zero
+func (t Transform) InverseTransformPoint(point physics.Vector) physics.Vector {
+	local := point.Subtract(t.Position).Rotate(-t.Rotation)
+	if t.Scale.X != 0 {
+		local.X /= t.Scale.X
+	} else {
+		local.X = 0
+	}
+	if t.Scale.Y != 0 {
+		local.Y /= t.Scale.Y
+	} else {
+		local.Y = 0
+	}
+	return local
+}
+
+// Returns the given local transform
+// placed inside this transform
+func (t Transform) Combine(local Transform) Transform {
+	return Transform{
+		Position: t.TransformPoint(local.Position),
+		Rotation: t.Rotation + local.Rotation,
+		Scale:    t.Scale.ElementMultiply(local.Scale),
+	}
+}
+
+// Gets the local transform of the object
+func (g *GameObject) GetTransform() Transform {
+	return g.transform
+}
+
+// Sets the local transform of the object
+func (g *GameObject) SetTransform(t Transform) {
+	g.transform = t
+	g.positionSet = true
+	g.invalidateGlobalTransform()
+}
+
+// Gets the position relative to the parent
+func (g *GameObject) GetPosition() physics.Vector {
+	return g.transform.Position
+}
+
+// Sets the position relative to the parent
+func (g *GameObject) SetPosition(position physics.Vector) {
+	g.transform.Position = position
+	g.positionSet = true
+	g.invalidateGlobalTransform()
+}
+
+// Returns whether the position of the
+// object or one of its ancestors was set
+func (g *GameObject) isPositioned() bool {
+	for o := g; o != nil; o = o.Parent {
+		if o.positionSet {
+			return true
+		}
+	}
+	return false
+}
+
+// Gets the rotation relative to the parent in radians
+func (g *GameObject) GetRotation() float64 {
+	return g.transform.Rotation
+}
+
+// Sets the rotation relative to the parent in radians
+func (g *GameObject) SetRotation(rotation float64) {
+	g.transform.Rotation = rotation
+	g.invalidateGlobalTransform()
+}
+
+// Gets the scale relative to the parent
+func (g *GameObject) GetScale() physics.Vector {
+	return g.transform.Scale
+}
+
+// Sets the scale relative to the parent
+func (g *GameObject) SetScale(scale physics.Vector) {
+	g.transform.Scale = scale
+	g.invalidateGlobalTransform()
+}
+
+// Gets the transform of the object in the space of
+// the root of its tree. This is cached until the
+// object or one of its ancestors changes
+func (g *GameObject) GetGlobalTransform() Transform {
+	if g.globalTransformDirty {
+		if g.Parent == nil {
+			g.globalTransform = g.transform
+		} else {
+			g.globalTransform = g.Parent.GetGlobalTransform().Combine(g.transform)
+		}
+		g.globalTransformDirty = false
+	}
+	return g.globalTransform
+}
+
+// Gets the position in the space of the root of the tree
+func (g *GameObject) GetGlobalPosition() physics.Vector {
+	return g.GetGlobalTransform().Position
+}
+
+// Sets the local position so that the
+// object is at the given global position
+func (g *GameObject) SetGlobalPosition(position physics.Vector) {
+	if g.Parent == nil {
+		g.SetPosition(position)
+		return
+	}
+	g.SetPosition(g.Parent.GetGlobalTransform().InverseTransformPoint(position))
+}
+
+// Gets the rotation in the space of the root of the tree
+func (g *GameObject) GetGlobalRotation() float64 {
+	return g.GetGlobalTransform().Rotation
+}
+
+// Sets the local rotation so that the
+// object has the given global rotation
+func (g *GameObject) SetGlobalRotation(rotation float64) {
+	if g.Parent == nil {
+		g.SetRotation(rotation)
+		return
+	}
+	g.SetRotation(rotation - g.Parent.GetGlobalRotation())
+}
+
+// Marks the cached global transform of this object
+// and all its descendants as out of date
+func (g *GameObject) invalidateGlobalTransform() {
+	// If already dirty, all the descendants
+	// are also dirty
+	if g.globalTransformDirty {
+		return
+	}
+	g.globalTransformDirty = true
+	for _, c := range g.Children {
+		c.invalidateGlobalTransform()
+	}
+}
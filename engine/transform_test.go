@@ -0,0 +1,121 @@
+package engine
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ashleycheung/go-game/event"
+	"github.com/ashleycheung/go-game/physics"
+)
+
+// Returns whether two vectors are roughly equal
+func vectorsClose(v1, v2 physics.Vector) bool {
+	return v1.DistanceTo(v2) < 1e-9
+}
+
+func TestGlobalTransform(t *testing.T) {
+	parent := NewGameObject()
+	child := NewGameObject()
+	parent.AddChild(child)
+
+	parent.SetPosition(physics.Vector{X: 10, Y: 0})
+	child.SetPosition(physics.Vector{X: 1, Y: 0})
+	if !vectorsClose(child.GetGlobalPosition(), physics.Vector{X: 11, Y: 0}) {
+		t.Error("child should move with parent", child.GetGlobalPosition())
+	}
+
+	// Rotate parent a quarter turn and double its size
+	parent.SetRotation(math.Pi / 2)
+	parent.SetScale(physics.Vector{X: 2, Y: 2})
+	if !vectorsClose(child.GetGlobalPosition(), physics.Vector{X: 10, Y: 2}) {
+		t.Error("child should rotate and scale with parent", child.GetGlobalPosition())
+	}
+	if child.GetGlobalRotation() != math.Pi/2 {
+		t.Error("child should rotate with parent")
+	}
+
+	child.SetGlobalPosition(physics.Vector{X: 0, Y: 0})
+	if !vectorsClose(child.GetGlobalPosition(), physics.NewZeroVector()) {
+		t.Error("global position not set", child.GetGlobalPosition())
+	}
+	if !vectorsClose(child.GetPosition(), physics.Vector{X: 0, Y: 5}) {
+		t.Error("local position not set", child.GetPosition())
+	}
+
+	// Removing from the parent makes the local position global
+	parent.RemoveChild(child)
+	if !vectorsClose(child.GetGlobalPosition(), physics.Vector{X: 0, Y: 5}) {
+		t.Error("global transform not updated", child.GetGlobalPosition())
+	}
+}
+
+func TestPhysicsTransformSync(t *testing.T) {
+	w := NewGameWorld()
+
+	player := NewGameObject()
+	pC := NewPhysicsComponent(physics.Circle{Radius: 5})
+	pC.Body.Position = physics.Vector{X: 5, Y: 5}
+	player.AddComponent("physics", pC)
+
+	weapon := NewGameObject()
+	weapon.SetPosition(physics.Vector{X: 1, Y: 0})
+	player.AddChild(weapon)
+	w.Scene.AddChild(player)
+
+	// Object moves to the body when unpositioned
+	if player.GetGlobalPosition() != (physics.Vector{X: 5, Y: 5}) {
+		t.Error("object not moved to body")
+	}
+
+	// Body moves the object
+	pC.Body.Velocity = physics.Vector{X: 1000}
+	w.Physics.Config.AirResistance = 0
+	w.Step(1000)
+	if !vectorsClose(player.GetGlobalPosition(), physics.Vector{X: 1005, Y: 5}) {
+		t.Error("object not synced to body", player.GetGlobalPosition())
+	}
+	if !vectorsClose(weapon.GetGlobalPosition(), physics.Vector{X: 1006, Y: 5}) {
+		t.Error("child did not follow", weapon.GetGlobalPosition())
+	}
+
+	// Object moves the body
+	pC.Body.Velocity = physics.NewZeroVector()
+	player.SetPosition(physics.Vector{X: 20, Y: 20})
+	w.Step(1000)
+	if !vectorsClose(pC.Body.Position, physics.Vector{X: 20, Y: 20}) {
+		t.Error("body not synced to object", pC.Body.Position)
+	}
+}
+
+// Tests that an object placed at the origin
+// on purpose keeps its position
+func TestPhysicsOriginPosition(t *testing.T) {
+	w := NewGameWorld()
+	obj := NewGameObject()
+	obj.SetPosition(physics.NewZeroVector())
+	pC := NewPhysicsComponent(physics.Circle{Radius: 5})
+	pC.Body.Position = physics.Vector{X: 5, Y: 5}
+	obj.AddComponent("physics", pC)
+	w.Scene.AddChild(obj)
+	if !obj.GetGlobalPosition().IsZero() || !pC.Body.Position.IsZero() {
+		t.Error("expected the body moved to the object", obj.GetGlobalPosition(), pC.Body.Position)
+	}
+}
+
+// Tests that a body removed during the
+// physics step is no longer synced
+func TestPhysicsRemovedDuringStep(t *testing.T) {
+	w := NewGameWorld()
+	obj := NewGameObject()
+	pC := NewPhysicsComponent(physics.Circle{Radius: 5})
+	obj.AddComponent("physics", pC)
+	w.Scene.AddChild(obj)
+	event.On(w.Physics.Event, physics.StepEndTopic, func(struct{}) error {
+		obj.RemoveComponent("physics")
+		return nil
+	})
+	w.Step(16)
+	if obj.GetComponent("physics") != nil {
+		t.Error("expected the component removed")
+	}
+}
@@ -0,0 +1,351 @@
+package engine
+
+import (
+	"github.com/ashleycheung/go-game/event"
+	"github.com/ashleycheung/go-game/physics"
+)
+
+// Something that changes values over a duration.
+// Tweens are played on the world with PlayTween
+type Tween interface {
+	// Returns the length of the tween in milliseconds
+	GetDuration() float64
+
+	// Sets the values to how they are at the
+	// given milliseconds into the tween
+	seek(t float64)
+}
+
+// Tweens a value of any type using a lerp function
+type PropertyTween[T any] struct {
+	from     T
+	to       T
+	duration float64
+	ease     EaseFunc
+	lerp     func(from T, to T, t float64) T
+	setter   func(value T)
+}
+
+// Creates a tween from one value to another over the duration
+// in milliseconds. The setter is called with the new value
+// each step and lerp blends the values by t from 0 to 1
+func NewPropertyTween[T any](
+	from T,
+	to T,
+	duration float64,
+	lerp func(from T, to T, t float64) T,
+	setter func(value T),
+) *PropertyTween[T] {
+	return &PropertyTween[T]{
+		from:     from,
+		to:       to,
+		duration: duration,
+		ease:     EaseLinear,
+		lerp:     lerp,
+		setter:   setter,
+	}
+}
+
+// Creates a tween of a float
+func NewFloatTween(from, to, duration float64, setter func(value float64)) *PropertyTween[float64] {
+	return NewPropertyTween(from, to, duration, func(from, to, t float64) float64 {
+		return from + (to-from)*t
+	}, setter)
+}
+
+// Creates a tween of a vector
+func NewVectorTween(from, to physics.Vector, duration float64, setter func(value physics.Vector)) *PropertyTween[physics.Vector] {
+	return NewPropertyTween(from, to, duration, func(from, to physics.Vector, t float64) physics.Vector {
+		return from.Add(to.Subtract(from).Scale(t))
+	}, setter)
+}
+
+// Sets the easing curve. Defaults to linear
+func (p *PropertyTween[T]) SetEase(ease EaseFunc) *PropertyTween[T] {
+	p.ease = ease
+	return p
+}
+
+func (p *PropertyTween[T]) GetDuration() float64 {
+	return p.duration
+}
+
+func (p *PropertyTween[T]) seek(t float64) {
+	progress := 1.0
+	if p.duration > 0 {
+		progress = t / p.duration
+	}
+	p.setter(p.lerp(p.from, p.to, p.ease(progress)))
+}
+
+// A tween that does nothing for the duration.
+// Used to add gaps in a sequence
+type DelayTween struct {
+	duration float64
+}
+
+// Creates a tween that waits for the milliseconds
+func NewDelayTween(duration float64) *DelayTween {
+	return &DelayTween{duration: duration}
+}
+
+func (d *DelayTween) GetDuration() float64 {
+	return d.duration
+}
+
+func (d *DelayTween) seek(t float64) {}
+
+// Plays a group of tweens one after another or all at once
+type TweenGroup struct {
+	tweens []Tween
+
+	// Whether the tweens play one after another
+	sequence bool
+
+	// The time last seeked to
+	lastTime float64
+}
+
+// Creates a group which plays the tweens one after another
+func NewTweenSequence(tweens ...Tween) *TweenGroup {
+	return &TweenGroup{tweens: tweens, sequence: true}
+}
+
+// Creates a group which plays the tweens at the same time
+func NewTweenParallel(tweens ...Tween) *TweenGroup {
+	return &TweenGroup{tweens: tweens}
+}
+
+// Returns the total length of the group
+func (g *TweenGroup) GetDuration() float64 {
+	duration := 0.0
+	for _, t := range g.tweens {
+		if g.sequence {
+			duration += t.GetDuration()
+		} else if t.GetDuration() > duration {
+			duration = t.GetDuration()
+		}
+	}
+	return duration
+}
+
+func (g *TweenGroup) seek(t float64) {
+	if !g.sequence {
+		for _, tween := range g.tweens {
+			tween.seek(clamp(t, 0, tween.GetDuration()))
+		}
+		return
+	}
+
+	// Only seek the tweens passed through since the
+	// last seek so a tween that hasn't started doesn't
+	// overwrite values set by an earlier one
+	from, to := g.lastTime, t
+	if from > to {
+		from, to = to, from
+	}
+	starts := make([]float64, len(g.tweens))
+	start := 0.0
+	for i, tween := range g.tweens {
+		starts[i] = start
+		start += tween.GetDuration()
+	}
+	seekTween := func(i int) {
+		tween := g.tweens[i]
+		end := starts[i] + tween.GetDuration()
+		if end < from || starts[i] > to {
+			return
+		}
+		tween.seek(clamp(t-starts[i], 0, tween.GetDuration()))
+	}
+	if t >= g.lastTime {
+		for i := range g.tweens {
+			seekTween(i)
+		}
+	} else {
+		for i := len(g.tweens) - 1; i >= 0; i-- {
+			seekTween(i)
+		}
+	}
+	g.lastTime = t
+}
+
+// Clamps the value between min and max
+func clamp(v, min, max float64) float64 {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// Plays a tween on the world
+type TweenPlayer struct {
+	world *GameWorld
+
+	tween Tween
+
+	// Time into the current loop
+	elapsed float64
+
+	// The number of loops finished
+	loopCount int
+
+	// The number of times to play. -1 is forever
+	loops int
+
+	// Whether every other loop plays backwards
+	yoyo bool
+
+	paused bool
+
+	done bool
+
+	// Removes the scene exit listener
+	// of the object the tween is bound to
+	unbind func()
+
+	// Emits loop and complete events
+	Event *event.EventManager[TweenEvent]
+}
+
+// Plays the tween from the start of the next step.
+// The tween is set to its start values straight away
+func (w *GameWorld) PlayTween(tween Tween) *TweenPlayer {
+	p := &TweenPlayer{
+		world: w,
+		tween: tween,
+		loops: 1,
+		Event: newEventManager[TweenEvent](),
+	}
+	tween.seek(0)
+	w.tweens = append(w.tweens, p)
+	return p
+}
+
+// Sets the number of times the tween plays.
+// -1 plays forever
+func (p *TweenPlayer) SetLoops(loops int) *TweenPlayer {
+	p.loops = loops
+	return p
+}
+
+// Sets whether every other loop plays backwards
+func (p *TweenPlayer) SetYoyo(yoyo bool) *TweenPlayer {
+	p.yoyo = yoyo
+	return p
+}
+
+// Stops the tween when the object leaves the scene.
+// If the object isn't in this world, the tween is
+// stopped straight away
+func (p *TweenPlayer) BindTo(obj *GameObject) *TweenPlayer {
+	if p.done {
+		return p
+	}
+	if obj.World != p.world {
+		p.Kill()
+		return p
+	}
+	if p.unbind != nil {
+		p.unbind()
+	}
+	p.unbind = event.OnOnce(
+		obj.Event,
+		OnSceneExitTopic,
+		func(struct{}) error {
+			p.unbind = nil
+			p.Kill()
+			return nil
+		},
+	)
+	return p
+}
+
+// Stops the tween where it is without
+// emitting the complete event
+func (p *TweenPlayer) Kill() {
+	p.done = true
+	if p.unbind != nil {
+		p.unbind()
+		p.unbind = nil
+	}
+}
+
+// Stops the tween moving until resumed
+func (p *TweenPlayer) Pause() {
+	p.paused = true
+}
+
+// Continues a paused tween
+func (p *TweenPlayer) Resume() {
+	p.paused = false
+}
+
+// Returns whether the tween is paused
+func (p *TweenPlayer) IsPaused() bool {
+	return p.paused
+}
+
+// Returns whether the tween finished or was killed
+func (p *TweenPlayer) IsDone() bool {
+	return p.done
+}
+
+// Returns whether the current loop plays backwards
+func (p *TweenPlayer) isReversed() bool {
+	return p.yoyo && p.loopCount%2 == 1
+}
+
+// Moves the tween forward by the delta
+func (p *TweenPlayer) update(delta float64) {
+	if p.done || p.paused {
+		return
+	}
+	duration := p.tween.GetDuration()
+	p.elapsed += delta
+	for p.elapsed >= duration {
+		// Finish the current loop
+		if p.isReversed() {
+			p.tween.seek(0)
+		} else {
+			p.tween.seek(duration)
+		}
+		p.loopCount++
+		if p.loops != -1 && p.loopCount >= p.loops {
+			p.Kill()
+			p.world.ReportError(event.Emit(p.Event, OnTweenCompleteTopic, struct{}{}))
+			return
+		}
+		p.elapsed -= duration
+		p.world.ReportError(event.Emit(p.Event, OnTweenLoopTopic, p.loopCount))
+		// A tween with no duration loops once a step
+		if duration <= 0 {
+			p.elapsed = 0
+			return
+		}
+	}
+	if p.isReversed() {
+		p.tween.seek(duration - p.elapsed)
+	} else {
+		p.tween.seek(p.elapsed)
+	}
+}
+
+// Moves every playing tween forward
+func (w *GameWorld) runTweens(delta float64) {
+	// Tweens played while running start next step
+	current := w.tweens
+	for _, p := range current {
+		p.update(delta)
+	}
+	remaining := []*TweenPlayer{}
+	for _, p := range w.tweens {
+		if !p.done {
+			remaining = append(remaining, p)
+		}
+	}
+	w.tweens = remaining
+}
@@ -0,0 +1,122 @@
+package engine
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ashleycheung/go-game/event"
+	"github.com/ashleycheung/go-game/physics"
+)
+
+func TestFloatTween(t *testing.T) {
+	w := NewGameWorld()
+	value := -1.0
+	completed := false
+	player := w.PlayTween(NewFloatTween(0, 100, 100, func(v float64) { value = v }))
+	player.Event.AddListener(OnTweenCompleteEvent, func(e event.Event[TweenEvent]) error {
+		completed = true
+		return nil
+	})
+	if value != 0 {
+		t.Error("tween should start at the from value", value)
+	}
+	w.Step(25)
+	if value != 25 {
+		t.Error("expected 25", value)
+	}
+	w.Step(100)
+	if value != 100 || !completed || !player.IsDone() {
+		t.Error("tween should have completed at the to value", value)
+	}
+}
+
+func TestTweenEase(t *testing.T) {
+	for _, ease := range []EaseFunc{
+		EaseLinear, EaseInQuad, EaseOutQuad, EaseInOutQuad,
+		EaseInCubic, EaseOutCubic, EaseInOutCubic,
+		EaseInSine, EaseOutSine, EaseInOutSine,
+		EaseOutBack, EaseOutElastic, EaseOutBounce,
+	} {
+		if math.Abs(ease(0)) > 1e-9 || math.Abs(ease(1)-1) > 1e-9 {
+			t.Error("ease should go from 0 to 1", ease(0), ease(1))
+		}
+	}
+
+	w := NewGameWorld()
+	value := 0.0
+	w.PlayTween(NewFloatTween(0, 100, 100, func(v float64) { value = v }).SetEase(EaseInQuad))
+	w.Step(50)
+	if value != 25 {
+		t.Error("expected eased value of 25", value)
+	}
+}
+
+func TestTweenSequence(t *testing.T) {
+	w := NewGameWorld()
+	pos := physics.Vector{}
+	seq := NewTweenSequence(
+		NewVectorTween(physics.Vector{}, physics.Vector{X: 10}, 100, func(v physics.Vector) { pos = v }),
+		NewDelayTween(50),
+		NewVectorTween(physics.Vector{X: 10}, physics.Vector{X: 10, Y: 10}, 100, func(v physics.Vector) { pos = v }),
+	)
+	if seq.GetDuration() != 250 {
+		t.Error("expected sequence duration of 250", seq.GetDuration())
+	}
+	w.PlayTween(seq)
+	w.Step(50)
+	if pos != (physics.Vector{X: 5}) {
+		t.Error("expected first tween half way", pos)
+	}
+	w.Step(75)
+	if pos != (physics.Vector{X: 10}) {
+		t.Error("expected first tween finished during the delay", pos)
+	}
+	w.Step(75)
+	if pos != (physics.Vector{X: 10, Y: 5}) {
+		t.Error("expected second tween half way", pos)
+	}
+}
+
+func TestTweenParallel(t *testing.T) {
+	w := NewGameWorld()
+	a, b := 0.0, 0.0
+	group := NewTweenParallel(
+		NewFloatTween(0, 10, 100, func(v float64) { a = v }),
+		NewFloatTween(0, 10, 200, func(v float64) { b = v }),
+	)
+	w.PlayTween(group)
+	w.Step(150)
+	if a != 10 || b != 7.5 {
+		t.Error("expected both tweens to play together", a, b)
+	}
+}
+
+func TestTweenYoyo(t *testing.T) {
+	w := NewGameWorld()
+	value := 0.0
+	loops := 0
+	player := w.PlayTween(NewFloatTween(0, 100, 100, func(v float64) { value = v })).
+		SetLoops(-1).
+		SetYoyo(true)
+	player.Event.AddListener(OnTweenLoopEvent, func(e event.Event[TweenEvent]) error {
+		loops++
+		return nil
+	})
+	w.Step(125)
+	if value != 75 || loops != 1 {
+		t.Error("expected tween to be playing backwards", value, loops)
+	}
+	w.Step(100)
+	if value != 25 || loops != 2 {
+		t.Error("expected tween to be playing forwards", value, loops)
+	}
+
+	obj := NewGameObject()
+	w.Scene.AddChild(obj)
+	player.BindTo(obj)
+	w.Scene.RemoveChild(obj)
+	w.Step(10)
+	if !player.IsDone() || value != 25 {
+		t.Error("tween should stop when the object leaves", value)
+	}
+}
@@ -0,0 +1,116 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Returned when publishing to a closed bus
+var ErrBusClosed = errors.New("event bus closed")
+
+// Emits published events on a manager from its own
+// goroutine. Publishers block while the buffer is full
+// so a slow listener slows down publishers instead of
+// events piling up. The listeners of the manager are
+// called on the bus goroutine, so they should be added
+// before publishing. Listeners must not call Publish or
+// Close as the bus goroutine would wait on itself forever
+// once the buffer is full. Use TryPublish instead
+type AsyncBus[T comparable] struct {
+	manager *EventManager[T]
+
+	events chan Event[T]
+
+	// Called with the errors of emitted events
+	onError func(err error)
+
+	// Guards closed. Publishers hold the read
+	// lock while sending so close waits for them
+	lock   sync.RWMutex
+	closed bool
+
+	// Closed once the bus goroutine finishes
+	done chan struct{}
+}
+
+// Creates a bus emitting on the manager which holds up to
+// the buffer size of events. The error handler is called
+// on the bus goroutine with any emit errors and can be nil
+func NewAsyncBus[T comparable](m *EventManager[T], bufferSize int, onError func(err error)) *AsyncBus[T] {
+	b := &AsyncBus[T]{
+		manager: m,
+		events:  make(chan Event[T], bufferSize),
+		onError: onError,
+		done:    make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// Emits events until the bus is closed
+func (b *AsyncBus[T]) run() {
+	defer close(b.done)
+	for e := range b.events {
+		if err := b.manager.EmitEvent(e); err != nil && b.onError != nil {
+			b.onError(err)
+		}
+	}
+}
+
+// Publishes the event, waiting while the buffer is full.
+// Returns the context error if it is done first. Must not
+// be called from a listener of the bus
+func (b *AsyncBus[T]) Publish(ctx context.Context, e Event[T]) error {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	if b.closed {
+		return ErrBusClosed
+	}
+	select {
+	case b.events <- e:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Publishes the event without waiting.
+// Returns ErrQueueFull if the buffer is full
+func (b *AsyncBus[T]) TryPublish(e Event[T]) error {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	if b.closed {
+		return ErrBusClosed
+	}
+	select {
+	case b.events <- e:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Returns the number of events waiting
+func (b *AsyncBus[T]) Len() int {
+	return len(b.events)
+}
+
+// Stops accepting events and waits for the events
+// waiting to be emitted. Must not be called from a
+// listener of the bus
+func (b *AsyncBus[T]) Close() {
+	b.lock.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.events)
+	}
+	b.lock.Unlock()
+	<-b.done
+}
+
+// Publishes the topic with the payload on the
+// bus. Must not be called from a listener of the bus
+func Publish[T comparable, P any](ctx context.Context, b *AsyncBus[T], topic Topic[T, P], payload P) error {
+	return b.Publish(ctx, topic.Event(payload))
+}
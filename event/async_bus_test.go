@@ -0,0 +1,70 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAsyncBus(t *testing.T) {
+	m := NewEventManager[string]()
+	total := 0
+	m.AddListener("score", func(e Event[string]) error {
+		total += e.Data.(int)
+		return nil
+	})
+	var failures atomic.Int32
+	m.AddListener("fail", func(e Event[string]) error {
+		return errors.New("failed")
+	})
+	bus := NewAsyncBus(m, 4, func(err error) {
+		failures.Add(1)
+	})
+	for i := 1; i <= 100; i++ {
+		if err := Publish(context.Background(), bus, NewTopic[string, int]("score"), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bus.Publish(context.Background(), Event[string]{Name: "fail"})
+	bus.Close()
+	if total != 5050 {
+		t.Error("expected every event emitted before closing", total)
+	}
+	if failures.Load() != 1 {
+		t.Error("expected the error handled", failures.Load())
+	}
+	if err := bus.TryPublish(Event[string]{Name: "score", Data: 1}); !errors.Is(err, ErrBusClosed) {
+		t.Error("expected the bus closed", err)
+	}
+}
+
+func TestAsyncBusBackpressure(t *testing.T) {
+	m := NewEventManager[string]()
+	release := make(chan struct{})
+	m.AddListener("slow", func(e Event[string]) error {
+		<-release
+		return nil
+	})
+	bus := NewAsyncBus(m, 1, nil)
+	defer bus.Close()
+
+	// One is being emitted and one fills the buffer
+	bus.Publish(context.Background(), Event[string]{Name: "slow"})
+	deadline := time.Now().Add(time.Second)
+	for bus.Len() != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	bus.Publish(context.Background(), Event[string]{Name: "slow"})
+
+	if err := bus.TryPublish(Event[string]{Name: "slow"}); !errors.Is(err, ErrQueueFull) {
+		t.Error("expected the buffer to be full", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := bus.Publish(ctx, Event[string]{Name: "slow"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("expected publishing to wait for space", err)
+	}
+	close(release)
+}
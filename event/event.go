@@ -1,11 +1,60 @@
 package event
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sort"
+)
+
+// Returned by a listener to stop the listeners after
+// it being called. It is not treated as an error
+var StopPropagation = errors.New("stop propagation")
+
+// What EmitEvent does when listeners return errors
+type ErrorPolicy int
+
+const (
+	// Returns the first error without
+	// calling the remaining listeners
+	StopOnFirstError ErrorPolicy = iota
+	// Calls every listener and returns
+	// the errors joined together
+	ContinueAndAggregate
+	// Calls every listener and passes each error
+	// to the error handler. EmitEvent returns nil
+	HandleErrors
+)
+
+// Returned for a listener which panicked
+// when panics are recovered
+type ListenerPanicError struct {
+	// The value passed to panic
+	Value any
+	// The stack of the listener when it panicked
+	Stack []byte
+}
+
+func (p *ListenerPanicError) Error() string {
+	return fmt.Sprintf("listener panicked: %v", p.Value)
+}
+
+// Returns the value if it is an error
+func (p *ListenerPanicError) Unwrap() error {
+	err, _ := p.Value.(error)
+	return err
+}
+
+// The priority of listeners and middlewares
+// added without one
+const DefaultPriority = 0
 
 func NewEventManager[T comparable]() *EventManager[T] {
 	return &EventManager[T]{
-		listeners:   map[T]map[int]EventListener[T]{},
-		middlewares: map[int]func(event Event[T]) Event[T]{},
+		listeners:   map[T]map[int]listenerEntry[T]{},
+		matchers:    map[int]listenerEntry[T]{},
+		ordered:     map[T][]listenerEntry[T]{},
+		middlewares: []middlewareEntry[T]{},
 		oneTimeIds:  map[int]bool{},
 	}
 }
@@ -13,16 +62,40 @@ func NewEventManager[T comparable]() *EventManager[T] {
 type EventManager[T comparable] struct {
 	// Maps the event name to map of listener id
 	// to the listener
-	listeners map[T]map[int]EventListener[T]
+	listeners map[T]map[int]listenerEntry[T]
+
+	// Maps the listener id to the listeners
+	// which match event names with a predicate
+	matchers map[int]listenerEntry[T]
+
+	// The listeners of each event in call order
+	// including the matching listeners.
+	// Cleared when the listeners change
+	ordered map[T][]listenerEntry[T]
 
-	// All middle wares for the event
-	middlewares map[int]func(event Event[T]) Event[T]
+	// All middle wares for the event in call order
+	middlewares []middlewareEntry[T]
 
 	idIncrement int
 
 	// Set of all listener ids which are one time
 	// listeners
 	oneTimeIds map[int]bool
+
+	errorPolicy ErrorPolicy
+
+	// Called with listener errors
+	// under the HandleErrors policy
+	errorHandler func(event Event[T], err error)
+
+	// Whether listener panics are returned as errors
+	recoverPanics bool
+
+	// The queue posted events wait in. Nil uses ownQueue
+	queue *Queue
+
+	// Created when first posting without a queue set
+	ownQueue *Queue
 }
 
 type Event[T comparable] struct {
@@ -35,6 +108,61 @@ type Event[T comparable] struct {
 
 type EventListener[T comparable] func(e Event[T]) error
 
+// A listener and when it is called
+type listenerEntry[T comparable] struct {
+	id       int
+	priority int
+	listener EventListener[T]
+	// The event name of exact listeners
+	name T
+	// Returns whether the listener is called for
+	// the event name. Nil for exact listeners
+	match func(eventName T) bool
+}
+
+// A middleware and when it is called
+type middlewareEntry[T comparable] struct {
+	id       int
+	priority int
+	fn       func(event Event[T]) Event[T]
+}
+
+// Sets what happens when listeners return errors.
+// Defaults to StopOnFirstError
+func (e *EventManager[T]) SetErrorPolicy(policy ErrorPolicy) {
+	e.errorPolicy = policy
+}
+
+// Returns what happens when listeners return errors
+func (e *EventManager[T]) GetErrorPolicy() ErrorPolicy {
+	return e.errorPolicy
+}
+
+// Sets the handler called with each listener
+// error and uses the HandleErrors policy
+func (e *EventManager[T]) SetErrorHandler(handler func(event Event[T], err error)) {
+	e.errorHandler = handler
+	e.errorPolicy = HandleErrors
+}
+
+// Sets whether listener panics are recovered and
+// treated as a ListenerPanicError. Defaults to false
+func (e *EventManager[T]) SetRecoverPanics(recoverPanics bool) {
+	e.recoverPanics = recoverPanics
+}
+
+// Returns the number of listeners called for the
+// event including the matching listeners
+func (e *EventManager[T]) ListenerCount(eventName T) int {
+	return len(e.orderedListeners(eventName))
+}
+
+// Returns the number of listeners added for the event
+// name itself, not counting the matching listeners
+func (e *EventManager[T]) ExactListenerCount(eventName T) int {
+	return len(e.listeners[eventName])
+}
+
 // Adds an event listener
 func (e *EventManager[T]) AddListener(
 	// Event name
@@ -44,20 +172,123 @@ func (e *EventManager[T]) AddListener(
 ) (
 	// When called, removes the listener
 	removeListener func(),
+) {
+	return e.AddListenerWithPriority(eventName, DefaultPriority, listener)
+}
+
+// Adds an event listener. Listeners with a higher priority
+// are called first and listeners with the same priority
+// are called in the order they were added
+func (e *EventManager[T]) AddListenerWithPriority(
+	eventName T,
+	priority int,
+	listener EventListener[T],
+) (
+	removeListener func(),
 ) {
 	e.idIncrement++
 	listenerId := e.idIncrement
 
+	entry := listenerEntry[T]{
+		id:       listenerId,
+		priority: priority,
+		listener: listener,
+		name:     eventName,
+	}
 	if _, exists := e.listeners[eventName]; !exists {
-		e.listeners[eventName] = map[int]EventListener[T]{
-			listenerId: listener,
+		e.listeners[eventName] = map[int]listenerEntry[T]{
+			listenerId: entry,
 		}
 	} else {
-		e.listeners[eventName][listenerId] = listener
+		e.listeners[eventName][listenerId] = entry
 	}
+	delete(e.ordered, eventName)
 	return func() {
-		delete(e.listeners[eventName], listenerId)
+		e.removeListener(entry)
+	}
+}
+
+// Returns whether the listener hasn't been removed
+func (e *EventManager[T]) hasListener(entry listenerEntry[T]) bool {
+	if entry.match != nil {
+		_, exists := e.matchers[entry.id]
+		return exists
+	}
+	_, exists := e.listeners[entry.name][entry.id]
+	return exists
+}
+
+// Removes the listener
+func (e *EventManager[T]) removeListener(entry listenerEntry[T]) {
+	if !e.hasListener(entry) {
+		return
+	}
+	delete(e.oneTimeIds, entry.id)
+	if entry.match != nil {
+		delete(e.matchers, entry.id)
+		// Any event could have used the listener
+		e.ordered = map[T][]listenerEntry[T]{}
+		return
 	}
+	delete(e.listeners[entry.name], entry.id)
+	delete(e.ordered, entry.name)
+}
+
+// Adds a listener called for every event
+func (e *EventManager[T]) AddCatchAllListener(listener EventListener[T]) (removeListener func()) {
+	return e.AddMatchListener(func(eventName T) bool {
+		return true
+	}, listener)
+}
+
+// Adds a listener called for the events whose name
+// the predicate returns true for. The predicate must
+// always return the same result for a name as the
+// result is cached until the listeners change
+func (e *EventManager[T]) AddMatchListener(
+	match func(eventName T) bool,
+	listener EventListener[T],
+) (
+	removeListener func(),
+) {
+	return e.AddMatchListenerWithPriority(match, DefaultPriority, listener)
+}
+
+// Adds a matching listener with a priority. Matching
+// listeners are ordered with the listeners of the
+// event by priority then the order they were added
+func (e *EventManager[T]) AddMatchListenerWithPriority(
+	match func(eventName T) bool,
+	priority int,
+	listener EventListener[T],
+) (
+	removeListener func(),
+) {
+	e.idIncrement++
+	entry := listenerEntry[T]{
+		id:       e.idIncrement,
+		priority: priority,
+		listener: listener,
+		match:    match,
+	}
+	e.matchers[entry.id] = entry
+	e.ordered = map[T][]listenerEntry[T]{}
+	return func() {
+		e.removeListener(entry)
+	}
+}
+
+// Adds a matching listener which is called
+// for the first matching event and then removed
+func (e *EventManager[T]) AddOneTimeMatchListener(
+	match func(eventName T) bool,
+	listener EventListener[T],
+) (
+	removeListener func(),
+) {
+	rm := e.AddMatchListener(match, listener)
+	e.oneTimeIds[e.idIncrement] = true
+	return rm
 }
 
 // Adds a listener that is only called once
@@ -68,51 +299,138 @@ func (e *EventManager[T]) AddOneTimeListener(
 ) (
 	removeListener func(),
 ) {
+	return e.AddOneTimeListenerWithPriority(eventName, DefaultPriority, listener)
+}
 
+// Adds a listener with a priority that
+// is only called once and then removed
+func (e *EventManager[T]) AddOneTimeListenerWithPriority(
+	eventName T,
+	priority int,
+	listener EventListener[T],
+) (
+	removeListener func(),
+) {
 	// Add as normal
-	rm := e.AddListener(eventName, listener)
+	rm := e.AddListenerWithPriority(eventName, priority, listener)
 	// The id is just the current increment
 	listenerId := e.idIncrement
 	// Add to one time listener
 	e.oneTimeIds[listenerId] = true
-	// Wrap remove listener
-	removeListener = func() {
-		// Clear from one time id
-		delete(e.oneTimeIds, listenerId)
-		// Remove listener
-		rm()
+	return rm
+}
+
+// Returns the listeners of the event in call order
+func (e *EventManager[T]) orderedListeners(eventName T) []listenerEntry[T] {
+	if ordered, exists := e.ordered[eventName]; exists {
+		return ordered
+	}
+	ordered := make([]listenerEntry[T], 0, len(e.listeners[eventName]))
+	for _, entry := range e.listeners[eventName] {
+		ordered = append(ordered, entry)
 	}
-	return
+	for _, entry := range e.matchers {
+		if entry.match(eventName) {
+			ordered = append(ordered, entry)
+		}
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].priority != ordered[j].priority {
+			return ordered[i].priority > ordered[j].priority
+		}
+		return ordered[i].id < ordered[j].id
+	})
+	e.ordered[eventName] = ordered
+	return ordered
 }
 
-// Adds a middleware to the event manager
+// Adds a middleware to the event manager.
+// Middlewares are called in the order they were added
 func (e *EventManager[T]) Middleware(fn func(event Event[T]) Event[T]) func() {
+	return e.MiddlewareWithPriority(DefaultPriority, fn)
+}
+
+// Adds a middleware to the event manager. Middlewares with
+// a higher priority are called first and middlewares with
+// the same priority are called in the order they were added
+func (e *EventManager[T]) MiddlewareWithPriority(priority int, fn func(event Event[T]) Event[T]) func() {
 	e.idIncrement++
 	id := e.idIncrement
-	e.middlewares[id] = fn
+	entry := middlewareEntry[T]{id: id, priority: priority, fn: fn}
+
+	// Insert after the middlewares with the same or
+	// higher priority. A new slice is made so emits
+	// in progress keep their middlewares
+	index := sort.Search(len(e.middlewares), func(i int) bool {
+		return e.middlewares[i].priority < priority
+	})
+	middlewares := make([]middlewareEntry[T], 0, len(e.middlewares)+1)
+	middlewares = append(middlewares, e.middlewares[:index]...)
+	middlewares = append(middlewares, entry)
+	e.middlewares = append(middlewares, e.middlewares[index:]...)
+
 	return func() {
-		delete(e.middlewares, id)
+		remaining := make([]middlewareEntry[T], 0, len(e.middlewares))
+		for _, m := range e.middlewares {
+			if m.id != id {
+				remaining = append(remaining, m)
+			}
+		}
+		e.middlewares = remaining
 	}
 }
 
+// Calls the middlewares then the listeners of the event in
+// order. Listener errors are handled by the error policy.
+// A listener returning StopPropagation stops the listeners
+// after it being called and is not treated as an error
 func (e *EventManager[T]) EmitEvent(event Event[T]) error {
 	// Parse through all middle wares
-	for _, fn := range e.middlewares {
-		event = fn(event)
+	for _, m := range e.middlewares {
+		event = m.fn(event)
 	}
 
-	// Call listeners
-	for id, listener := range e.listeners[event.Name] {
-		err := listener(event)
+	errs := []error{}
+	// Call listeners. Listeners added while emitting
+	// are called from the next emit
+	for _, entry := range e.orderedListeners(event.Name) {
+		// Removed by an earlier listener
+		if !e.hasListener(entry) {
+			continue
+		}
 		// Clear listener if one time
-		if e.oneTimeIds[id] {
-			// Remove listener
-			delete(e.listeners[event.Name], id)
-			delete(e.oneTimeIds, id)
+		if e.oneTimeIds[entry.id] {
+			e.removeListener(entry)
 		}
-		if err != nil {
-			return fmt.Errorf("event %v: %w", event.Name, err)
+		err := e.callListener(entry.listener, event)
+		if errors.Is(err, StopPropagation) {
+			break
 		}
+		if err == nil {
+			continue
+		}
+		err = fmt.Errorf("event %v: %w", event.Name, err)
+		switch {
+		case e.errorPolicy == HandleErrors && e.errorHandler != nil:
+			e.errorHandler(event, err)
+		case e.errorPolicy == StopOnFirstError:
+			return err
+		default:
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Calls the listener, recovering
+// panics if they are recovered
+func (e *EventManager[T]) callListener(listener EventListener[T], event Event[T]) (err error) {
+	if e.recoverPanics {
+		defer func() {
+			if r := recover(); r != nil {
+				err = &ListenerPanicError{Value: r, Stack: debug.Stack()}
+			}
+		}()
 	}
-	return nil
+	return listener(event)
 }
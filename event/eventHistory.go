@@ -1,68 +1,273 @@
 package event
 
-// Stores a history of the events
-// called
+import (
+	"sync"
+	"time"
+
+	"github.com/ashleycheung/go-game/clock"
+	"github.com/ashleycheung/go-game/utils"
+)
+
+// Stores a history of the events called. Safe to read
+// from any goroutine, but BufferSize must only be changed
+// on the goroutine emitting the tracked events
 type EventHistory[T comparable] struct {
+	lock sync.Mutex
 	// Deletes the middlware
 	middlewareDeleter func()
 	// The max number of events to
 	// track before the oldest events
-	// are chucked
+	// are chucked. 0 or less keeps none
 	BufferSize int
 	// Stores the events
 	// stored in reverse order
 	// (latest is at the end)
-	history []Event[T]
+	history *utils.RingBuffer[HistoryEntry[T]]
 	// Name of all the events tracked
 	// If empty, all events are tracked
 	trackedEvents map[T]bool
 
-	// Stores whether there are events
-	// that have not been read yet
-	hasUnreadHistory bool
+	// The number of events of each
+	// name in the history
+	counts map[T]int
+
+	// The sequence number of the last event
+	lastSeq int
+
+	// Used by HasUnreadHistory and GetHistory
+	defaultCursor *HistoryCursor[T]
+
+	// Called with each new entry
+	subscribers []historySubscriber[T]
+
+	subscriberIncrement int
+
+	// The source of the entry times
+	clock clock.Clock
+}
+
+// An event stored in the history
+type HistoryEntry[T comparable] struct {
+	Event Event[T]
+	// When the event was emitted
+	Time time.Time
+	// Increases by one with each event tracked
+	// starting at 1. Not reset by ClearHistory
+	Seq int
+}
+
+// Reads the events added to the history since it last
+// read. Each consumer of a history uses its own cursor
+// so reading doesn't change what others see as unread
+type HistoryCursor[T comparable] struct {
+	history *EventHistory[T]
+	// The sequence number of the last event read
+	lastSeq int
+}
+
+type historySubscriber[T comparable] struct {
+	id int
+	fn func(entry HistoryEntry[T])
 }
 
 // Creates a new event history
 func NewEventHistory[T comparable]() *EventHistory[T] {
-	return &EventHistory[T]{
+	eH := &EventHistory[T]{
 		BufferSize:    50,
-		history:       []Event[T]{},
+		history:       utils.NewRingBuffer[HistoryEntry[T]](50),
 		trackedEvents: map[T]bool{},
+		counts:        map[T]int{},
+		subscribers:   []historySubscriber[T]{},
+		clock:         clock.NewReal(),
 	}
+	eH.defaultCursor = &HistoryCursor[T]{history: eH}
+	return eH
+}
+
+// Sets the source of the entry times
+func (eH *EventHistory[T]) SetClock(c clock.Clock) {
+	eH.lock.Lock()
+	defer eH.lock.Unlock()
+	eH.clock = c
 }
 
 // Returns whether there is history that has not been read yet
 func (eH *EventHistory[T]) HasUnreadHistory() bool {
-	return eH.hasUnreadHistory
+	return eH.defaultCursor.HasUnread()
 }
 
 // Returns the history of events with
 // the latest one first
 func (eH *EventHistory[T]) GetHistory() []Event[T] {
-	outHistory := make([]Event[T], len(eH.history))
-	for i := 0; i < len(eH.history); i++ {
-		outHistory[len(eH.history)-i-1] = eH.history[i]
+	eH.lock.Lock()
+	entries := eH.filter(func(entry HistoryEntry[T]) bool {
+		return true
+	})
+	eH.defaultCursor.lastSeq = eH.lastSeq
+	eH.lock.Unlock()
+	return entryEvents(entries)
+}
+
+// Returns the entries the filter returns
+// true for with the latest one first
+func (eH *EventHistory[T]) Filter(filter func(entry HistoryEntry[T]) bool) []HistoryEntry[T] {
+	eH.lock.Lock()
+	defer eH.lock.Unlock()
+	return eH.filter(filter)
+}
+
+// Returns the entries of the event
+// name with the latest one first
+func (eH *EventHistory[T]) GetHistoryOf(name T) []HistoryEntry[T] {
+	return eH.Filter(func(entry HistoryEntry[T]) bool {
+		return entry.Event.Name == name
+	})
+}
+
+// Returns the entries from the start time up to and
+// including the end time with the latest one first
+func (eH *EventHistory[T]) GetHistoryBetween(start, end time.Time) []HistoryEntry[T] {
+	return eH.Filter(func(entry HistoryEntry[T]) bool {
+		return !entry.Time.Before(start) && !entry.Time.After(end)
+	})
+}
+
+// Returns the entries from the last duration
+// with the latest one first
+func (eH *EventHistory[T]) GetHistoryWithin(d time.Duration) []HistoryEntry[T] {
+	eH.lock.Lock()
+	start := eH.clock.Now().Add(-d)
+	eH.lock.Unlock()
+	return eH.Filter(func(entry HistoryEntry[T]) bool {
+		return !entry.Time.Before(start)
+	})
+}
+
+// Returns the number of events of
+// the name in the history
+func (eH *EventHistory[T]) Count(name T) int {
+	eH.lock.Lock()
+	defer eH.lock.Unlock()
+	return eH.counts[name]
+}
+
+// Returns the number of events of
+// each name in the history
+func (eH *EventHistory[T]) GetCounts() map[T]int {
+	eH.lock.Lock()
+	defer eH.lock.Unlock()
+	counts := make(map[T]int, len(eH.counts))
+	for name, count := range eH.counts {
+		counts[name] = count
+	}
+	return counts
+}
+
+// Creates a cursor with every event
+// in the history already read
+func (eH *EventHistory[T]) NewCursor() *HistoryCursor[T] {
+	eH.lock.Lock()
+	defer eH.lock.Unlock()
+	return &HistoryCursor[T]{history: eH, lastSeq: eH.lastSeq}
+}
+
+// Calls the function with each entry added to the
+// history. It is called on the goroutine emitting
+// the event. Returns a function which unsubscribes
+func (eH *EventHistory[T]) Subscribe(fn func(entry HistoryEntry[T])) func() {
+	eH.lock.Lock()
+	defer eH.lock.Unlock()
+	eH.subscriberIncrement++
+	id := eH.subscriberIncrement
+	// A new slice is made so pushes in
+	// progress keep their subscribers
+	subscribers := make([]historySubscriber[T], 0, len(eH.subscribers)+1)
+	subscribers = append(subscribers, eH.subscribers...)
+	eH.subscribers = append(subscribers, historySubscriber[T]{id: id, fn: fn})
+	return func() {
+		eH.lock.Lock()
+		defer eH.lock.Unlock()
+		remaining := make([]historySubscriber[T], 0, len(eH.subscribers))
+		for _, s := range eH.subscribers {
+			if s.id != id {
+				remaining = append(remaining, s)
+			}
+		}
+		eH.subscribers = remaining
 	}
-	eH.hasUnreadHistory = false
-	return outHistory
 }
 
 // Clears the history of events tracked
 func (eH *EventHistory[T]) ClearHistory() {
-	eH.history = []Event[T]{}
+	eH.lock.Lock()
+	defer eH.lock.Unlock()
+	eH.history.Clear()
+	eH.counts = map[T]int{}
+}
+
+// Returns the entries the filter returns true
+// for with the latest one first. Must be locked
+func (eH *EventHistory[T]) filter(filter func(entry HistoryEntry[T]) bool) []HistoryEntry[T] {
+	entries := []HistoryEntry[T]{}
+	for i := eH.history.Len() - 1; i >= 0; i-- {
+		entry := eH.history.Get(i)
+		if filter(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
 }
 
 // Pushes an event on to the buffer
 func (eH *EventHistory[T]) pushEvent(event Event[T]) {
-	eH.history = append(eH.history, event)
-	// Remove first element if greater than buffer size
-	if len(eH.history) > eH.BufferSize {
-		eH.history = eH.history[1:]
+	eH.lock.Lock()
+	eH.resize()
+	eH.lastSeq++
+	entry := HistoryEntry[T]{Event: event, Time: eH.clock.Now(), Seq: eH.lastSeq}
+	if eH.BufferSize > 0 {
+		if removed, full := eH.history.Push(entry); full {
+			eH.uncount(removed)
+		}
+		eH.counts[event.Name]++
+	}
+	subscribers := eH.subscribers
+	eH.lock.Unlock()
+
+	for _, s := range subscribers {
+		s.fn(entry)
 	}
-	eH.hasUnreadHistory = true
 }
 
-// Tracks the given event manager
+// Fits the buffer to the buffer size as it
+// can be changed at any time. Must be locked
+func (eH *EventHistory[T]) resize() {
+	if eH.BufferSize <= 0 {
+		if eH.history.Len() != 0 {
+			eH.history.Clear()
+			eH.counts = map[T]int{}
+		}
+		return
+	}
+	if eH.history.Cap() == eH.BufferSize {
+		return
+	}
+	for eH.history.Len() > eH.BufferSize {
+		removed, _ := eH.history.Pop()
+		eH.uncount(removed)
+	}
+	eH.history.SetCapacity(eH.BufferSize)
+}
+
+// Removes the entry from the counts
+func (eH *EventHistory[T]) uncount(entry HistoryEntry[T]) {
+	eH.counts[entry.Event.Name]--
+	if eH.counts[entry.Event.Name] <= 0 {
+		delete(eH.counts, entry.Event.Name)
+	}
+}
+
+// Tracks the given event manager. Events are recorded
+// as changed by the middlewares added before tracking
 func (eH *EventHistory[T]) Track(eM *EventManager[T]) {
 	// Stop tracking if already exists
 	if eH.middlewareDeleter != nil {
@@ -73,7 +278,10 @@ func (eH *EventHistory[T]) Track(eM *EventManager[T]) {
 		func(event Event[T]) Event[T] {
 			// Add event if all events are tracked
 			// or the tracked event name is given
-			if len(eH.trackedEvents) == 0 || eH.trackedEvents[event.Name] {
+			eH.lock.Lock()
+			tracked := len(eH.trackedEvents) == 0 || eH.trackedEvents[event.Name]
+			eH.lock.Unlock()
+			if tracked {
 				eH.pushEvent(event)
 			}
 			return event
@@ -93,10 +301,49 @@ func (eH *EventHistory[T]) StopTracking() {
 // are tracked but if an event is given, it will only
 // track that one
 func (eH *EventHistory[T]) TrackEvent(name T) {
+	eH.lock.Lock()
+	defer eH.lock.Unlock()
 	eH.trackedEvents[name] = true
 }
 
 // Stops tracking the given event
 func (eH *EventHistory[T]) StopTrackingEvent(name T) {
+	eH.lock.Lock()
+	defer eH.lock.Unlock()
 	delete(eH.trackedEvents, name)
 }
+
+// Returns whether events were added
+// to the history since the last read
+func (c *HistoryCursor[T]) HasUnread() bool {
+	c.history.lock.Lock()
+	defer c.history.lock.Unlock()
+	return c.lastSeq < c.history.lastSeq
+}
+
+// Returns the entries added since the last read with
+// the latest one first and marks them as read. Entries
+// pushed out of the buffer before being read are lost
+func (c *HistoryCursor[T]) Read() []HistoryEntry[T] {
+	c.history.lock.Lock()
+	defer c.history.lock.Unlock()
+	entries := []HistoryEntry[T]{}
+	for i := c.history.history.Len() - 1; i >= 0; i-- {
+		entry := c.history.history.Get(i)
+		if entry.Seq <= c.lastSeq {
+			break
+		}
+		entries = append(entries, entry)
+	}
+	c.lastSeq = c.history.lastSeq
+	return entries
+}
+
+// Returns the events of the entries
+func entryEvents[T comparable](entries []HistoryEntry[T]) []Event[T] {
+	events := make([]Event[T], len(entries))
+	for i, entry := range entries {
+		events[i] = entry.Event
+	}
+	return events
+}
@@ -1,7 +1,12 @@
 package event
 
 import (
+	"fmt"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/ashleycheung/go-game/clock"
 )
 
 func TestEventHistory(t *testing.T) {
@@ -55,6 +60,21 @@ func TestEventHistoryBuffer(t *testing.T) {
 	}
 }
 
+func TestEventHistoryNoBuffer(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		eH := NewEventHistory[string]()
+		eM := NewEventManager[string]()
+		eH.Track(eM)
+		eM.EmitEvent(Event[string]{Name: "one"})
+		eH.BufferSize = size
+		eM.EmitEvent(Event[string]{Name: "two"})
+		eM.EmitEvent(Event[string]{Name: "three"})
+		if history := eH.GetHistory(); len(history) != 0 || len(eH.GetCounts()) != 0 {
+			t.Error("expected nothing kept", size, history, eH.GetCounts())
+		}
+	}
+}
+
 func TestEventHistoryTrackEvents(t *testing.T) {
 	eH := NewEventHistory[string]()
 	eM := NewEventManager[string]()
@@ -80,3 +100,124 @@ func TestEventHistoryTrackEvents(t *testing.T) {
 		t.Error("only event two should be tracked")
 	}
 }
+
+// Returns the names of the entries
+func entryNames(entries []HistoryEntry[string]) string {
+	return fmt.Sprint(entryEvents(entries))
+}
+
+func TestEventHistoryQueries(t *testing.T) {
+	start := time.Unix(0, 0)
+	c := clock.NewManual(start)
+	eH := NewEventHistory[string]()
+	eH.SetClock(c)
+	eM := NewEventManager[string]()
+	eH.Track(eM)
+	for i, name := range []string{"hit", "jump", "hit", "hit"} {
+		eM.EmitEvent(Event[string]{Name: name, Data: i})
+		c.Advance(time.Second)
+	}
+
+	within := eH.GetHistoryWithin(2 * time.Second)
+	if entryNames(within) != "[{hit 3} {hit 2}]" {
+		t.Error("expected the last two seconds", entryNames(within))
+	}
+	between := eH.GetHistoryBetween(start.Add(time.Second), start.Add(2*time.Second))
+	if entryNames(between) != "[{hit 2} {jump 1}]" {
+		t.Error("expected the events between", entryNames(between))
+	}
+	if len(eH.GetHistoryOf("hit")) != 3 {
+		t.Error("expected the hits", eH.GetHistoryOf("hit"))
+	}
+	odd := eH.Filter(func(entry HistoryEntry[string]) bool {
+		return entry.Event.Data.(int)%2 == 1
+	})
+	if entryNames(odd) != "[{hit 3} {jump 1}]" {
+		t.Error("expected the filtered events", entryNames(odd))
+	}
+
+	if eH.Count("hit") != 3 || fmt.Sprint(eH.GetCounts()) != "map[hit:3 jump:1]" {
+		t.Error("expected the counts", eH.GetCounts())
+	}
+	// Counts follow the events pushed out
+	eH.BufferSize = 2
+	eM.EmitEvent(Event[string]{Name: "jump", Data: 4})
+	if fmt.Sprint(eH.GetCounts()) != "map[hit:1 jump:1]" {
+		t.Error("expected the counts of the buffer", eH.GetCounts())
+	}
+	eH.ClearHistory()
+	if len(eH.GetCounts()) != 0 {
+		t.Error("expected the counts cleared")
+	}
+}
+
+func TestEventHistoryCursors(t *testing.T) {
+	eH := NewEventHistory[string]()
+	eM := NewEventManager[string]()
+	eH.Track(eM)
+	eM.EmitEvent(Event[string]{Name: "one"})
+
+	debug := eH.NewCursor()
+	telemetry := eH.NewCursor()
+	if debug.HasUnread() {
+		t.Error("new cursors start with everything read")
+	}
+	eM.EmitEvent(Event[string]{Name: "two"})
+	eM.EmitEvent(Event[string]{Name: "three"})
+	if entryNames(debug.Read()) != "[{three <nil>} {two <nil>}]" {
+		t.Error("expected the unread events")
+	}
+	if debug.HasUnread() || !telemetry.HasUnread() {
+		t.Error("reading shouldn't change other cursors")
+	}
+	if !eH.HasUnreadHistory() {
+		t.Error("expected the default cursor unread")
+	}
+	eH.GetHistory()
+	if eH.HasUnreadHistory() || !telemetry.HasUnread() {
+		t.Error("expected only the default cursor read")
+	}
+	eM.EmitEvent(Event[string]{Name: "four"})
+	if len(debug.Read()) != 1 || len(telemetry.Read()) != 3 {
+		t.Error("expected each cursor to read its unread events")
+	}
+}
+
+func TestEventHistorySubscribe(t *testing.T) {
+	eH := NewEventHistory[string]()
+	eM := NewEventManager[string]()
+	eH.Track(eM)
+	seqs := []int{}
+	unsubscribe := eH.Subscribe(func(entry HistoryEntry[string]) {
+		seqs = append(seqs, entry.Seq)
+	})
+	eM.EmitEvent(Event[string]{Name: "one"})
+	eM.EmitEvent(Event[string]{Name: "two"})
+	unsubscribe()
+	eM.EmitEvent(Event[string]{Name: "three"})
+	if fmt.Sprint(seqs) != "[1 2]" {
+		t.Error("expected the entries until unsubscribing", seqs)
+	}
+}
+
+func TestEventHistoryConcurrentReaders(t *testing.T) {
+	eH := NewEventHistory[string]()
+	eM := NewEventManager[string]()
+	eH.Track(eM)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cursor := eH.NewCursor()
+			for j := 0; j < 100; j++ {
+				cursor.Read()
+				eH.GetCounts()
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		eM.EmitEvent(Event[string]{Name: "event"})
+	}
+	wg.Wait()
+}
@@ -1,6 +1,9 @@
 package event
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -59,3 +62,259 @@ func TestOneTimeListener(t *testing.T) {
 		t.Error("one time id not removed")
 	}
 }
+
+func TestListenerOrder(t *testing.T) {
+	m := NewEventManager[string]()
+	order := []string{}
+	listener := func(name string) EventListener[string] {
+		return func(e Event[string]) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		m.AddListener("event", listener(name))
+	}
+	m.AddListenerWithPriority("event", 10, listener("ui"))
+	m.AddListenerWithPriority("event", -1, listener("last"))
+	m.AddOneTimeListenerWithPriority("event", 5, listener("once"))
+
+	m.EmitEvent(Event[string]{Name: "event"})
+	expected := []string{"ui", "once", "a", "b", "c", "d", "e", "last"}
+	if fmt.Sprint(order) != fmt.Sprint(expected) {
+		t.Error("wrong listener order", order)
+	}
+
+	order = []string{}
+	m.EmitEvent(Event[string]{Name: "event"})
+	expected = []string{"ui", "a", "b", "c", "d", "e", "last"}
+	if fmt.Sprint(order) != fmt.Sprint(expected) {
+		t.Error("one time listener should be removed", order)
+	}
+}
+
+func TestStopPropagation(t *testing.T) {
+	m := NewEventManager[string]()
+	gameplayCalled := false
+	m.AddListener("click", func(e Event[string]) error {
+		gameplayCalled = true
+		return nil
+	})
+	rm := m.AddListenerWithPriority("click", 100, func(e Event[string]) error {
+		return StopPropagation
+	})
+	if err := m.EmitEvent(Event[string]{Name: "click"}); err != nil {
+		t.Error("stopping propagation is not an error", err)
+	}
+	if gameplayCalled {
+		t.Error("expected the event to be consumed")
+	}
+	rm()
+	m.EmitEvent(Event[string]{Name: "click"})
+	if !gameplayCalled {
+		t.Error("expected the listener to be called once the consumer is removed")
+	}
+}
+
+func TestListenerRemovedWhileEmitting(t *testing.T) {
+	m := NewEventManager[string]()
+	called := 0
+	var rmSecond func()
+	m.AddListener("event", func(e Event[string]) error {
+		rmSecond()
+		// Added listeners are called from the next emit
+		m.AddListener("event", func(e Event[string]) error {
+			called++
+			return nil
+		})
+		return nil
+	})
+	rmSecond = m.AddListener("event", func(e Event[string]) error {
+		t.Error("removed listener should not be called")
+		return nil
+	})
+	m.EmitEvent(Event[string]{Name: "event"})
+	if called != 0 {
+		t.Error("listener added while emitting should not be called", called)
+	}
+}
+
+func TestOneTimeListenerReentrant(t *testing.T) {
+	m := NewEventManager[string]()
+	called := 0
+	m.AddOneTimeListener("event", func(e Event[string]) error {
+		called++
+		m.EmitEvent(e)
+		return nil
+	})
+	m.EmitEvent(Event[string]{Name: "event"})
+	if called != 1 {
+		t.Error("one time listener should only be called once", called)
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	m := NewEventManager[string]()
+	order := []int{}
+	middleware := func(n int) func(Event[string]) Event[string] {
+		return func(e Event[string]) Event[string] {
+			order = append(order, n)
+			return e
+		}
+	}
+	for i := 1; i <= 5; i++ {
+		m.Middleware(middleware(i))
+	}
+	m.MiddlewareWithPriority(10, middleware(0))
+	rm := m.MiddlewareWithPriority(-10, middleware(9))
+	m.MiddlewareWithPriority(-10, middleware(10))
+	m.EmitEvent(Event[string]{})
+	if fmt.Sprint(order) != fmt.Sprint([]int{0, 1, 2, 3, 4, 5, 9, 10}) {
+		t.Error("wrong middleware order", order)
+	}
+
+	rm()
+	order = []int{}
+	m.EmitEvent(Event[string]{})
+	if fmt.Sprint(order) != fmt.Sprint([]int{0, 1, 2, 3, 4, 5, 10}) {
+		t.Error("middleware not removed", order)
+	}
+}
+
+// Adds a failing, a panicking and a working listener
+func addFailingListeners(m *EventManager[string]) *bool {
+	called := false
+	m.AddListener("event", func(e Event[string]) error {
+		return errors.New("failed")
+	})
+	m.AddListener("event", func(e Event[string]) error {
+		panic("buggy listener")
+	})
+	m.AddListener("event", func(e Event[string]) error {
+		called = true
+		return nil
+	})
+	return &called
+}
+
+func TestErrorPolicyStopOnFirst(t *testing.T) {
+	m := NewEventManager[string]()
+	called := addFailingListeners(m)
+	err := m.EmitEvent(Event[string]{Name: "event"})
+	if err == nil || err.Error() != "event event: failed" {
+		t.Error("expected the first error", err)
+	}
+	if *called {
+		t.Error("expected the remaining listeners to be skipped")
+	}
+}
+
+func TestErrorPolicyAggregate(t *testing.T) {
+	m := NewEventManager[string]()
+	m.SetErrorPolicy(ContinueAndAggregate)
+	m.SetRecoverPanics(true)
+	called := addFailingListeners(m)
+	err := m.EmitEvent(Event[string]{Name: "event"})
+	if !*called {
+		t.Error("expected every listener to be called")
+	}
+	var panicErr *ListenerPanicError
+	if !errors.As(err, &panicErr) || panicErr.Value != "buggy listener" {
+		t.Error("expected the panic as an error", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "failed") {
+		t.Error("expected the errors joined", err)
+	}
+
+	// Panics aren't recovered by default
+	m.SetRecoverPanics(false)
+	defer func() {
+		if recover() == nil {
+			t.Error("expected the panic")
+		}
+	}()
+	m.EmitEvent(Event[string]{Name: "event"})
+}
+
+func TestErrorPolicyHandler(t *testing.T) {
+	m := NewEventManager[string]()
+	m.SetRecoverPanics(true)
+	handled := []error{}
+	m.SetErrorHandler(func(e Event[string], err error) {
+		handled = append(handled, err)
+	})
+	called := addFailingListeners(m)
+	if err := m.EmitEvent(Event[string]{Name: "event"}); err != nil {
+		t.Error("expected errors to go to the handler", err)
+	}
+	if !*called || len(handled) != 2 {
+		t.Error("expected every listener called and both errors handled", handled)
+	}
+	if m.GetErrorPolicy() != HandleErrors {
+		t.Error("expected the handler policy")
+	}
+}
+
+func TestCatchAllListener(t *testing.T) {
+	m := NewEventManager[string]()
+	order := []string{}
+	m.AddListener("a", func(e Event[string]) error {
+		order = append(order, "a")
+		return nil
+	})
+	rm := m.AddCatchAllListener(func(e Event[string]) error {
+		order = append(order, "all "+e.Name)
+		return nil
+	})
+	m.AddListenerWithPriority("a", 1, func(e Event[string]) error {
+		order = append(order, "first")
+		return nil
+	})
+	m.EmitEvent(Event[string]{Name: "a"})
+	m.EmitEvent(Event[string]{Name: "b"})
+	if fmt.Sprint(order) != "[first a all a all b]" {
+		t.Error("expected the catch all ordered with the listeners", order)
+	}
+	if m.ListenerCount("b") != 1 {
+		t.Error("expected the catch all counted", m.ListenerCount("b"))
+	}
+	if m.ExactListenerCount("b") != 0 || m.ExactListenerCount("a") != 2 {
+		t.Error("expected the catch all not counted as exact")
+	}
+	rm()
+	order = []string{}
+	m.EmitEvent(Event[string]{Name: "b"})
+	if len(order) != 0 || m.ListenerCount("b") != 0 {
+		t.Error("expected the catch all removed", order)
+	}
+}
+
+func TestMatchListener(t *testing.T) {
+	m := NewEventManager[int]()
+	received := []int{}
+	m.AddMatchListener(func(eventName int) bool {
+		return eventName%2 == 0
+	}, func(e Event[int]) error {
+		received = append(received, e.Name)
+		return nil
+	})
+	once := 0
+	m.AddOneTimeMatchListener(func(eventName int) bool {
+		return eventName > 2
+	}, func(e Event[int]) error {
+		once = e.Name
+		return nil
+	})
+	for i := 1; i <= 5; i++ {
+		m.EmitEvent(Event[int]{Name: i})
+	}
+	if fmt.Sprint(received) != "[2 4]" {
+		t.Error("expected the even events", received)
+	}
+	if once != 3 {
+		t.Error("expected the one time listener called for the first match", once)
+	}
+	if len(m.matchers) != 1 || len(m.oneTimeIds) != 0 {
+		t.Error("expected the one time listener removed")
+	}
+}
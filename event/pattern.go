@@ -0,0 +1,58 @@
+package event
+
+import (
+	"fmt"
+	"path"
+)
+
+// Adds a listener called for the events whose name
+// matches the glob pattern, such as "onTimer*". Patterns
+// use the syntax of path.Match so * doesn't match a /.
+// Returns an error if the pattern is malformed
+func AddPatternListener[T ~string](
+	m *EventManager[T],
+	pattern string,
+	listener EventListener[T],
+) (func(), error) {
+	return AddPatternListenerWithPriority(m, pattern, DefaultPriority, listener)
+}
+
+// Adds a pattern listener with a priority
+func AddPatternListenerWithPriority[T ~string](
+	m *EventManager[T],
+	pattern string,
+	priority int,
+	listener EventListener[T],
+) (func(), error) {
+	match, err := patternMatcher[T](pattern)
+	if err != nil {
+		return nil, err
+	}
+	return m.AddMatchListenerWithPriority(match, priority, listener), nil
+}
+
+// Adds a pattern listener which is called
+// for the first matching event and then removed
+func AddOneTimePatternListener[T ~string](
+	m *EventManager[T],
+	pattern string,
+	listener EventListener[T],
+) (func(), error) {
+	match, err := patternMatcher[T](pattern)
+	if err != nil {
+		return nil, err
+	}
+	return m.AddOneTimeMatchListener(match, listener), nil
+}
+
+// Returns a predicate matching names to the pattern
+func patternMatcher[T ~string](pattern string) (func(eventName T) bool, error) {
+	// Matching an empty name checks the whole pattern
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("event pattern %q: %w", pattern, err)
+	}
+	return func(eventName T) bool {
+		matched, _ := path.Match(pattern, string(eventName))
+		return matched
+	}, nil
+}
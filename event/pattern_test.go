@@ -0,0 +1,51 @@
+package event
+
+import (
+	"fmt"
+	"testing"
+)
+
+type timerEvent string
+
+func TestPatternListener(t *testing.T) {
+	m := NewEventManager[timerEvent]()
+	received := []timerEvent{}
+	rm, err := AddPatternListener(m, "onTimer*", func(e Event[timerEvent]) error {
+		received = append(received, e.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []timerEvent{"onTimerStart", "onTweenLoop", "onTimerEnd", "onTimer/nested"} {
+		m.EmitEvent(Event[timerEvent]{Name: name})
+	}
+	if fmt.Sprint(received) != "[onTimerStart onTimerEnd]" {
+		t.Error("expected the timer events", received)
+	}
+	rm()
+	m.EmitEvent(Event[timerEvent]{Name: "onTimerStart"})
+	if len(received) != 2 {
+		t.Error("expected the listener removed", received)
+	}
+
+	if _, err := AddPatternListener(m, "onTimer[", func(e Event[timerEvent]) error {
+		return nil
+	}); err == nil {
+		t.Error("expected the malformed pattern to fail")
+	}
+}
+
+func TestOneTimePatternListener(t *testing.T) {
+	m := NewEventManager[string]()
+	calls := 0
+	AddOneTimePatternListener(m, "player.?", func(e Event[string]) error {
+		calls++
+		return nil
+	})
+	m.EmitEvent(Event[string]{Name: "player.1"})
+	m.EmitEvent(Event[string]{Name: "player.2"})
+	if calls != 1 {
+		t.Error("expected one call", calls)
+	}
+}
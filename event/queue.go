@@ -0,0 +1,164 @@
+package event
+
+import (
+	"errors"
+	"sync"
+)
+
+// Returned when posting to a full queue
+var ErrQueueFull = errors.New("event queue full")
+
+// Holds posted events until they are flushed, then emits
+// them in the order they were posted. One queue can be
+// shared by many event managers so their events keep
+// their order. Safe to post to from any goroutine
+type Queue struct {
+	lock sync.Mutex
+
+	// The max events held. 0 is unbounded
+	limit int
+
+	pending []queuedEvent
+
+	// Maps the coalescing key to the
+	// index of the event in pending
+	coalesced map[coalesceKey]int
+}
+
+// An event waiting to be emitted
+type queuedEvent struct {
+	emit func() error
+}
+
+// Identifies events which are coalesced
+type coalesceKey struct {
+	manager any
+	name    any
+	key     any
+}
+
+// Creates a queue holding at most the limit of events.
+// A limit of 0 is unbounded
+func NewQueue(limit int) *Queue {
+	return &Queue{
+		limit:     limit,
+		pending:   []queuedEvent{},
+		coalesced: map[coalesceKey]int{},
+	}
+}
+
+// Returns the number of events waiting
+func (q *Queue) Len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return len(q.pending)
+}
+
+// Adds the event. If the key isn't nil and an event
+// with the same manager, name and key is waiting,
+// it is replaced in place
+func (q *Queue) push(key *coalesceKey, emit func() error) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if key != nil {
+		if index, exists := q.coalesced[*key]; exists {
+			q.pending[index].emit = emit
+			return nil
+		}
+	}
+	if q.limit > 0 && len(q.pending) >= q.limit {
+		return ErrQueueFull
+	}
+	if key != nil {
+		q.coalesced[*key] = len(q.pending)
+	}
+	q.pending = append(q.pending, queuedEvent{emit: emit})
+	return nil
+}
+
+// Emits the events waiting in the order they were posted.
+// Events posted while flushing wait for the next flush.
+// Returns the errors of the emits joined together
+func (q *Queue) Flush() error {
+	q.lock.Lock()
+	pending := q.pending
+	q.pending = []queuedEvent{}
+	q.coalesced = map[coalesceKey]int{}
+	q.lock.Unlock()
+
+	errs := []error{}
+	for _, e := range pending {
+		if err := e.emit(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Removes the events waiting without emitting them
+func (q *Queue) Clear() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.pending = []queuedEvent{}
+	q.coalesced = map[coalesceKey]int{}
+}
+
+// Posts the event to the queue to be emitted
+// on the manager when the queue is flushed
+func PostTo[T comparable](q *Queue, m *EventManager[T], e Event[T]) error {
+	return q.push(nil, func() error {
+		return m.EmitEvent(e)
+	})
+}
+
+// Posts the event to the queue, replacing a waiting event
+// with the same manager, name and key. Used so an event
+// posted many times before a flush is only emitted once
+// with the latest data. The key must be comparable
+func PostCoalescedTo[T comparable](q *Queue, m *EventManager[T], e Event[T], key any) error {
+	return q.push(&coalesceKey{manager: m, name: e.Name, key: key}, func() error {
+		return m.EmitEvent(e)
+	})
+}
+
+// Posts the topic with the payload to be
+// emitted when the queue of the manager is flushed
+func Post[T comparable, P any](m *EventManager[T], topic Topic[T, P], payload P) error {
+	return m.Post(topic.Event(payload))
+}
+
+// Sets the queue posted events wait in. Nil
+// uses a queue belonging to the manager
+func (e *EventManager[T]) SetQueue(q *Queue) {
+	e.queue = q
+}
+
+// Returns the queue posted events wait in
+func (e *EventManager[T]) GetQueue() *Queue {
+	if e.queue != nil {
+		return e.queue
+	}
+	if e.ownQueue == nil {
+		e.ownQueue = NewQueue(0)
+	}
+	return e.ownQueue
+}
+
+// Posts the event to be emitted when the queue of the
+// manager is flushed instead of straight away
+func (e *EventManager[T]) Post(event Event[T]) error {
+	return PostTo(e.GetQueue(), e, event)
+}
+
+// Posts the event, replacing a waiting
+// event with the same name and key
+func (e *EventManager[T]) PostCoalesced(event Event[T], key any) error {
+	return PostCoalescedTo(e.GetQueue(), e, event, key)
+}
+
+// Emits the events waiting in the queue of the manager.
+// If the queue is shared, events of the other
+// managers are emitted too
+func (e *EventManager[T]) Flush() error {
+	return e.GetQueue().Flush()
+}
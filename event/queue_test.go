@@ -0,0 +1,129 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestPostAndFlush(t *testing.T) {
+	m := NewEventManager[string]()
+	received := []any{}
+	m.AddListener("event", func(e Event[string]) error {
+		received = append(received, e.Data)
+		// Posted while flushing so waits for the next flush
+		if e.Data == 1 {
+			m.Post(Event[string]{Name: "event", Data: 3})
+		}
+		return nil
+	})
+	m.Post(Event[string]{Name: "event", Data: 1})
+	Post(m, NewTopic[string, int]("event"), 2)
+	if len(received) != 0 {
+		t.Error("posted events should wait for a flush", received)
+	}
+	m.Flush()
+	if fmt.Sprint(received) != "[1 2]" {
+		t.Error("expected the events in order", received)
+	}
+	m.Flush()
+	if fmt.Sprint(received) != "[1 2 3]" {
+		t.Error("expected the event posted while flushing", received)
+	}
+}
+
+func TestSharedQueue(t *testing.T) {
+	q := NewQueue(0)
+	a := NewEventManager[string]()
+	b := NewEventManager[int]()
+	a.SetQueue(q)
+	b.SetQueue(q)
+	order := []string{}
+	a.AddListener("a", func(e Event[string]) error {
+		order = append(order, "a")
+		return errors.New("failed")
+	})
+	b.AddListener(1, func(e Event[int]) error {
+		order = append(order, "b")
+		return nil
+	})
+	a.Post(Event[string]{Name: "a"})
+	b.Post(Event[int]{Name: 1})
+	a.Post(Event[string]{Name: "a"})
+	if q.Len() != 3 {
+		t.Error("expected the events in the shared queue", q.Len())
+	}
+	err := b.Flush()
+	if fmt.Sprint(order) != "[a b a]" {
+		t.Error("expected the events of both managers in order", order)
+	}
+	if err == nil {
+		t.Error("expected the listener errors")
+	}
+}
+
+func TestPostCoalesced(t *testing.T) {
+	m := NewEventManager[string]()
+	received := []any{}
+	m.AddListener("move", func(e Event[string]) error {
+		received = append(received, e.Data)
+		return nil
+	})
+	m.AddListener("hit", func(e Event[string]) error {
+		received = append(received, e.Data)
+		return nil
+	})
+	m.PostCoalesced(Event[string]{Name: "move", Data: "a1"}, "a")
+	m.Post(Event[string]{Name: "hit", Data: "hit"})
+	m.PostCoalesced(Event[string]{Name: "move", Data: "b1"}, "b")
+	m.PostCoalesced(Event[string]{Name: "move", Data: "a2"}, "a")
+	m.Flush()
+	// The latest data keeps the place of the first post
+	if fmt.Sprint(received) != "[a2 hit b1]" {
+		t.Error("expected the moves coalesced", received)
+	}
+}
+
+func TestQueueLimit(t *testing.T) {
+	q := NewQueue(2)
+	m := NewEventManager[string]()
+	m.SetQueue(q)
+	m.Post(Event[string]{Name: "a"})
+	m.Post(Event[string]{Name: "b"})
+	if err := m.Post(Event[string]{Name: "c"}); !errors.Is(err, ErrQueueFull) {
+		t.Error("expected the queue to be full", err)
+	}
+	// Coalescing doesn't need space
+	m.PostCoalesced(Event[string]{Name: "a"}, 1)
+	q.Clear()
+	if q.Len() != 0 {
+		t.Error("expected the queue cleared")
+	}
+}
+
+func TestQueuePostFromGoroutines(t *testing.T) {
+	q := NewQueue(0)
+	m := NewEventManager[string]()
+	m.SetQueue(q)
+	count := 0
+	m.AddListener("event", func(e Event[string]) error {
+		count++
+		return nil
+	})
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				PostTo(q, m, Event[string]{Name: "event"})
+			}
+		}()
+	}
+	wg.Wait()
+	q.Flush()
+	if count != 1000 {
+		t.Error("expected every posted event", count)
+	}
+}
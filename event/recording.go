@@ -0,0 +1,297 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ashleycheung/go-game/clock"
+)
+
+// An event written by a recorder. Each
+// record is written as a line of json
+type Record[T comparable] struct {
+	// Milliseconds since the recording started
+	Time float64 `json:"time"`
+	// The tick the event was emitted on
+	Tick int `json:"tick"`
+	// Name of the event
+	Name T `json:"name"`
+	// The event data as json
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// Writes the events emitted on a manager to a
+// writer with the time and tick they were emitted
+type Recorder[T comparable] struct {
+	lock sync.Mutex
+
+	encoder *json.Encoder
+
+	clock clock.Clock
+
+	// When recording started
+	start time.Time
+
+	tick int
+
+	// Deletes the middleware
+	middlewareDeleter func()
+
+	// Name of the events recorded.
+	// If empty, all events are recorded
+	recordedEvents map[T]bool
+
+	// The first error writing an event
+	err error
+}
+
+// Creates a recorder writing to the writer
+// with the time from the clock
+func NewRecorder[T comparable](w io.Writer, c clock.Clock) *Recorder[T] {
+	return &Recorder[T]{
+		encoder:        json.NewEncoder(w),
+		clock:          c,
+		start:          c.Now(),
+		recordedEvents: map[T]bool{},
+	}
+}
+
+// Records the events of the manager as changed by
+// the middlewares added before recording. The event
+// data must be able to be encoded as json
+func (r *Recorder[T]) Record(m *EventManager[T]) {
+	if r.middlewareDeleter != nil {
+		r.StopRecording()
+	}
+	r.middlewareDeleter = m.Middleware(func(event Event[T]) Event[T] {
+		r.write(event)
+		return event
+	})
+}
+
+// Stops recording the current event manager
+func (r *Recorder[T]) StopRecording() {
+	if r.middlewareDeleter != nil {
+		r.middlewareDeleter()
+		r.middlewareDeleter = nil
+	}
+}
+
+// Records the given event. By default, all events are
+// recorded but if an event is given, it will only
+// record the events given
+func (r *Recorder[T]) RecordEvent(name T) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.recordedEvents[name] = true
+}
+
+// Moves to the next tick. Usually called each
+// world step so events can be replayed by step
+func (r *Recorder[T]) Tick() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.tick++
+}
+
+// Returns the current tick
+func (r *Recorder[T]) GetTick() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.tick
+}
+
+// Returns the first error writing an event.
+// Events after an error aren't written
+func (r *Recorder[T]) Err() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.err
+}
+
+// Writes the event as a line
+func (r *Recorder[T]) write(event Event[T]) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.err != nil {
+		return
+	}
+	if len(r.recordedEvents) != 0 && !r.recordedEvents[event.Name] {
+		return
+	}
+	record := Record[T]{
+		Time: float64(r.clock.Since(r.start).Microseconds()) / 1000,
+		Tick: r.tick,
+		Name: event.Name,
+	}
+	if event.Data != nil {
+		data, err := json.Marshal(event.Data)
+		if err != nil {
+			r.err = fmt.Errorf("record event %v: %w", event.Name, err)
+			return
+		}
+		record.Data = data
+	}
+	if err := r.encoder.Encode(record); err != nil {
+		r.err = fmt.Errorf("record event %v: %w", event.Name, err)
+	}
+}
+
+// Emits recorded events on a manager
+type Replayer[T comparable] struct {
+	decoder *json.Decoder
+
+	manager *EventManager[T]
+
+	clock clock.Clock
+
+	// Multiplies the speed of timed replays
+	speed float64
+
+	// Decodes the data of the events by name
+	decoders map[T]func(data json.RawMessage) (any, error)
+
+	// The record read but not yet emitted
+	next *Record[T]
+}
+
+// Creates a replayer reading records
+// from the reader and emitting on the manager
+func NewReplayer[T comparable](r io.Reader, m *EventManager[T], c clock.Clock) *Replayer[T] {
+	return &Replayer[T]{
+		decoder:  json.NewDecoder(r),
+		manager:  m,
+		clock:    c,
+		speed:    1,
+		decoders: map[T]func(data json.RawMessage) (any, error){},
+	}
+}
+
+// Sets how fast Run replays. 2 replays twice as
+// fast and 0 replays without waiting. Defaults to 1
+func (r *Replayer[T]) SetSpeed(speed float64) {
+	r.speed = speed
+}
+
+// Sets how the data of the event is decoded. Data of
+// events without a decoder is decoded as plain json
+// values such as map[string]any
+func (r *Replayer[T]) SetDecoder(name T, decoder func(data json.RawMessage) (any, error)) {
+	r.decoders[name] = decoder
+}
+
+// Decodes the data of the topic as its payload type
+func ReplayTopic[T comparable, P any](r *Replayer[T], topic Topic[T, P]) {
+	r.SetDecoder(topic.Name, func(data json.RawMessage) (any, error) {
+		var payload P
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, err
+		}
+		return payload, nil
+	})
+}
+
+// Returns the next record without emitting it.
+// Returns io.EOF when there are no more records
+func (r *Replayer[T]) Peek() (Record[T], error) {
+	if r.next == nil {
+		record := Record[T]{}
+		if err := r.decoder.Decode(&record); err != nil {
+			if errors.Is(err, io.EOF) {
+				return record, io.EOF
+			}
+			return record, fmt.Errorf("read record: %w", err)
+		}
+		r.next = &record
+	}
+	return *r.next, nil
+}
+
+// Emits the next record. Returns io.EOF
+// when there are no more records
+func (r *Replayer[T]) EmitNext() error {
+	record, err := r.Peek()
+	if err != nil {
+		return err
+	}
+	r.next = nil
+	event := Event[T]{Name: record.Name}
+	if len(record.Data) != 0 {
+		decode, exists := r.decoders[record.Name]
+		if !exists {
+			decode = func(data json.RawMessage) (any, error) {
+				var value any
+				err := json.Unmarshal(data, &value)
+				return value, err
+			}
+		}
+		if event.Data, err = decode(record.Data); err != nil {
+			return fmt.Errorf("replay event %v: %w", record.Name, err)
+		}
+	}
+	return r.manager.EmitEvent(event)
+}
+
+// Emits the records up to and including the tick.
+// Used to replay the events of each world step.
+// Returns the listener errors joined
+func (r *Replayer[T]) EmitUntilTick(tick int) error {
+	errs := []error{}
+	for {
+		record, err := r.Peek()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if record.Tick > tick {
+			break
+		}
+		if err := r.EmitNext(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Emits every record waiting on the clock so they are
+// emitted with the timing they were recorded at scaled
+// by the speed. Returns when the records run out or the
+// context is done. Returns the listener errors joined
+func (r *Replayer[T]) Run(ctx context.Context) error {
+	start := r.clock.Now()
+	errs := []error{}
+	for {
+		record, err := r.Peek()
+		if errors.Is(err, io.EOF) {
+			return errors.Join(errs...)
+		}
+		if err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		if r.speed > 0 {
+			at := time.Duration(record.Time / r.speed * float64(time.Millisecond))
+			if wait := at - r.clock.Since(start); wait > 0 {
+				timer := r.clock.NewTimer(wait)
+				select {
+				case <-timer.C():
+				case <-ctx.Done():
+					timer.Stop()
+					return errors.Join(append(errs, ctx.Err())...)
+				}
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		if err := r.EmitNext(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package event
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ashleycheung/go-game/clock"
+)
+
+type inputData struct {
+	Key string `json:"key"`
+}
+
+var inputTopic = NewTopic[string, inputData]("input")
+
+// Records jump at tick 0, then move at tick 1 after
+// 100ms and jump at tick 2 after 300ms
+func recordInputs(t *testing.T) *bytes.Buffer {
+	c := clock.NewManual(time.Unix(0, 0))
+	buffer := &bytes.Buffer{}
+	m := NewEventManager[string]()
+	r := NewRecorder[string](buffer, c)
+	r.Record(m)
+	r.RecordEvent("input")
+
+	Emit(m, inputTopic, inputData{Key: "jump"})
+	c.Advance(100 * time.Millisecond)
+	r.Tick()
+	Emit(m, inputTopic, inputData{Key: "move"})
+	m.EmitEvent(Event[string]{Name: "ignored"})
+	c.Advance(200 * time.Millisecond)
+	r.Tick()
+	Emit(m, inputTopic, inputData{Key: "jump"})
+	r.StopRecording()
+	m.EmitEvent(Event[string]{Name: "input"})
+
+	if err := r.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer
+}
+
+func TestRecorder(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(recordInputs(t).String()), "\n")
+	if len(lines) != 3 {
+		t.Fatal("expected a line per input", lines)
+	}
+	if lines[1] != `{"time":100,"tick":1,"name":"input","data":{"key":"move"}}` {
+		t.Error("unexpected record", lines[1])
+	}
+}
+
+func TestRecorderError(t *testing.T) {
+	m := NewEventManager[string]()
+	r := NewRecorder[string](&bytes.Buffer{}, clock.NewReal())
+	r.Record(m)
+	m.EmitEvent(Event[string]{Name: "bad", Data: func() {}})
+	if r.Err() == nil {
+		t.Error("expected the data to fail to encode")
+	}
+}
+
+func TestReplayerRun(t *testing.T) {
+	buffer := recordInputs(t)
+	c := clock.NewManual(time.Unix(0, 0))
+	m := NewEventManager[string]()
+	received := []string{}
+	On(m, inputTopic, func(input inputData) error {
+		received = append(received, fmt.Sprint(input.Key, " ", c.Since(time.Unix(0, 0)).Milliseconds()))
+		return nil
+	})
+	r := NewReplayer(buffer, m, c)
+	ReplayTopic(r, inputTopic)
+	r.SetSpeed(2)
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(received) != "[jump 0 move 50 jump 150]" {
+		t.Error("expected the inputs at double speed", received)
+	}
+}
+
+func TestReplayerTicks(t *testing.T) {
+	buffer := recordInputs(t)
+	m := NewEventManager[string]()
+	received := []any{}
+	m.AddListener("input", func(e Event[string]) error {
+		received = append(received, e.Data)
+		return nil
+	})
+	r := NewReplayer(buffer, m, clock.NewReal())
+	r.EmitUntilTick(0)
+	if len(received) != 1 {
+		t.Error("expected the first tick", received)
+	}
+	r.EmitUntilTick(2)
+	// Decoded as plain json without a decoder
+	if fmt.Sprint(received) != "[map[key:jump] map[key:move] map[key:jump]]" {
+		t.Error("expected every tick", received)
+	}
+	if err := r.EmitNext(); err == nil {
+		t.Error("expected no more records")
+	}
+}
+
+func TestReplayerCancel(t *testing.T) {
+	m := NewEventManager[string]()
+	r := NewReplayer(strings.NewReader(`{"time":60000,"tick":0,"name":"late"}`), m, clock.NewReal())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := r.Run(ctx); err == nil {
+		t.Error("expected the replay cancelled")
+	}
+}
@@ -0,0 +1,80 @@
+package event
+
+import "fmt"
+
+// An event name bound to the type of its payload.
+// Listeners added with On receive the payload as P
+// so mismatched payloads are caught by the compiler
+type Topic[T comparable, P any] struct {
+	Name T
+}
+
+// Creates a topic for the event name
+func NewTopic[T comparable, P any](name T) Topic[T, P] {
+	return Topic[T, P]{Name: name}
+}
+
+// Listens to a topic with the typed payload
+type TopicListener[P any] func(payload P) error
+
+// Returns the event of the topic with the payload.
+// The data of topics with a struct{} payload is left nil
+func (t Topic[T, P]) Event(payload P) Event[T] {
+	var data any = payload
+	if _, empty := data.(struct{}); empty {
+		data = nil
+	}
+	return Event[T]{Name: t.Name, Data: data}
+}
+
+// Returns the payload of the event. A nil payload is the
+// zero value so topics without data can use struct{}
+func (t Topic[T, P]) Payload(e Event[T]) (P, error) {
+	if e.Data == nil {
+		var zero P
+		return zero, nil
+	}
+	payload, ok := e.Data.(P)
+	if !ok {
+		return payload, fmt.Errorf("event %v: payload is %T not %T", t.Name, e.Data, payload)
+	}
+	return payload, nil
+}
+
+// Wraps the listener to receive the typed payload.
+// Events with the wrong payload type return an error
+func (t Topic[T, P]) listener(listener TopicListener[P]) EventListener[T] {
+	return func(e Event[T]) error {
+		payload, err := t.Payload(e)
+		if err != nil {
+			return err
+		}
+		return listener(payload)
+	}
+}
+
+// Adds a listener of the topic to the manager
+func On[T comparable, P any](m *EventManager[T], topic Topic[T, P], listener TopicListener[P]) func() {
+	return m.AddListener(topic.Name, topic.listener(listener))
+}
+
+// Adds a listener of the topic with a priority
+func OnWithPriority[T comparable, P any](
+	m *EventManager[T],
+	topic Topic[T, P],
+	priority int,
+	listener TopicListener[P],
+) func() {
+	return m.AddListenerWithPriority(topic.Name, priority, topic.listener(listener))
+}
+
+// Adds a listener of the topic which is
+// only called once and then removed
+func OnOnce[T comparable, P any](m *EventManager[T], topic Topic[T, P], listener TopicListener[P]) func() {
+	return m.AddOneTimeListener(topic.Name, topic.listener(listener))
+}
+
+// Emits the topic with the payload
+func Emit[T comparable, P any](m *EventManager[T], topic Topic[T, P], payload P) error {
+	return m.EmitEvent(topic.Event(payload))
+}
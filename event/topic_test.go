@@ -0,0 +1,94 @@
+package event
+
+import (
+	"strings"
+	"testing"
+)
+
+type damage struct {
+	Amount int
+}
+
+var (
+	damageTopic = NewTopic[string, damage]("damage")
+	resetTopic  = NewTopic[string, struct{}]("reset")
+)
+
+func TestTopic(t *testing.T) {
+	m := NewEventManager[string]()
+	total := 0
+	On(m, damageTopic, func(d damage) error {
+		total += d.Amount
+		return nil
+	})
+	once := 0
+	OnOnce(m, damageTopic, func(d damage) error {
+		once++
+		return nil
+	})
+	Emit(m, damageTopic, damage{Amount: 3})
+	Emit(m, damageTopic, damage{Amount: 4})
+	if total != 7 || once != 1 {
+		t.Error("expected the typed payloads", total, once)
+	}
+
+	// Untyped listeners see the same event
+	var data any
+	m.AddListener("damage", func(e Event[string]) error {
+		data = e.Data
+		return nil
+	})
+	Emit(m, damageTopic, damage{Amount: 1})
+	if data != (damage{Amount: 1}) {
+		t.Error("expected the payload as the data", data)
+	}
+}
+
+func TestTopicEmptyPayload(t *testing.T) {
+	m := NewEventManager[string]()
+	called := false
+	On(m, resetTopic, func(struct{}) error {
+		called = true
+		return nil
+	})
+	var data any = "unset"
+	m.AddListener("reset", func(e Event[string]) error {
+		data = e.Data
+		return nil
+	})
+	if err := Emit(m, resetTopic, struct{}{}); err != nil {
+		t.Error(err)
+	}
+	if !called || data != nil {
+		t.Error("expected the listener called with no data", called, data)
+	}
+}
+
+func TestTopicWrongPayload(t *testing.T) {
+	m := NewEventManager[string]()
+	On(m, damageTopic, func(d damage) error {
+		t.Error("listener should not be called with the wrong payload")
+		return nil
+	})
+	err := m.EmitEvent(Event[string]{Name: "damage", Data: "oops"})
+	if err == nil || !strings.Contains(err.Error(), "payload is string") {
+		t.Error("expected a payload type error", err)
+	}
+}
+
+func TestTopicPriority(t *testing.T) {
+	m := NewEventManager[string]()
+	order := []string{}
+	On(m, damageTopic, func(d damage) error {
+		order = append(order, "gameplay")
+		return nil
+	})
+	OnWithPriority(m, damageTopic, 10, func(d damage) error {
+		order = append(order, "shield")
+		return StopPropagation
+	})
+	Emit(m, damageTopic, damage{})
+	if len(order) != 1 || order[0] != "shield" {
+		t.Error("expected the shield to consume the damage", order)
+	}
+}
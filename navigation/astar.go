@@ -0,0 +1,164 @@
+package navigation
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/ashleycheung/go-game/physics"
+)
+
+// A node waiting to be searched
+type openNode struct {
+	cell Cell
+	// Cost from the start plus the estimate to the end
+	priority float64
+}
+
+// A min heap of open nodes
+type openHeap []openNode
+
+func (h openHeap) Len() int           { return len(h) }
+func (h openHeap) Less(i, j int) bool { return h[i].priority < h[j].priority }
+func (h openHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *openHeap) Push(x any)        { *h = append(*h, x.(openNode)) }
+func (h *openHeap) Pop() any {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
+
+// The distance between cells moving in 8 directions
+func octile(a, b Cell) float64 {
+	dx := math.Abs(float64(a.X - b.X))
+	dy := math.Abs(float64(a.Y - b.Y))
+	return math.Max(dx, dy) + (math.Sqrt2-1)*math.Min(dx, dy)
+}
+
+// Runs A* from the start to the end cell. The successors
+// function returns the cells reachable from a cell and
+// its parent. Returns the cells reached, start first
+func search(g *Grid, start, end Cell, successors func(c Cell, parent Cell, hasParent bool) []Cell) ([]Cell, bool) {
+	if !g.IsWalkable(start) || !g.IsWalkable(end) {
+		return nil, false
+	}
+	index := func(c Cell) int {
+		return c.Y*g.Width + c.X
+	}
+	cellCount := g.Width * g.Height
+	costs := make([]float64, cellCount)
+	for i := range costs {
+		costs[i] = math.Inf(1)
+	}
+	parents := make([]int, cellCount)
+	closed := make([]bool, cellCount)
+	costs[index(start)] = 0
+	parents[index(start)] = -1
+	open := &openHeap{{cell: start, priority: octile(start, end)}}
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(openNode).cell
+		currentIndex := index(current)
+		if closed[currentIndex] {
+			continue
+		}
+		closed[currentIndex] = true
+
+		if current == end {
+			path := []Cell{}
+			for i := currentIndex; i != -1; i = parents[i] {
+				path = append(path, Cell{X: i % g.Width, Y: i / g.Width})
+			}
+			// Reverse so the start is first
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			return path, true
+		}
+
+		parentIndex := parents[currentIndex]
+		parent := Cell{}
+		if parentIndex != -1 {
+			parent = Cell{X: parentIndex % g.Width, Y: parentIndex / g.Width}
+		}
+		for _, next := range successors(current, parent, parentIndex != -1) {
+			nextIndex := index(next)
+			if closed[nextIndex] {
+				continue
+			}
+			cost := costs[currentIndex] + octile(current, next)
+			if costs[nextIndex] <= cost {
+				continue
+			}
+			costs[nextIndex] = cost
+			parents[nextIndex] = currentIndex
+			heap.Push(open, openNode{cell: next, priority: cost + octile(next, end)})
+		}
+	}
+	return nil, false
+}
+
+// Finds the shortest path between the points using A*.
+// Returns the points to walk through ending at the end
+// point, without the start. Points are at the centre of
+// cells where the path turns. A start in a blocked cell
+// starts from the nearest walkable cell. Returns false
+// if there is no path or the end isn't walkable
+func (g *Grid) FindPath(start, end physics.Vector) ([]physics.Vector, bool) {
+	startCell, snapped := g.startCell(start)
+	cells, found := search(g, startCell, g.WorldToCell(end),
+		func(c Cell, parent Cell, hasParent bool) []Cell {
+			return g.neighbours(c)
+		},
+	)
+	if !found {
+		return nil, false
+	}
+	return g.toWaypoints(simplifyCells(cells), end, snapped), true
+}
+
+// The max cells away from a blocked start point
+// to look for a walkable cell
+const maxStartSnapDistance = 3
+
+// Returns the cell to start from. Agents pushed into
+// a blocked cell start from the nearest walkable cell
+// instead. Returns true if the start was moved
+func (g *Grid) startCell(start physics.Vector) (Cell, bool) {
+	c := g.WorldToCell(start)
+	if g.IsWalkable(c) {
+		return c, false
+	}
+	if nearest, found := g.NearestWalkable(c, maxStartSnapDistance); found {
+		return nearest, true
+	}
+	return c, false
+}
+
+// Removes the cells in the middle of straight lines
+func simplifyCells(cells []Cell) []Cell {
+	if len(cells) <= 2 {
+		return cells
+	}
+	result := []Cell{cells[0]}
+	for i := 1; i < len(cells)-1; i++ {
+		prev, curr, next := cells[i-1], cells[i], cells[i+1]
+		if curr.X-prev.X != next.X-curr.X || curr.Y-prev.Y != next.Y-curr.Y {
+			result = append(result, curr)
+		}
+	}
+	return append(result, cells[len(cells)-1])
+}
+
+// Converts the cells to points ending at the end point.
+// The start cell is only included if the start was moved
+func (g *Grid) toWaypoints(cells []Cell, end physics.Vector, includeStart bool) []physics.Vector {
+	waypoints := []physics.Vector{}
+	if includeStart && len(cells) > 1 {
+		waypoints = append(waypoints, g.CellToWorld(cells[0]))
+	}
+	for i := 1; i < len(cells)-1; i++ {
+		waypoints = append(waypoints, g.CellToWorld(cells[i]))
+	}
+	return append(waypoints, end)
+}
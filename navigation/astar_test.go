@@ -0,0 +1,120 @@
+package navigation
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/ashleycheung/go-game/physics"
+)
+
+// Returns the length of the path from the start
+func pathLength(start physics.Vector, path []physics.Vector) float64 {
+	length := 0.0
+	for _, p := range path {
+		length += start.DistanceTo(p)
+		start = p
+	}
+	return length
+}
+
+// Returns whether the straight lines of the
+// path only go through walkable cells
+func pathIsWalkable(g *Grid, start physics.Vector, path []physics.Vector) bool {
+	for _, p := range path {
+		steps := int(start.DistanceTo(p)/(g.CellSize/4)) + 1
+		for i := 0; i <= steps; i++ {
+			point := start.Add(p.Subtract(start).Scale(float64(i) / float64(steps)))
+			if !g.IsWalkable(g.WorldToCell(point)) {
+				return false
+			}
+		}
+		start = p
+	}
+	return true
+}
+
+func TestFindPath(t *testing.T) {
+	g := NewGrid(physics.BBox{BottomRight: physics.Vector{X: 100, Y: 100}}, 10)
+	// A wall with a gap at the bottom
+	for y := 0; y < 9; y++ {
+		g.SetBlocked(Cell{5, y}, true)
+	}
+	start := physics.Vector{X: 15, Y: 15}
+	end := physics.Vector{X: 85, Y: 15}
+	for name, find := range map[string]func(start, end physics.Vector) ([]physics.Vector, bool){
+		"astar": g.FindPath,
+		"jps":   g.FindPathJPS,
+	} {
+		path, found := find(start, end)
+		if !found {
+			t.Fatal(name, "expected a path")
+		}
+		if path[len(path)-1] != end {
+			t.Error(name, "path should end at the end point", path)
+		}
+		if !pathIsWalkable(g, start, path) {
+			t.Error(name, "path goes through a wall", path)
+		}
+		// Down to the gap and back up
+		if length := pathLength(start, path); length < 190 || length > 210 {
+			t.Error(name, "unexpected path length", length, path)
+		}
+	}
+
+	g.SetBlocked(Cell{5, 9}, true)
+	if _, found := g.FindPath(start, end); found {
+		t.Error("expected no path")
+	}
+	if _, found := g.FindPathJPS(start, end); found {
+		t.Error("expected no path")
+	}
+}
+
+func TestJPSMatchesAStar(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		g := NewGrid(physics.BBox{BottomRight: physics.Vector{X: 300, Y: 300}}, 10)
+		for j := 0; j < 250; j++ {
+			g.SetBlocked(Cell{r.Intn(g.Width), r.Intn(g.Height)}, true)
+		}
+		start := g.CellToWorld(Cell{0, 0})
+		end := g.CellToWorld(Cell{g.Width - 1, g.Height - 1})
+		g.SetBlocked(Cell{0, 0}, false)
+		g.SetBlocked(Cell{g.Width - 1, g.Height - 1}, false)
+
+		aPath, aFound := g.FindPath(start, end)
+		jPath, jFound := g.FindPathJPS(start, end)
+		if aFound != jFound {
+			t.Fatal("jps and astar disagree on whether there is a path", aFound, jFound)
+		}
+		if !aFound {
+			continue
+		}
+		if math.Abs(pathLength(start, aPath)-pathLength(start, jPath)) > 1e-6 {
+			t.Error("expected the same length", pathLength(start, aPath), pathLength(start, jPath))
+		}
+		if !pathIsWalkable(g, start, jPath) {
+			t.Error("jps path goes through a wall", jPath)
+		}
+	}
+}
+
+func BenchmarkFindPath(b *testing.B) {
+	g := NewGrid(physics.BBox{BottomRight: physics.Vector{X: 2000, Y: 2000}}, 10)
+	for y := 0; y < g.Height-5; y++ {
+		g.SetBlocked(Cell{g.Width / 2, y}, true)
+	}
+	start := physics.Vector{X: 5, Y: 5}
+	end := physics.Vector{X: 1995, Y: 5}
+	b.Run("astar", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			g.FindPath(start, end)
+		}
+	})
+	b.Run("jps", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			g.FindPathJPS(start, end)
+		}
+	})
+}
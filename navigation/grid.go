@@ -0,0 +1,255 @@
+// Finds paths around obstacles in the physics world
+package navigation
+
+import (
+	"math"
+
+	"github.com/ashleycheung/go-game/physics"
+)
+
+// A cell position in a grid
+type Cell struct {
+	X int
+	Y int
+}
+
+// The cell size used when the one given is 0 or less
+const DefaultCellSize = 10
+
+// Creates a grid covering the bounds where every
+// cell is walkable. Cells are squares of the given size.
+// A size of 0 or less uses DefaultCellSize
+func NewGrid(bounds physics.BBox, cellSize float64) *Grid {
+	if cellSize <= 0 {
+		cellSize = DefaultCellSize
+	}
+	size := bounds.Size()
+	width := int(math.Max(math.Ceil(size.X/cellSize), 0))
+	height := int(math.Max(math.Ceil(size.Y/cellSize), 0))
+	return &Grid{
+		Origin:   bounds.TopLeft,
+		CellSize: cellSize,
+		Width:    width,
+		Height:   height,
+		blocked:  make([]bool, width*height),
+	}
+}
+
+// Creates a grid where the cells covered by static,
+// non sensor bodies in the world are blocked. Obstacles
+// are grown by the agent radius so an agent of that
+// radius following the path doesn't touch them
+func NewGridFromWorld(world *physics.World, bounds physics.BBox, cellSize float64, agentRadius float64) *Grid {
+	g := NewGrid(bounds, cellSize)
+	for _, body := range world.Bodies() {
+		if body.Static && !body.Sensor {
+			g.BlockShape(body.Position, body.Shape, agentRadius)
+		}
+	}
+	return g
+}
+
+// A grid of walkable and blocked cells
+type Grid struct {
+	// The top left of the grid in world coordinates
+	Origin physics.Vector
+
+	// The width and height of a cell
+	CellSize float64
+
+	// The number of cells across
+	Width int
+
+	// The number of cells down
+	Height int
+
+	// Indexed by y * Width + x
+	blocked []bool
+}
+
+// Returns whether the cell is inside the grid
+func (g *Grid) InBounds(c Cell) bool {
+	return c.X >= 0 && c.Y >= 0 && c.X < g.Width && c.Y < g.Height
+}
+
+// Returns whether the cell can be walked on.
+// Cells outside the grid can't be walked on
+func (g *Grid) IsWalkable(c Cell) bool {
+	return g.InBounds(c) && !g.blocked[c.Y*g.Width+c.X]
+}
+
+// Sets whether the cell is blocked.
+// Does nothing if the cell is outside the grid
+func (g *Grid) SetBlocked(c Cell, blocked bool) {
+	if g.InBounds(c) {
+		g.blocked[c.Y*g.Width+c.X] = blocked
+	}
+}
+
+// Returns the cell containing the point. The
+// cell may be outside the grid
+func (g *Grid) WorldToCell(p physics.Vector) Cell {
+	local := p.Subtract(g.Origin)
+	return Cell{
+		X: int(math.Floor(local.X / g.CellSize)),
+		Y: int(math.Floor(local.Y / g.CellSize)),
+	}
+}
+
+// Returns the centre of the cell in world coordinates
+func (g *Grid) CellToWorld(c Cell) physics.Vector {
+	return physics.Vector{
+		X: g.Origin.X + (float64(c.X)+0.5)*g.CellSize,
+		Y: g.Origin.Y + (float64(c.Y)+0.5)*g.CellSize,
+	}
+}
+
+// Returns the bounding box of the cell
+func (g *Grid) cellBBox(c Cell) physics.BBox {
+	topLeft := physics.Vector{
+		X: g.Origin.X + float64(c.X)*g.CellSize,
+		Y: g.Origin.Y + float64(c.Y)*g.CellSize,
+	}
+	return physics.BBox{
+		TopLeft:     topLeft,
+		BottomRight: topLeft.Add(physics.Vector{X: g.CellSize, Y: g.CellSize}),
+	}
+}
+
+// Sets every cell overlapping the shape, grown
+// by the inflate distance, as blocked or walkable
+func (g *Grid) setShape(position physics.Vector, shape physics.Shape, inflate float64, blocked bool) {
+	bounds := ShapeBBox(position, shape, inflate)
+	min := g.WorldToCell(bounds.TopLeft)
+	max := g.WorldToCell(bounds.BottomRight)
+	for y := min.Y; y <= max.Y; y++ {
+		for x := min.X; x <= max.X; x++ {
+			c := Cell{X: x, Y: y}
+			if !g.InBounds(c) {
+				continue
+			}
+			// Cells only touching the edge of the bounds
+			// are not covered
+			cellBounds := g.cellBBox(c)
+			if cellBounds.TopLeft.X >= bounds.BottomRight.X ||
+				cellBounds.BottomRight.X <= bounds.TopLeft.X ||
+				cellBounds.TopLeft.Y >= bounds.BottomRight.Y ||
+				cellBounds.BottomRight.Y <= bounds.TopLeft.Y {
+				continue
+			}
+			if circle, isCircle := shape.(physics.Circle); isCircle {
+				// Skip the corners of the bounds
+				// which are outside the circle
+				closest := position.Clamp(cellBounds)
+				if closest.DistanceTo(position) >= circle.Radius+inflate {
+					continue
+				}
+			}
+			g.SetBlocked(c, blocked)
+		}
+	}
+}
+
+// Blocks every cell overlapping the shape grown
+// by the inflate distance
+func (g *Grid) BlockShape(position physics.Vector, shape physics.Shape, inflate float64) {
+	g.setShape(position, shape, inflate, true)
+}
+
+// Unblocks every cell overlapping the shape grown
+// by the inflate distance
+func (g *Grid) UnblockShape(position physics.Vector, shape physics.Shape, inflate float64) {
+	g.setShape(position, shape, inflate, false)
+}
+
+// Returns the bounding box of the shape at the
+// position grown by the inflate distance
+func ShapeBBox(position physics.Vector, shape physics.Shape, inflate float64) physics.BBox {
+	var half physics.Vector
+	switch s := shape.(type) {
+	case physics.Circle:
+		half = physics.Vector{X: s.Radius, Y: s.Radius}
+	case physics.Rectangle:
+		half = s.Size.Scale(0.5)
+	}
+	half = half.Add(physics.Vector{X: inflate, Y: inflate})
+	return physics.BBox{
+		TopLeft:     position.Subtract(half),
+		BottomRight: position.Add(half),
+	}
+}
+
+// Returns the walkable neighbours of the cell. Diagonal
+// moves are only allowed when both cells beside the
+// diagonal are walkable so paths never cut corners
+func (g *Grid) neighbours(c Cell) []Cell {
+	result := make([]Cell, 0, 8)
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if dx == 0 && dy == 0 {
+				continue
+			}
+			n := Cell{X: c.X + dx, Y: c.Y + dy}
+			if !g.IsWalkable(n) {
+				continue
+			}
+			if dx != 0 && dy != 0 &&
+				(!g.IsWalkable(Cell{X: c.X + dx, Y: c.Y}) || !g.IsWalkable(Cell{X: c.X, Y: c.Y + dy})) {
+				continue
+			}
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+// Returns the nearest walkable cell to the given cell
+// searching outwards up to the max distance in cells
+func (g *Grid) NearestWalkable(c Cell, maxDistance int) (Cell, bool) {
+	if g.IsWalkable(c) {
+		return c, true
+	}
+	for r := 1; r <= maxDistance; r++ {
+		best := Cell{}
+		bestDist := math.Inf(1)
+		for y := c.Y - r; y <= c.Y+r; y++ {
+			for x := c.X - r; x <= c.X+r; x++ {
+				// Only check the ring
+				if y != c.Y-r && y != c.Y+r && x != c.X-r && x != c.X+r {
+					continue
+				}
+				n := Cell{X: x, Y: y}
+				if !g.IsWalkable(n) {
+					continue
+				}
+				dist := math.Hypot(float64(x-c.X), float64(y-c.Y))
+				if dist < bestDist {
+					best = n
+					bestDist = dist
+				}
+			}
+		}
+		if !math.IsInf(bestDist, 1) {
+			return best, true
+		}
+	}
+	return Cell{}, false
+}
+
+// Finds paths between points
+type Pathfinder interface {
+	// Returns the points to walk through to get from
+	// start to end, without the start and ending at end.
+	// Returns false if there is no path
+	FindPath(start, end physics.Vector) ([]physics.Vector, bool)
+}
+
+// Lets a function be used as a pathfinder,
+// such as a grid using jump point search
+//
+//	navigation.PathfinderFunc(grid.FindPathJPS)
+type PathfinderFunc func(start, end physics.Vector) ([]physics.Vector, bool)
+
+func (f PathfinderFunc) FindPath(start, end physics.Vector) ([]physics.Vector, bool) {
+	return f(start, end)
+}
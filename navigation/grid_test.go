@@ -0,0 +1,77 @@
+package navigation
+
+import (
+	"testing"
+
+	"github.com/ashleycheung/go-game/physics"
+)
+
+func TestGridFromWorld(t *testing.T) {
+	world := physics.NewWorld()
+	wall := physics.NewBody(physics.Rectangle{Size: physics.Vector{X: 20, Y: 40}})
+	wall.Position = physics.Vector{X: 50, Y: 50}
+	wall.Static = true
+	world.AddBody(wall)
+
+	// Moving bodies are not obstacles
+	ball := physics.NewBody(physics.Circle{Radius: 10})
+	ball.Position = physics.Vector{X: 15, Y: 15}
+	world.AddBody(ball)
+
+	g := NewGridFromWorld(world, physics.BBox{BottomRight: physics.Vector{X: 100, Y: 100}}, 10, 0)
+	if g.Width != 10 || g.Height != 10 {
+		t.Fatal("expected a 10 by 10 grid", g.Width, g.Height)
+	}
+	// The wall covers x 40 to 60 and y 30 to 70
+	for _, c := range []Cell{{4, 3}, {5, 3}, {4, 6}, {5, 6}} {
+		if g.IsWalkable(c) {
+			t.Error("expected cell to be blocked", c)
+		}
+	}
+	for _, c := range []Cell{{3, 3}, {6, 3}, {4, 2}, {4, 7}, {1, 1}} {
+		if !g.IsWalkable(c) {
+			t.Error("expected cell to be walkable", c)
+		}
+	}
+
+	// Inflating by the agent radius blocks the cells around it
+	inflated := NewGridFromWorld(world, physics.BBox{BottomRight: physics.Vector{X: 100, Y: 100}}, 10, 5)
+	if inflated.IsWalkable(Cell{3, 3}) || inflated.IsWalkable(Cell{4, 2}) {
+		t.Error("expected inflated cells to be blocked")
+	}
+}
+
+func TestBlockCircle(t *testing.T) {
+	g := NewGrid(physics.BBox{BottomRight: physics.Vector{X: 100, Y: 100}}, 10)
+	g.BlockShape(physics.Vector{X: 50, Y: 50}, physics.Circle{Radius: 12}, 0)
+	if g.IsWalkable(Cell{4, 4}) || g.IsWalkable(Cell{5, 6}) {
+		t.Error("expected the centre cells to be blocked")
+	}
+	// The corners of the bounding box are outside the circle
+	if !g.IsWalkable(Cell{3, 3}) {
+		t.Error("expected corner cell to be walkable")
+	}
+	g.UnblockShape(physics.Vector{X: 50, Y: 50}, physics.Circle{Radius: 12}, 0)
+	if !g.IsWalkable(Cell{4, 4}) {
+		t.Error("expected cell to be unblocked")
+	}
+}
+
+func TestNearestWalkable(t *testing.T) {
+	g := NewGrid(physics.BBox{BottomRight: physics.Vector{X: 50, Y: 50}}, 10)
+	g.SetBlocked(Cell{2, 2}, true)
+	g.SetBlocked(Cell{1, 2}, true)
+	c, ok := g.NearestWalkable(Cell{2, 2}, 2)
+	if !ok || c == (Cell{2, 2}) || !g.IsWalkable(c) {
+		t.Error("expected a walkable neighbour", c)
+	}
+}
+
+func TestGridInvalidCellSize(t *testing.T) {
+	for _, cellSize := range []float64{0, -5} {
+		g := NewGrid(physics.BBox{BottomRight: physics.Vector{X: 100, Y: 100}}, cellSize)
+		if g.CellSize != DefaultCellSize || g.Width != 10 || g.Height != 10 {
+			t.Error("expected the default cell size", cellSize, g.CellSize, g.Width, g.Height)
+		}
+	}
+}
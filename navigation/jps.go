@@ -0,0 +1,143 @@
+package navigation
+
+import "github.com/ashleycheung/go-game/physics"
+
+// Returns -1, 0 or 1
+func sign(v int) int {
+	if v < 0 {
+		return -1
+	}
+	if v > 0 {
+		return 1
+	}
+	return 0
+}
+
+// Finds the shortest path between the points using
+// jump point search. Gives the same length paths as
+// FindPath but searches far fewer cells on open grids
+func (g *Grid) FindPathJPS(start, end physics.Vector) ([]physics.Vector, bool) {
+	endCell := g.WorldToCell(end)
+	startCell, snapped := g.startCell(start)
+	cells, found := search(g, startCell, endCell,
+		func(c Cell, parent Cell, hasParent bool) []Cell {
+			jumpPoints := []Cell{}
+			for _, n := range g.prunedNeighbours(c, parent, hasParent) {
+				if jp, ok := g.jump(n, c, endCell); ok {
+					jumpPoints = append(jumpPoints, jp)
+				}
+			}
+			return jumpPoints
+		},
+	)
+	if !found {
+		return nil, false
+	}
+	return g.toWaypoints(simplifyCells(cells), end, snapped), true
+}
+
+// Returns the neighbours worth searching based on
+// the direction the cell was reached from
+func (g *Grid) prunedNeighbours(c Cell, parent Cell, hasParent bool) []Cell {
+	if !hasParent {
+		return g.neighbours(c)
+	}
+	walkable := func(dx, dy int) bool {
+		return g.IsWalkable(Cell{X: c.X + dx, Y: c.Y + dy})
+	}
+	dx := sign(c.X - parent.X)
+	dy := sign(c.Y - parent.Y)
+	result := []Cell{}
+	add := func(x, y int) {
+		result = append(result, Cell{X: c.X + x, Y: c.Y + y})
+	}
+
+	switch {
+	case dx != 0 && dy != 0:
+		if walkable(0, dy) {
+			add(0, dy)
+		}
+		if walkable(dx, 0) {
+			add(dx, 0)
+		}
+		if walkable(0, dy) && walkable(dx, 0) && walkable(dx, dy) {
+			add(dx, dy)
+		}
+	case dx != 0:
+		next, up, down := walkable(dx, 0), walkable(0, -1), walkable(0, 1)
+		if next {
+			add(dx, 0)
+			if up && walkable(dx, -1) {
+				add(dx, -1)
+			}
+			if down && walkable(dx, 1) {
+				add(dx, 1)
+			}
+		}
+		if up {
+			add(0, -1)
+		}
+		if down {
+			add(0, 1)
+		}
+	default:
+		next, left, right := walkable(0, dy), walkable(-1, 0), walkable(1, 0)
+		if next {
+			add(0, dy)
+			if left && walkable(-1, dy) {
+				add(-1, dy)
+			}
+			if right && walkable(1, dy) {
+				add(1, dy)
+			}
+		}
+		if left {
+			add(-1, 0)
+		}
+		if right {
+			add(1, 0)
+		}
+	}
+	return result
+}
+
+// Moves from the parent through the cell in the same
+// direction until reaching a cell that needs to be
+// searched. Returns false if it hits a wall first
+func (g *Grid) jump(c Cell, parent Cell, end Cell) (Cell, bool) {
+	dx := c.X - parent.X
+	dy := c.Y - parent.Y
+	for {
+		if !g.IsWalkable(c) {
+			return Cell{}, false
+		}
+		if c == end {
+			return c, true
+		}
+		walkable := func(x, y int) bool {
+			return g.IsWalkable(Cell{X: c.X + x, Y: c.Y + y})
+		}
+
+		if dx != 0 && dy != 0 {
+			// Stop if a straight line from here finds something
+			if _, ok := g.jump(Cell{X: c.X + dx, Y: c.Y}, c, end); ok {
+				return c, true
+			}
+			if _, ok := g.jump(Cell{X: c.X, Y: c.Y + dy}, c, end); ok {
+				return c, true
+			}
+			// Diagonals can't cut corners
+			if !walkable(dx, 0) || !walkable(0, dy) {
+				return Cell{}, false
+			}
+		} else if dx != 0 {
+			// A wall behind opens up beside
+			if (walkable(0, -1) && !walkable(-dx, -1)) || (walkable(0, 1) && !walkable(-dx, 1)) {
+				return c, true
+			}
+		} else if (walkable(-1, 0) && !walkable(-1, -dy)) || (walkable(1, 0) && !walkable(1, -dy)) {
+			return c, true
+		}
+		c = Cell{X: c.X + dx, Y: c.Y + dy}
+	}
+}
@@ -0,0 +1,163 @@
+package navigation
+
+import (
+	"math"
+	"sort"
+
+	"github.com/ashleycheung/go-game/physics"
+)
+
+// The cells of a navigation mesh. Rows and columns are split
+// along the edges of the obstacle outlines so every cell is
+// either fully blocked or fully walkable
+type meshGrid struct {
+	// The x of each column edge from left to right
+	xs []float64
+
+	// The y of each row edge from top to bottom
+	ys []float64
+
+	// Indexed by y * width + x
+	blocked []bool
+}
+
+// Creates the cells of the bounds with the
+// rectangles blocked
+func newMeshGrid(bounds physics.BBox, rects []physics.BBox) *meshGrid {
+	// Bounds with no area have no cells
+	if bounds.TopLeft.X >= bounds.BottomRight.X || bounds.TopLeft.Y >= bounds.BottomRight.Y {
+		return &meshGrid{xs: []float64{bounds.TopLeft.X}, ys: []float64{bounds.TopLeft.Y}}
+	}
+	clipped := []physics.BBox{}
+	for _, r := range rects {
+		r = physics.BBox{
+			TopLeft: physics.Vector{
+				X: math.Max(r.TopLeft.X, bounds.TopLeft.X),
+				Y: math.Max(r.TopLeft.Y, bounds.TopLeft.Y),
+			},
+			BottomRight: physics.Vector{
+				X: math.Min(r.BottomRight.X, bounds.BottomRight.X),
+				Y: math.Min(r.BottomRight.Y, bounds.BottomRight.Y),
+			},
+		}
+		if r.TopLeft.X < r.BottomRight.X && r.TopLeft.Y < r.BottomRight.Y {
+			clipped = append(clipped, r)
+		}
+	}
+
+	xs := []float64{bounds.TopLeft.X, bounds.BottomRight.X}
+	ys := []float64{bounds.TopLeft.Y, bounds.BottomRight.Y}
+	for _, r := range clipped {
+		xs = append(xs, r.TopLeft.X, r.BottomRight.X)
+		ys = append(ys, r.TopLeft.Y, r.BottomRight.Y)
+	}
+	g := &meshGrid{xs: sortedUnique(xs), ys: sortedUnique(ys)}
+	g.blocked = make([]bool, g.width()*g.height())
+
+	// The rectangle edges are on the cell edges
+	// so the cells they cover are found exactly
+	for _, r := range clipped {
+		minX := sort.SearchFloat64s(g.xs, r.TopLeft.X)
+		maxX := sort.SearchFloat64s(g.xs, r.BottomRight.X)
+		minY := sort.SearchFloat64s(g.ys, r.TopLeft.Y)
+		maxY := sort.SearchFloat64s(g.ys, r.BottomRight.Y)
+		for y := minY; y < maxY; y++ {
+			for x := minX; x < maxX; x++ {
+				g.blocked[y*g.width()+x] = true
+			}
+		}
+	}
+	return g
+}
+
+// Sorts the values and removes duplicates
+func sortedUnique(values []float64) []float64 {
+	sort.Float64s(values)
+	out := []float64{}
+	for _, v := range values {
+		if len(out) == 0 || out[len(out)-1] != v {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// The number of cells across
+func (g *meshGrid) width() int {
+	return len(g.xs) - 1
+}
+
+// The number of cells down
+func (g *meshGrid) height() int {
+	return len(g.ys) - 1
+}
+
+// Returns whether the cell is inside the grid
+func (g *meshGrid) inBounds(c Cell) bool {
+	return c.X >= 0 && c.Y >= 0 && c.X < g.width() && c.Y < g.height()
+}
+
+// Returns whether the cell can be walked on.
+// Cells outside the grid can't be walked on
+func (g *meshGrid) isWalkable(c Cell) bool {
+	return g.inBounds(c) && !g.blocked[c.Y*g.width()+c.X]
+}
+
+// Returns the bounding box of the cell
+func (g *meshGrid) cellBBox(c Cell) physics.BBox {
+	return physics.BBox{
+		TopLeft:     physics.Vector{X: g.xs[c.X], Y: g.ys[c.Y]},
+		BottomRight: physics.Vector{X: g.xs[c.X+1], Y: g.ys[c.Y+1]},
+	}
+}
+
+// Returns the cell containing the point. Points on
+// an edge are in the cell to the right or below.
+// The cell may be outside the grid
+func (g *meshGrid) worldToCell(p physics.Vector) Cell {
+	return Cell{X: edgeIndex(g.xs, p.X), Y: edgeIndex(g.ys, p.Y)}
+}
+
+// Returns the index of the last edge at or before the value
+func edgeIndex(edges []float64, v float64) int {
+	i := sort.SearchFloat64s(edges, v)
+	if i < len(edges) && edges[i] == v {
+		return i
+	}
+	return i - 1
+}
+
+// Returns the rectangles covering the shape at the position
+// grown by the inflate distance. Rectangles are covered
+// exactly and circles by bands of at most the band height
+func obstacleRects(position physics.Vector, shape physics.Shape, inflate float64, bandHeight float64) []physics.BBox {
+	circle, isCircle := shape.(physics.Circle)
+	if !isCircle {
+		return []physics.BBox{ShapeBBox(position, shape, inflate)}
+	}
+	radius := circle.Radius + inflate
+	if radius <= 0 {
+		return []physics.BBox{}
+	}
+	bands := int(math.Max(math.Ceil(2*radius/bandHeight), 1))
+	height := 2 * radius / float64(bands)
+	rects := make([]physics.BBox, 0, bands)
+	for i := 0; i < bands; i++ {
+		top := position.Y - radius + float64(i)*height
+		bottom := top + height
+		// The band is as wide as the circle
+		// where it is closest to the centre
+		dist := 0.0
+		if top > position.Y {
+			dist = top - position.Y
+		} else if bottom < position.Y {
+			dist = position.Y - bottom
+		}
+		halfWidth := math.Sqrt(math.Max(radius*radius-dist*dist, 0))
+		rects = append(rects, physics.BBox{
+			TopLeft:     physics.Vector{X: position.X - halfWidth, Y: top},
+			BottomRight: physics.Vector{X: position.X + halfWidth, Y: bottom},
+		})
+	}
+	return rects
+}
@@ -0,0 +1,528 @@
+package navigation
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/ashleycheung/go-game/physics"
+)
+
+// Configures how a navigation mesh is built
+type NavMeshConfig struct {
+	// The area that can be walked on
+	Bounds physics.BBox
+
+	// The height of the bands circles are outlined with.
+	// Smaller sizes follow circles more closely but make
+	// more polygons. Rectangles are followed exactly.
+	// 0 or less uses DefaultCellSize
+	CellSize float64
+
+	// Obstacles are grown by this distance so an agent
+	// of this radius following a path doesn't touch them
+	AgentRadius float64
+}
+
+// A convex area of the navigation mesh. Any two points
+// inside it can be walked between in a straight line
+type NavPolygon struct {
+	Id int
+
+	// The polygon is an axis aligned rectangle
+	BBox physics.BBox
+
+	// The edges shared with neighbouring polygons
+	Portals []Portal
+
+	// The cells covered by the polygon
+	minCell Cell
+	maxCell Cell
+}
+
+// Returns the vertices of the polygon clockwise
+// from the top left
+func (p *NavPolygon) Vertices() []physics.Vector {
+	return []physics.Vector{
+		p.BBox.TopLeft,
+		{X: p.BBox.BottomRight.X, Y: p.BBox.TopLeft.Y},
+		p.BBox.BottomRight,
+		{X: p.BBox.TopLeft.X, Y: p.BBox.BottomRight.Y},
+	}
+}
+
+// Returns the centre of the polygon
+func (p *NavPolygon) Centre() physics.Vector {
+	return physics.MidPoint(p.BBox.TopLeft, p.BBox.BottomRight)
+}
+
+// Returns whether the point is inside the polygon
+func (p *NavPolygon) Contains(point physics.Vector) bool {
+	return point.X >= p.BBox.TopLeft.X && point.X <= p.BBox.BottomRight.X &&
+		point.Y >= p.BBox.TopLeft.Y && point.Y <= p.BBox.BottomRight.Y
+}
+
+// An edge shared by two polygons
+type Portal struct {
+	// The id of the polygon on the other side
+	Neighbour int
+
+	// The ends of the shared edge
+	A physics.Vector
+	B physics.Vector
+}
+
+// A shape carved out of the mesh
+type navObstacle struct {
+	position physics.Vector
+	shape    physics.Shape
+}
+
+// Creates a navigation mesh where the
+// whole of the bounds is walkable
+func NewNavMesh(config NavMeshConfig) *NavMesh {
+	if config.CellSize <= 0 {
+		config.CellSize = DefaultCellSize
+	}
+	m := &NavMesh{
+		config:    config,
+		obstacles: map[int]navObstacle{},
+	}
+	m.rebuild()
+	return m
+}
+
+// Creates a navigation mesh of the bounds minus
+// the static, non sensor bodies in the world
+func NewNavMeshFromWorld(world *physics.World, config NavMeshConfig) *NavMesh {
+	m := NewNavMesh(config)
+	for _, body := range world.Bodies() {
+		if body.Static && !body.Sensor {
+			m.static = append(m.static, navObstacle{position: body.Position, shape: body.Shape})
+		}
+	}
+	m.rebuild()
+	return m
+}
+
+// A mesh of convex polygons covering the walkable area.
+// Paths are found by A* over the polygons and smoothed
+// with the funnel algorithm so agents walk in straight
+// lines past corners instead of along grid cells.
+// The polygons are axis aligned rectangles split along
+// the edges of the obstacles grown by the agent radius.
+// Physics shapes are axis aligned rectangles and circles,
+// so rectangles are followed exactly while circles are
+// outlined with bands of rectangles. Grown rectangles keep
+// square corners so agents stay a little further from them.
+// Changes to obstacles are rebuilt together on the
+// next query, so moving many obstacles costs one rebuild
+type NavMesh struct {
+	config NavMeshConfig
+
+	// The static bodies of the world
+	static []navObstacle
+
+	// The cells with obstacles carved
+	grid *meshGrid
+
+	obstacles map[int]navObstacle
+
+	obstacleIncrement int
+
+	// Whether the obstacles changed
+	// since the polygons were built
+	dirty bool
+
+	polygons []*NavPolygon
+
+	// The polygon id of each cell. -1 if blocked
+	cellPolygons []int
+}
+
+// Returns the polygons of the mesh
+func (m *NavMesh) Polygons() []*NavPolygon {
+	m.update()
+	return m.polygons
+}
+
+// Carves the shape out of the mesh and returns
+// an id used to move or remove it
+func (m *NavMesh) AddObstacle(position physics.Vector, shape physics.Shape) int {
+	m.obstacleIncrement++
+	m.obstacles[m.obstacleIncrement] = navObstacle{position: position, shape: shape}
+	m.dirty = true
+	return m.obstacleIncrement
+}
+
+// Moves a carved obstacle. Does nothing if the
+// obstacle doesn't exist
+func (m *NavMesh) MoveObstacle(id int, position physics.Vector) {
+	obstacle, exists := m.obstacles[id]
+	if !exists {
+		return
+	}
+	obstacle.position = position
+	m.obstacles[id] = obstacle
+	m.dirty = true
+}
+
+// Removes a carved obstacle so the area
+// can be walked on again
+func (m *NavMesh) RemoveObstacle(id int) {
+	if _, exists := m.obstacles[id]; !exists {
+		return
+	}
+	delete(m.obstacles, id)
+	m.dirty = true
+}
+
+// Rebuilds the polygons if the obstacles changed
+func (m *NavMesh) update() {
+	if m.dirty {
+		m.rebuild()
+	}
+}
+
+// Rebuilds the polygons from the walkable cells
+func (m *NavMesh) rebuild() {
+	m.dirty = false
+	rects := []physics.BBox{}
+	for _, o := range m.static {
+		rects = append(rects, obstacleRects(o.position, o.shape, m.config.AgentRadius, m.config.CellSize)...)
+	}
+	for _, o := range m.obstacles {
+		rects = append(rects, obstacleRects(o.position, o.shape, m.config.AgentRadius, m.config.CellSize)...)
+	}
+	m.grid = newMeshGrid(m.config.Bounds, rects)
+	m.mergeCells()
+	m.findPortals()
+}
+
+// Greedily merges the walkable cells into rectangles
+func (m *NavMesh) mergeCells() {
+	g := m.grid
+	m.polygons = []*NavPolygon{}
+	m.cellPolygons = make([]int, g.width()*g.height())
+	for i := range m.cellPolygons {
+		m.cellPolygons[i] = -1
+	}
+	free := func(x, y int) bool {
+		return g.isWalkable(Cell{X: x, Y: y}) && m.cellPolygons[y*g.width()+x] == -1
+	}
+
+	for y := 0; y < g.height(); y++ {
+		for x := 0; x < g.width(); x++ {
+			if !free(x, y) {
+				continue
+			}
+			// Grow right then down
+			maxX := x
+			for free(maxX+1, y) {
+				maxX++
+			}
+			maxY := y
+		grow:
+			for {
+				for cx := x; cx <= maxX; cx++ {
+					if !free(cx, maxY+1) {
+						break grow
+					}
+				}
+				maxY++
+			}
+
+			id := len(m.polygons)
+			for cy := y; cy <= maxY; cy++ {
+				for cx := x; cx <= maxX; cx++ {
+					m.cellPolygons[cy*g.width()+cx] = id
+				}
+			}
+			m.polygons = append(m.polygons, &NavPolygon{
+				Id: id,
+				BBox: physics.BBox{
+					TopLeft:     g.cellBBox(Cell{X: x, Y: y}).TopLeft,
+					BottomRight: g.cellBBox(Cell{X: maxX, Y: maxY}).BottomRight,
+				},
+				Portals: []Portal{},
+				minCell: Cell{X: x, Y: y},
+				maxCell: Cell{X: maxX, Y: maxY},
+			})
+		}
+	}
+}
+
+// Returns the polygon id of the cell. -1 if none
+func (m *NavMesh) polygonOfCell(c Cell) int {
+	if !m.grid.inBounds(c) {
+		return -1
+	}
+	return m.cellPolygons[c.Y*m.grid.width()+c.X]
+}
+
+// The cells just outside one side of a polygon
+type meshSide struct {
+	cells []Cell
+	// Returns the ends of the edge of an outside
+	// cell that touches the polygon
+	edge func(cellBounds physics.BBox) (physics.Vector, physics.Vector)
+}
+
+// Finds the shared edges between polygons by walking
+// along the outside of every polygon
+func (m *NavMesh) findPortals() {
+	g := m.grid
+	for _, p := range m.polygons {
+		var top, bottom, left, right []Cell
+		for x := p.minCell.X; x <= p.maxCell.X; x++ {
+			top = append(top, Cell{X: x, Y: p.minCell.Y - 1})
+			bottom = append(bottom, Cell{X: x, Y: p.maxCell.Y + 1})
+		}
+		for y := p.minCell.Y; y <= p.maxCell.Y; y++ {
+			left = append(left, Cell{X: p.minCell.X - 1, Y: y})
+			right = append(right, Cell{X: p.maxCell.X + 1, Y: y})
+		}
+		sides := []meshSide{
+			{top, func(b physics.BBox) (physics.Vector, physics.Vector) {
+				return physics.Vector{X: b.TopLeft.X, Y: b.BottomRight.Y}, b.BottomRight
+			}},
+			{bottom, func(b physics.BBox) (physics.Vector, physics.Vector) {
+				return b.TopLeft, physics.Vector{X: b.BottomRight.X, Y: b.TopLeft.Y}
+			}},
+			{left, func(b physics.BBox) (physics.Vector, physics.Vector) {
+				return physics.Vector{X: b.BottomRight.X, Y: b.TopLeft.Y}, b.BottomRight
+			}},
+			{right, func(b physics.BBox) (physics.Vector, physics.Vector) {
+				return b.TopLeft, physics.Vector{X: b.TopLeft.X, Y: b.BottomRight.Y}
+			}},
+		}
+
+		// Join runs of cells of the same neighbour into one portal
+		for _, side := range sides {
+			for i := 0; i < len(side.cells); {
+				neighbour := m.polygonOfCell(side.cells[i])
+				j := i
+				for j+1 < len(side.cells) && m.polygonOfCell(side.cells[j+1]) == neighbour {
+					j++
+				}
+				if neighbour != -1 {
+					a, _ := side.edge(g.cellBBox(side.cells[i]))
+					_, b := side.edge(g.cellBBox(side.cells[j]))
+					p.Portals = append(p.Portals, Portal{Neighbour: neighbour, A: a, B: b})
+				}
+				i = j + 1
+			}
+		}
+	}
+}
+
+// Returns the polygon containing the point
+func (m *NavMesh) PolygonAt(point physics.Vector) (*NavPolygon, bool) {
+	m.update()
+	id := m.polygonOfCell(m.grid.worldToCell(point))
+	if id == -1 {
+		return nil, false
+	}
+	return m.polygons[id], true
+}
+
+// How far inside polygons nearest points are kept
+const edgeInset = 1e-6
+
+// Returns the polygon nearest to the point
+// and the nearest point inside it
+func (m *NavMesh) NearestPolygon(point physics.Vector) (*NavPolygon, physics.Vector, bool) {
+	if p, exists := m.PolygonAt(point); exists {
+		return p, point, true
+	}
+	var nearest *NavPolygon
+	nearestPoint := point
+	nearestDist := math.Inf(1)
+	for _, p := range m.polygons {
+		// Keep off the bottom right edges as
+		// they belong to the next cells
+		inside := physics.BBox{
+			TopLeft:     p.BBox.TopLeft,
+			BottomRight: p.BBox.BottomRight.Subtract(physics.Vector{X: edgeInset, Y: edgeInset}),
+		}
+		clamped := point.Clamp(inside)
+		if dist := clamped.DistanceSquaredTo(point); dist < nearestDist {
+			nearest = p
+			nearestPoint = clamped
+			nearestDist = dist
+		}
+	}
+	return nearest, nearestPoint, nearest != nil
+}
+
+// Returns whether the point can be walked on
+func (m *NavMesh) IsWalkable(point physics.Vector) bool {
+	_, exists := m.PolygonAt(point)
+	return exists
+}
+
+// A polygon waiting to be searched
+type polygonNode struct {
+	id       int
+	priority float64
+}
+
+type polygonHeap []polygonNode
+
+func (h polygonHeap) Len() int           { return len(h) }
+func (h polygonHeap) Less(i, j int) bool { return h[i].priority < h[j].priority }
+func (h polygonHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *polygonHeap) Push(x any)        { *h = append(*h, x.(polygonNode)) }
+func (h *polygonHeap) Pop() any {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
+
+// Finds the polygons to walk through with A*. Polygons are
+// entered at the middle of portals. Returns the portals
+// crossed in order
+func (m *NavMesh) findCorridor(start *NavPolygon, startPoint physics.Vector, end *NavPolygon, endPoint physics.Vector) ([]Portal, bool) {
+	costs := map[int]float64{start.Id: 0}
+	entries := map[int]physics.Vector{start.Id: startPoint}
+	cameFrom := map[int]Portal{}
+	parents := map[int]int{}
+	closed := map[int]bool{}
+	open := &polygonHeap{{id: start.Id, priority: startPoint.DistanceTo(endPoint)}}
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(polygonNode).id
+		if closed[current] {
+			continue
+		}
+		closed[current] = true
+		if current == end.Id {
+			portals := []Portal{}
+			for current != start.Id {
+				portals = append([]Portal{cameFrom[current]}, portals...)
+				current = parents[current]
+			}
+			return portals, true
+		}
+		for _, portal := range m.polygons[current].Portals {
+			if closed[portal.Neighbour] {
+				continue
+			}
+			entry := physics.MidPoint(portal.A, portal.B)
+			cost := costs[current] + entries[current].DistanceTo(entry)
+			if existing, seen := costs[portal.Neighbour]; seen && existing <= cost {
+				continue
+			}
+			costs[portal.Neighbour] = cost
+			entries[portal.Neighbour] = entry
+			cameFrom[portal.Neighbour] = portal
+			parents[portal.Neighbour] = current
+			heap.Push(open, polygonNode{
+				id:       portal.Neighbour,
+				priority: cost + entry.DistanceTo(endPoint),
+			})
+		}
+	}
+	return nil, false
+}
+
+// Finds the shortest path between the points. Returns
+// the corners to walk through ending at the end point,
+// without the start. A start outside the mesh starts from
+// the nearest point in the mesh. Returns false if there is
+// no path or the end isn't in the mesh
+func (m *NavMesh) FindPath(start, end physics.Vector) ([]physics.Vector, bool) {
+	endPolygon, exists := m.PolygonAt(end)
+	if !exists {
+		return nil, false
+	}
+	startPolygon, startPoint, exists := m.NearestPolygon(start)
+	if !exists {
+		return nil, false
+	}
+	portals, found := m.findCorridor(startPolygon, startPoint, endPolygon, end)
+	if !found {
+		return nil, false
+	}
+
+	path := []physics.Vector{}
+	if startPoint != start {
+		path = append(path, startPoint)
+	}
+	return append(path, funnel(startPoint, end, startPolygon, portals, m.polygons)...), true
+}
+
+// Twice the signed area of the triangle. Positive
+// if c is to the right of the line from a to b
+func triarea2(a, b, c physics.Vector) float64 {
+	return (c.X-a.X)*(b.Y-a.Y) - (b.X-a.X)*(c.Y-a.Y)
+}
+
+// Smooths the path through the portals with the simple
+// stupid funnel algorithm. Returns the corners of the
+// path and the end, without the start
+func funnel(start, end physics.Vector, startPolygon *NavPolygon, portals []Portal, polygons []*NavPolygon) []physics.Vector {
+	// Orders the ends of each portal into left and
+	// right as seen walking through it
+	lefts := []physics.Vector{start}
+	rights := []physics.Vector{start}
+	from := startPolygon.Centre()
+	for _, p := range portals {
+		mid := physics.MidPoint(p.A, p.B)
+		if triarea2(from, mid, p.A) > 0 {
+			lefts = append(lefts, p.B)
+			rights = append(rights, p.A)
+		} else {
+			lefts = append(lefts, p.A)
+			rights = append(rights, p.B)
+		}
+		from = polygons[p.Neighbour].Centre()
+	}
+	lefts = append(lefts, end)
+	rights = append(rights, end)
+
+	path := []physics.Vector{}
+	apex, portalLeft, portalRight := start, start, start
+	apexIndex, leftIndex, rightIndex := 0, 0, 0
+	for i := 1; i < len(lefts); i++ {
+		left, right := lefts[i], rights[i]
+
+		// Tighten the right side
+		if triarea2(apex, portalRight, right) <= 0 {
+			if apex == portalRight || triarea2(apex, portalLeft, right) > 0 {
+				portalRight = right
+				rightIndex = i
+			} else {
+				// Right crossed over left so left is a corner
+				path = append(path, portalLeft)
+				apex = portalLeft
+				apexIndex = leftIndex
+				portalLeft, portalRight = apex, apex
+				leftIndex, rightIndex = apexIndex, apexIndex
+				i = apexIndex
+				continue
+			}
+		}
+
+		// Tighten the left side
+		if triarea2(apex, portalLeft, left) >= 0 {
+			if apex == portalLeft || triarea2(apex, portalRight, left) < 0 {
+				portalLeft = left
+				leftIndex = i
+			} else {
+				// Left crossed over right so right is a corner
+				path = append(path, portalRight)
+				apex = portalRight
+				apexIndex = rightIndex
+				portalLeft, portalRight = apex, apex
+				leftIndex, rightIndex = apexIndex, apexIndex
+				i = apexIndex
+				continue
+			}
+		}
+	}
+	if len(path) == 0 || path[len(path)-1] != end {
+		path = append(path, end)
+	}
+	return path
+}
@@ -0,0 +1,180 @@
+package navigation
+
+import (
+	"testing"
+
+	"github.com/ashleycheung/go-game/physics"
+)
+
+// Returns whether the straight lines of the
+// path only go through the mesh
+func meshPathIsWalkable(m *NavMesh, start physics.Vector, path []physics.Vector) bool {
+	for _, p := range path {
+		steps := int(start.DistanceTo(p)) + 1
+		for i := 0; i <= steps; i++ {
+			point := start.Add(p.Subtract(start).Scale(float64(i) / float64(steps)))
+			// Points exactly on the bottom or right edge
+			// of the mesh are in the next cell
+			nudged := point.Subtract(physics.Vector{X: 0.01, Y: 0.01})
+			if !m.IsWalkable(point) && !m.IsWalkable(nudged) {
+				return false
+			}
+		}
+		start = p
+	}
+	return true
+}
+
+func TestNavMeshOpen(t *testing.T) {
+	m := NewNavMesh(NavMeshConfig{
+		Bounds:   physics.BBox{BottomRight: physics.Vector{X: 100, Y: 100}},
+		CellSize: 10,
+	})
+	if len(m.Polygons()) != 1 {
+		t.Fatal("expected the open area to be one polygon", len(m.Polygons()))
+	}
+	end := physics.Vector{X: 90, Y: 80}
+	path, found := m.FindPath(physics.Vector{X: 5, Y: 5}, end)
+	if !found || len(path) != 1 || path[0] != end {
+		t.Error("expected a straight path", path)
+	}
+}
+
+func TestNavMeshFromWorld(t *testing.T) {
+	world := physics.NewWorld()
+	wall := physics.NewBody(physics.Rectangle{Size: physics.Vector{X: 20, Y: 160}})
+	wall.Position = physics.Vector{X: 100, Y: 80}
+	wall.Static = true
+	world.AddBody(wall)
+
+	m := NewNavMeshFromWorld(world, NavMeshConfig{
+		Bounds:      physics.BBox{BottomRight: physics.Vector{X: 200, Y: 200}},
+		CellSize:    5,
+		AgentRadius: 5,
+	})
+	start := physics.Vector{X: 50, Y: 20}
+	end := physics.Vector{X: 150, Y: 20}
+	path, found := m.FindPath(start, end)
+	if !found {
+		t.Fatal("expected a path")
+	}
+	// The wall ends at 160 and is grown by 5 so the
+	// path turns at the two corners under it
+	if len(path) != 3 {
+		t.Error("expected a path around the two corners", path)
+	}
+	for _, corner := range path[:2] {
+		if corner.Y != 165 {
+			t.Error("expected the corners at the bottom of the wall", path)
+		}
+	}
+	if !meshPathIsWalkable(m, start, path) {
+		t.Error("path goes outside the mesh", path)
+	}
+
+	// Smoothed paths are shorter than grid paths
+	grid := NewGridFromWorld(world, physics.BBox{BottomRight: physics.Vector{X: 200, Y: 200}}, 5, 5)
+	gridPath, _ := grid.FindPath(start, end)
+	if pathLength(start, path) >= pathLength(start, gridPath) {
+		t.Error("expected the mesh path to be shorter", pathLength(start, path), pathLength(start, gridPath))
+	}
+
+	if _, found := m.FindPath(start, physics.Vector{X: 100, Y: 50}); found {
+		t.Error("expected no path into the wall")
+	}
+}
+
+func TestNavMeshCarving(t *testing.T) {
+	m := NewNavMesh(NavMeshConfig{
+		Bounds:      physics.BBox{BottomRight: physics.Vector{X: 100, Y: 100}},
+		CellSize:    5,
+		AgentRadius: 5,
+	})
+	start := physics.Vector{X: 10, Y: 50}
+	end := physics.Vector{X: 90, Y: 50}
+
+	// A box carved in the way
+	id := m.AddObstacle(physics.Vector{X: 50, Y: 50}, physics.Rectangle{Size: physics.Vector{X: 20, Y: 20}})
+	if m.IsWalkable(physics.Vector{X: 50, Y: 50}) {
+		t.Error("expected the obstacle to be carved")
+	}
+	path, found := m.FindPath(start, end)
+	if !found || len(path) != 3 || !meshPathIsWalkable(m, start, path) {
+		t.Error("expected a path around the obstacle", path)
+	}
+
+	// Moved out of the way
+	m.MoveObstacle(id, physics.Vector{X: 50, Y: 10})
+	path, _ = m.FindPath(start, end)
+	if len(path) != 1 {
+		t.Error("expected a straight path", path)
+	}
+
+	m.RemoveObstacle(id)
+	if !m.IsWalkable(physics.Vector{X: 50, Y: 10}) {
+		t.Error("expected the area to be walkable again")
+	}
+
+	// Walled off completely
+	m.AddObstacle(physics.Vector{X: 50, Y: 50}, physics.Rectangle{Size: physics.Vector{X: 10, Y: 100}})
+	if _, found := m.FindPath(start, end); found {
+		t.Error("expected no path")
+	}
+}
+
+// Obstacle changes are only rebuilt once queried
+func TestNavMeshBatchedObstacles(t *testing.T) {
+	m := NewNavMesh(NavMeshConfig{
+		Bounds:   physics.BBox{BottomRight: physics.Vector{X: 100, Y: 100}},
+		CellSize: 5,
+	})
+	polygons := m.polygons
+	ids := []int{}
+	for i := 0; i < 5; i++ {
+		ids = append(ids, m.AddObstacle(physics.Vector{X: float64(i * 20), Y: 50}, physics.Circle{Radius: 5}))
+	}
+	for _, id := range ids {
+		m.MoveObstacle(id, physics.Vector{X: 50, Y: 10})
+	}
+	if !m.dirty || len(m.polygons) != len(polygons) || &m.polygons[0] != &polygons[0] {
+		t.Fatal("expected the rebuild to wait for a query")
+	}
+	if m.IsWalkable(physics.Vector{X: 50, Y: 10}) || !m.IsWalkable(physics.Vector{X: 0, Y: 50}) {
+		t.Error("expected the obstacles carved where they moved")
+	}
+	if m.dirty {
+		t.Error("expected the mesh rebuilt by the query")
+	}
+}
+
+// Polygons follow the obstacle edges
+// instead of the cell size
+func TestNavMeshFollowsObstacles(t *testing.T) {
+	m := NewNavMesh(NavMeshConfig{
+		Bounds:      physics.BBox{BottomRight: physics.Vector{X: 100, Y: 100}},
+		CellSize:    10,
+		AgentRadius: 1,
+	})
+	// Covers 32 to 48 once grown
+	m.AddObstacle(physics.Vector{X: 40, Y: 50}, physics.Rectangle{Size: physics.Vector{X: 14, Y: 14}})
+	if !m.IsWalkable(physics.Vector{X: 31.9, Y: 50}) || m.IsWalkable(physics.Vector{X: 32.1, Y: 50}) {
+		t.Error("expected the left edge followed exactly")
+	}
+	if m.IsWalkable(physics.Vector{X: 47.9, Y: 50}) || !m.IsWalkable(physics.Vector{X: 48, Y: 50}) {
+		t.Error("expected the right edge followed exactly")
+	}
+
+	// Circles are covered by bands no taller than the cell size
+	m = NewNavMesh(NavMeshConfig{
+		Bounds:      physics.BBox{BottomRight: physics.Vector{X: 100, Y: 100}},
+		CellSize:    2,
+		AgentRadius: 1,
+	})
+	m.AddObstacle(physics.Vector{X: 80, Y: 20}, physics.Circle{Radius: 9})
+	if m.IsWalkable(physics.Vector{X: 80, Y: 20}) || m.IsWalkable(physics.Vector{X: 89.9, Y: 20}) {
+		t.Error("expected the circle blocked")
+	}
+	if !m.IsWalkable(physics.Vector{X: 87, Y: 11}) || !m.IsWalkable(physics.Vector{X: 80, Y: 30.1}) {
+		t.Error("expected outside the circle to be walkable")
+	}
+}
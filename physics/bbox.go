@@ -15,3 +15,12 @@ func (b BBox) ToSizePosition() (size, position Vector) {
 	position = b.TopLeft.Add(size.Scale(0.5))
 	return
 }
+
+// Returns whether the bboxes overlap.
+// Touching edges count as overlapping
+func (b BBox) Overlaps(other BBox) bool {
+	return b.TopLeft.X <= other.BottomRight.X &&
+		b.BottomRight.X >= other.TopLeft.X &&
+		b.TopLeft.Y <= other.BottomRight.Y &&
+		b.BottomRight.Y >= other.TopLeft.Y
+}
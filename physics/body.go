@@ -18,6 +18,9 @@ func NewBody(shape Shape) *Body {
 		DragCoefficient:  1,
 		CollisionBodyIds: map[int]bool{},
 	}
+	// A failing listener shouldn't stop the others
+	newBody.event.SetErrorPolicy(event.ContinueAndAggregate)
+	newBody.event.SetRecoverPanics(true)
 	return &newBody
 }
 
@@ -117,6 +120,22 @@ func (b *Body) Step(delta float64) {
 	b.Position = b.Position.Add(b.Velocity.Scale(delta / 1000))
 }
 
+// Returns the bbox of the body's shape
+func (b *Body) GetBBox() BBox {
+	switch b.Shape.GetType() {
+	case CircleType:
+		circle := b.Shape.(Circle)
+		half := Vector{X: circle.Radius, Y: circle.Radius}
+		return BBox{
+			TopLeft:     b.Position.Subtract(half),
+			BottomRight: b.Position.Add(half),
+		}
+	case RectangleType:
+		return RectToBBox(b.Position, b.Shape.(Rectangle))
+	}
+	panic("unsupported type " + b.Shape.GetType())
+}
+
 // Makes a deep clone of the given body
 // with the exact same id
 func (b *Body) Clone() *Body {
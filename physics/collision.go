@@ -16,99 +16,130 @@ type Collision struct {
 	B2 *Body
 }
 
+// The number of bodies each worker checks
+// at a time during the narrow phase
+const narrowPhaseChunkSize = 64
+
 // Returns all pairs of body collision within
 // the world. When two shapes just touch
 // on the edge they are considered colliding.
+// If the world has a worker pool, the narrow phase
+// is split across the workers. The collision events
+// are emitted before returning unless the world has
+// a queue set with SetEventQueue
 func FindCollisions(w *World) []Collision {
+	collisions := findCollisions(w)
+	w.emitCollisionEvents(collisions)
+	return collisions
+}
+
+// Finds the collisions without emitting their events
+func findCollisions(w *World) []Collision {
 	bodies := w.Bodies()
 
 	// Build quadtree
 	w.QuadTree = NewQuadTreeFromBodies(bodies, w.QuadTree.splitAmount, w.QuadTree.maxDepth)
 
-	outCollisions := []Collision{}
-
-	// Compare all bodies
-	for i := 0; i < len(bodies); i++ {
-		// Get neighbours
-		body1 := bodies[i]
-		neighbours := w.QuadTree.GetNeighbours(body1)
-
-		// Check if colliding with neighbours
-		for _, body2 := range neighbours {
-			// If they already have collided ignore
-			if body1.CollisionBodyIds[body2.Id] {
-				continue
-			}
-
-			body1ShapeType := body1.Shape.GetType()
-			body2ShapeType := body2.Shape.GetType()
-			var doesCollide bool
-
-			// Pass to the correct shape collision detector
-			if body1ShapeType == CircleType && body2ShapeType == CircleType {
-				b1Circle := body1.Shape.(Circle)
-				b2Circle := body2.Shape.(Circle)
-				doesCollide = CircleCircleCollision(
-					b1Circle.Radius,
-					body1.Position,
-					b2Circle.Radius,
-					body2.Position)
-			} else if body1ShapeType == RectangleType && body2ShapeType == RectangleType {
-				b1Rect := body1.Shape.(Rectangle)
-				b2Rect := body2.Shape.(Rectangle)
-				doesCollide = RectangleRectangleCollision(
-					b1Rect.Size,
-					body1.Position,
-					b2Rect.Size,
-					body2.Position)
-			} else if body1ShapeType == CircleType && body2ShapeType == RectangleType {
-				circle := body1.Shape.(Circle)
-				rect := body2.Shape.(Rectangle)
-				doesCollide = CircleRectangleCollision(circle.Radius, body1.Position, rect.Size, body2.Position)
-			} else if body1ShapeType == RectangleType && body2ShapeType == CircleType {
-				rect := body1.Shape.(Rectangle)
-				circle := body2.Shape.(Circle)
-				doesCollide = CircleRectangleCollision(circle.Radius, body2.Position, rect.Size, body1.Position)
-			} else {
-				panic(fmt.Sprintf("collisions between %s and %s not supported", body1ShapeType, body2ShapeType))
+	// Each pair is only checked by the body with
+	// the lower id so no two workers write the same pair
+	findBodyCollisions := func(start, end int) []Collision {
+		collisions := []Collision{}
+		for i := start; i < end; i++ {
+			body1 := bodies[i]
+			// Get neighbours
+			for _, body2 := range w.QuadTree.GetNeighbours(body1) {
+				if body1.Id >= body2.Id {
+					continue
+				}
+				if DoBodiesCollide(body1, body2) {
+					collisions = append(collisions, Collision{
+						B1: body1, B2: body2})
+				}
 			}
+		}
+		return collisions
+	}
 
-			// If collision occurs
-			// create a collision
-			if doesCollide {
-				// Add bodies to their respective ids
-				body1.CollisionBodyIds[body2.Id] = true
-				body2.CollisionBodyIds[body1.Id] = true
-
-				// Add to out collisions
-				outCollisions = append(outCollisions, Collision{
-					B1: body1, B2: body2})
-			}
+	outCollisions := []Collision{}
+	if w.workerPool == nil {
+		outCollisions = findBodyCollisions(0, len(bodies))
+	} else {
+		// Each chunk writes to its own slice which
+		// are joined in order once all are done
+		chunkCollisions := make([][]Collision, (len(bodies)+narrowPhaseChunkSize-1)/narrowPhaseChunkSize)
+		w.workerPool.ParallelFor(len(bodies), narrowPhaseChunkSize, func(start, end int) {
+			chunkCollisions[start/narrowPhaseChunkSize] = findBodyCollisions(start, end)
+		})
+		for _, collisions := range chunkCollisions {
+			outCollisions = append(outCollisions, collisions...)
 		}
 	}
 
-	// For each pair of collisions
-	// call the event
+	// Add bodies to their respective ids
 	for _, c := range outCollisions {
-		// Call b1 event
-		c.B1.GetEvent().EmitEvent(event.Event[PhysicsBodyEvent]{
-			Name: BodyCollideEvent,
-			Data: BodyCollideEventData{
-				TargetBody: c.B2,
-			},
-		})
+		c.B1.CollisionBodyIds[c.B2.Id] = true
+		c.B2.CollisionBodyIds[c.B1.Id] = true
+	}
 
-		// Call b2 event
-		c.B2.GetEvent().EmitEvent(event.Event[PhysicsBodyEvent]{
+	return outCollisions
+}
+
+// Posts the collision event of both bodies of each
+// collision and emits them once all are posted
+// unless the queue is flushed by its owner
+func (w *World) emitCollisionEvents(collisions []Collision) {
+	// For each pair of collisions
+	// post the event
+	for _, c := range collisions {
+		// Post b1 event
+		w.reportError(event.PostTo(w.eventQueue, c.B1.GetEvent(), event.Event[PhysicsBodyEvent]{
 			Name: BodyCollideEvent,
-			Data: BodyCollideEventData{
-				TargetBody: c.B1,
-			},
-		})
+			Data: BodyCollideEventData{TargetBody: c.B2},
+		}))
 
+		// Post b2 event
+		w.reportError(event.PostTo(w.eventQueue, c.B2.GetEvent(), event.Event[PhysicsBodyEvent]{
+			Name: BodyCollideEvent,
+			Data: BodyCollideEventData{TargetBody: c.B1},
+		}))
+	}
+	if !w.externalQueue {
+		w.reportError(w.eventQueue.Flush())
 	}
+}
 
-	return outCollisions
+// Returns whether two bodies collide by passing
+// them to the correct shape collision detector
+func DoBodiesCollide(body1, body2 *Body) bool {
+	body1ShapeType := body1.Shape.GetType()
+	body2ShapeType := body2.Shape.GetType()
+
+	if body1ShapeType == CircleType && body2ShapeType == CircleType {
+		b1Circle := body1.Shape.(Circle)
+		b2Circle := body2.Shape.(Circle)
+		return CircleCircleCollision(
+			b1Circle.Radius,
+			body1.Position,
+			b2Circle.Radius,
+			body2.Position)
+	} else if body1ShapeType == RectangleType && body2ShapeType == RectangleType {
+		b1Rect := body1.Shape.(Rectangle)
+		b2Rect := body2.Shape.(Rectangle)
+		return RectangleRectangleCollision(
+			b1Rect.Size,
+			body1.Position,
+			b2Rect.Size,
+			body2.Position)
+	} else if body1ShapeType == CircleType && body2ShapeType == RectangleType {
+		circle := body1.Shape.(Circle)
+		rect := body2.Shape.(Rectangle)
+		return CircleRectangleCollision(circle.Radius, body1.Position, rect.Size, body2.Position)
+	} else if body1ShapeType == RectangleType && body2ShapeType == CircleType {
+		rect := body1.Shape.(Rectangle)
+		circle := body2.Shape.(Circle)
+		return CircleRectangleCollision(circle.Radius, body2.Position, rect.Size, body1.Position)
+	}
+	panic(fmt.Sprintf("collisions between %s and %s not supported", body1ShapeType, body2ShapeType))
 }
 
 // Returns whether there is a collision between
@@ -2,6 +2,9 @@ package physics
 
 import (
 	"testing"
+
+	"github.com/ashleycheung/go-game/event"
+	"github.com/ashleycheung/go-game/utils"
 )
 
 func TestCollision(t *testing.T) {
@@ -151,3 +154,99 @@ func TestRectangleRectangleCollision3(t *testing.T) {
 		t.Fatalf("Should collide")
 	}
 }
+
+// Finding collisions with workers should
+// give the same result as without
+func TestParallelCollision(t *testing.T) {
+	w := NewWorld()
+	for i := 0; i < 500; i++ {
+		b := NewBody(Circle{Radius: 3})
+		b.Position = Vector{X: float64(i%25) * 5, Y: float64(i/25) * 5}
+		w.AddBody(b)
+	}
+	serial := FindCollisions(w)
+
+	for _, b := range w.Bodies() {
+		b.CollisionBodyIds = map[int]bool{}
+	}
+	pool := utils.NewWorkerPool(4)
+	defer pool.Close()
+	w.SetWorkerPool(pool)
+	parallel := FindCollisions(w)
+
+	if len(serial) == 0 || len(serial) != len(parallel) {
+		t.Fatal("wrong number of collisions", len(serial), len(parallel))
+	}
+	pairs := map[[2]int]bool{}
+	for _, c := range serial {
+		pairs[[2]int{c.B1.Id, c.B2.Id}] = true
+	}
+	for _, c := range parallel {
+		if !pairs[[2]int{c.B1.Id, c.B2.Id}] {
+			t.Fatal("collision missing from serial result")
+		}
+	}
+}
+
+// Calling FindCollisions directly emits
+// the events before it returns
+func TestFindCollisionsEvents(t *testing.T) {
+	w := NewWorld()
+	b1 := NewBody(Circle{2})
+	w.AddBody(b1)
+	b2 := NewBody(Circle{2})
+	b2.Position = Vector{1, 0}
+	w.AddBody(b2)
+
+	hits := []*Body{}
+	b1.GetEvent().AddListener(BodyCollideEvent, func(e event.Event[PhysicsBodyEvent]) error {
+		hits = append(hits, e.Data.(BodyCollideEventData).TargetBody)
+		return nil
+	})
+	FindCollisions(w)
+	if len(hits) != 1 || hits[0] != b2 {
+		t.Fatal("expected the collision event emitted", hits)
+	}
+
+	// Step emits each collision once
+	hits = hits[:0]
+	w.Step(0)
+	if len(hits) != 1 {
+		t.Error("expected a single event from the step", len(hits))
+	}
+}
+
+// Collision events wait in a set queue until it is flushed
+func TestSetEventQueue(t *testing.T) {
+	w := NewWorld()
+	q := event.NewQueue(0)
+	w.SetEventQueue(q)
+	b1 := NewBody(Circle{2})
+	w.AddBody(b1)
+	b2 := NewBody(Circle{2})
+	b2.Position = Vector{1, 0}
+	w.AddBody(b2)
+
+	hits := 0
+	b1.GetEvent().AddListener(BodyCollideEvent, func(e event.Event[PhysicsBodyEvent]) error {
+		hits++
+		return nil
+	})
+	w.Step(0)
+	if hits != 0 {
+		t.Fatal("expected the event to wait for the flush", hits)
+	}
+	if err := q.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if hits != 1 {
+		t.Fatal("expected the event emitted by the flush", hits)
+	}
+
+	// Without a set queue the step emits the events
+	w.SetEventQueue(nil)
+	w.Step(0)
+	if hits != 2 {
+		t.Error("expected the step to emit the event", hits)
+	}
+}
@@ -1,5 +1,7 @@
 package physics
 
+import "github.com/ashleycheung/go-game/event"
+
 // All the physics events
 type PhysicsBodyEvent string
 
@@ -15,4 +17,23 @@ const (
 	// Called when a step has finished
 	StepEndEvent                  PhysicsWorldEvent = "stepend"
 	BeforeCollisionDetectionEvent PhysicsWorldEvent = "beforeCollisionDetection"
+
+	// Called when listeners of the world or its
+	// bodies fail. The data is the error
+	ErrorEvent PhysicsWorldEvent = "error"
+)
+
+// Typed topics of the physics events
+var (
+	// Called when a body collides with another body
+	BodyCollideTopic = event.NewTopic[PhysicsBodyEvent, BodyCollideEventData](BodyCollideEvent)
+
+	// Called when a step has finished
+	StepEndTopic = event.NewTopic[PhysicsWorldEvent, struct{}](StepEndEvent)
+
+	// Called before collision detection
+	BeforeCollisionDetectionTopic = event.NewTopic[PhysicsWorldEvent, struct{}](BeforeCollisionDetectionEvent)
+
+	// Called when listeners of the world or its bodies fail
+	ErrorTopic = event.NewTopic[PhysicsWorldEvent, error](ErrorEvent)
 )
@@ -1,10 +1,10 @@
 package physics
 
+import "sort"
+
 const DefaultSplitAmount = 5
 const DefaultMaxDepth = 20
 
-var repeatNum int
-
 // Creates a quad tree given a slice of bodies
 func NewQuadTreeFromBodies(bodies []*Body, splitAmount, maxDepth int) *QuadTree {
 
@@ -12,26 +12,10 @@ func NewQuadTreeFromBodies(bodies []*Body, splitAmount, maxDepth int) *QuadTree
 	// currently fit in
 	region := BBox{}
 	for _, b := range bodies {
-		var bodyTopLeft Vector
-		var bodyBottomRight Vector
-
-		switch b.Shape.GetType() {
-		case CircleType:
-			circle := b.Shape.(Circle)
-			bodyTopLeft = b.Position.Subtract(Vector{
-				X: circle.Radius,
-				Y: circle.Radius,
-			})
-			bodyBottomRight = b.Position.Add(Vector{
-				X: circle.Radius,
-				Y: circle.Radius,
-			})
-		case RectangleType:
-			rect := b.Shape.(Rectangle)
-			bodyTopLeft, bodyBottomRight = RectangleCorners(b.Position, rect)
-		default:
-			panic("unsupported type " + b.Shape.GetType())
-		}
+		bodyBBox := b.GetBBox()
+		bodyTopLeft := bodyBBox.TopLeft
+		bodyBottomRight := bodyBBox.BottomRight
+
 		// Update region if necessary
 		if bodyTopLeft.X < region.TopLeft.X {
 			region.TopLeft.X = bodyTopLeft.X
@@ -132,6 +116,34 @@ func (qTree *QuadTree) GetNeighbours(body *Body) []*Body {
 	return []*Body{}
 }
 
+// Returns the bodies in the tree overlapping
+// the region, ordered by id
+func (qTree *QuadTree) QueryRegion(region BBox) []*Body {
+	found := map[*Body]bool{}
+	bodies := []*Body{}
+	var search func(node *QuadTreeNode)
+	search = func(node *QuadTreeNode) {
+		if node == nil || !node.Region.Overlaps(region) {
+			return
+		}
+		for b := range node.bodies {
+			if !found[b] && b.GetBBox().Overlaps(region) {
+				found[b] = true
+				bodies = append(bodies, b)
+			}
+		}
+		search(node.topLeft)
+		search(node.topRight)
+		search(node.bottomLeft)
+		search(node.bottomRight)
+	}
+	search(qTree.rootNode)
+	sort.Slice(bodies, func(i, j int) bool {
+		return bodies[i].Id < bodies[j].Id
+	})
+	return bodies
+}
+
 // Used by the quad tree node to cache
 // the body to a given node
 func (qTree *QuadTree) cacheBodyToNode(body *Body, node *QuadTreeNode) {
@@ -232,13 +244,28 @@ func (qNode *QuadTreeNode) AddBody(b *Body) {
 		qNode.tree.cacheBodyToNode(b, qNode)
 	}
 
-	// Split if necessary and less than depth
-	if len(qNode.bodies) >= qNode.tree.splitAmount && !qNode.hasAttemptedSplit && qNode.depth < qNode.tree.maxDepth {
-		qNode.hasAttemptedSplit = true
+	// Split if necessary and less than depth. Bodies covering
+	// the whole node would be added to every child, so
+	// splitting wouldn't separate them
+	if len(qNode.bodies) >= qNode.tree.splitAmount && !qNode.hasAttemptedSplit &&
+		qNode.depth < qNode.tree.maxDepth && !qNode.isCovered() {
 		qNode.split()
 	}
 }
 
+// Returns whether the bounding box of every
+// body in the node covers the whole region
+func (qNode *QuadTreeNode) isCovered() bool {
+	for b := range qNode.bodies {
+		bbox := b.GetBBox()
+		if bbox.TopLeft.X > qNode.Region.TopLeft.X || bbox.TopLeft.Y > qNode.Region.TopLeft.Y ||
+			bbox.BottomRight.X < qNode.Region.BottomRight.X || bbox.BottomRight.Y < qNode.Region.BottomRight.Y {
+			return false
+		}
+	}
+	return true
+}
+
 // Splits the quad tree.
 // If already split, does nothing
 func (qNode *QuadTreeNode) split() {
@@ -310,9 +337,4 @@ func (qNode *QuadTreeNode) split() {
 
 	// Clear current quadtree
 	qNode.bodies = map[*Body]bool{}
-
-	if repeatNum > 100 {
-		panic("")
-	}
-	repeatNum++
 }
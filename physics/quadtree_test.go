@@ -57,3 +57,78 @@ func TestQuadTreeInfiniteLoop(t *testing.T) {
 	}, 4, 20)
 	fmt.Println(q)
 }
+
+// Adding bodies splits the tree so each body
+// only has the bodies near it as neighbours
+func TestQuadTreeSplits(t *testing.T) {
+	bodies := []*Body{}
+	for i := 0; i < 300; i++ {
+		b := NewBody(Circle{Radius: 2})
+		b.Id = i + 1
+		b.Position = Vector{X: float64(i%20) * 50, Y: float64(i/20) * 50}
+		bodies = append(bodies, b)
+	}
+	// Enough splits to have hit the old global limit
+	for i := 0; i < 3; i++ {
+		tree := NewQuadTreeFromBodies(bodies, DefaultSplitAmount, DefaultMaxDepth)
+		if len(tree.GetBBoxes()) < 100 {
+			t.Fatal("expected the tree to split", len(tree.GetBBoxes()))
+		}
+		for _, b := range bodies {
+			if n := len(tree.GetNeighbours(b)); n >= DefaultSplitAmount*4 {
+				t.Fatal("expected the neighbours to be bounded", b.Id, n)
+			}
+		}
+	}
+}
+
+// Bodies covering each other shouldn't split forever
+func TestQuadTreeOverlapping(t *testing.T) {
+	bodies := []*Body{}
+	for i := 0; i < 50; i++ {
+		b := NewBody(Rectangle{Size: Vector{X: 1000, Y: 1000}})
+		b.Id = i + 1
+		b.Position = Vector{X: 500, Y: 500}
+		bodies = append(bodies, b)
+	}
+	tree := NewQuadTreeFromBodies(bodies, DefaultSplitAmount, DefaultMaxDepth)
+	if len(tree.GetNeighbours(bodies[0])) != 49 {
+		t.Error("expected every body to be a neighbour")
+	}
+}
+
+func TestQuadTreeQueryRegion(t *testing.T) {
+	bodies := []*Body{}
+	for i := 0; i < 100; i++ {
+		b := NewBody(Circle{Radius: 2})
+		b.Id = i + 1
+		b.Position = Vector{X: float64(i%10) * 10, Y: float64(i/10) * 10}
+		bodies = append(bodies, b)
+	}
+	tree := NewQuadTreeFromBodies(bodies, DefaultSplitAmount, DefaultMaxDepth)
+
+	found := tree.QueryRegion(BBox{
+		TopLeft:     Vector{X: 15, Y: 15},
+		BottomRight: Vector{X: 35, Y: 25},
+	})
+	// Bodies at x 20 and 30 and y 20
+	if len(found) != 2 || found[0].Id != 23 || found[1].Id != 24 {
+		t.Error("expected the 2 bodies in the region", found)
+	}
+
+	// Bodies touching the region are included
+	found = tree.QueryRegion(BBox{
+		TopLeft:     Vector{X: 12, Y: -5},
+		BottomRight: Vector{X: 18, Y: 5},
+	})
+	if len(found) != 2 || found[0].Id != 2 || found[1].Id != 3 {
+		t.Error("expected the bodies overlapping the region", found)
+	}
+
+	if found := tree.QueryRegion(BBox{
+		TopLeft:     Vector{X: 500, Y: 500},
+		BottomRight: Vector{X: 600, Y: 600},
+	}); len(found) != 0 {
+		t.Error("expected no bodies outside the tree", found)
+	}
+}
@@ -124,3 +124,16 @@ func (v Vector) Clamp(bbox BBox) Vector {
 		Y: math.Max(bbox.TopLeft.Y, math.Min(bbox.BottomRight.Y, v.Y)),
 	}
 }
+
+// Rotates the vector by the given angle in radians
+func (v Vector) Rotate(angle float64) Vector {
+	if angle == 0 {
+		return v
+	}
+	cos := math.Cos(angle)
+	sin := math.Sin(angle)
+	return Vector{
+		X: v.X*cos - v.Y*sin,
+		Y: v.X*sin + v.Y*cos,
+	}
+}
@@ -1,19 +1,29 @@
 package physics
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"sync"
 	"time"
 
+	"github.com/ashleycheung/go-game/clock"
 	"github.com/ashleycheung/go-game/event"
+	"github.com/ashleycheung/go-game/utils"
 )
 
 func NewWorld() *World {
 	w := &World{
-		bodies: map[int]*Body{},
-		Event:  event.NewEventManager[PhysicsWorldEvent](),
-		Config: DefaultWorldConfig(),
+		bodies:     map[int]*Body{},
+		Event:      event.NewEventManager[PhysicsWorldEvent](),
+		Config:     DefaultWorldConfig(),
+		clock:      clock.NewReal(),
+		eventQueue: event.NewQueue(0),
 	}
 	w.QuadTree = NewQuadTree(BBox{}, DefaultSplitAmount, DefaultMaxDepth)
+	// A failing listener shouldn't stop the others
+	w.Event.SetErrorPolicy(event.ContinueAndAggregate)
+	w.Event.SetRecoverPanics(true)
 	return w
 }
 
@@ -35,8 +45,55 @@ type World struct {
 	// Whether the world is running or not
 	running bool
 
+	// Stops the current run
+	cancelRun context.CancelFunc
+
+	// Guards running and cancelRun
+	runLock sync.Mutex
+
+	// The source of time used by Run
+	clock clock.Clock
+
 	// Saves the last quadtree in the world
 	QuadTree *QuadTree
+
+	// Splits the collision narrow phase
+	// across workers if not nil
+	workerPool *utils.WorkerPool
+
+	// Holds the collision events until
+	// the collisions are resolved
+	eventQueue *event.Queue
+
+	// Whether the event queue was set with
+	// SetEventQueue and is flushed by its owner
+	externalQueue bool
+}
+
+// Sets the queue collision events are posted to.
+// The owner of the queue must flush it to emit them.
+// If nil, the world uses its own queue and emits
+// the events before returning from Step
+func (w *World) SetEventQueue(q *event.Queue) {
+	if q == nil {
+		w.eventQueue = event.NewQueue(0)
+		w.externalQueue = false
+		return
+	}
+	w.eventQueue = q
+	w.externalQueue = true
+}
+
+// Sets the pool used to find collisions concurrently.
+// If nil, collisions are found on the calling goroutine
+func (w *World) SetWorkerPool(pool *utils.WorkerPool) {
+	w.workerPool = pool
+}
+
+// Sets the source of time used by Run.
+// Must not be called while the world is running
+func (w *World) SetClock(c clock.Clock) {
+	w.clock = c
 }
 
 // Adds a body into the world
@@ -93,13 +150,11 @@ func (w *World) Step(delta float64) {
 	}
 
 	// Called before collision detection occurs
-	w.Event.EmitEvent(event.Event[PhysicsWorldEvent]{
-		Name: BeforeCollisionDetectionEvent,
-	})
+	w.reportError(event.Emit(w.Event, BeforeCollisionDetectionTopic, struct{}{}))
 
 	// Detect collision and continue
 	// to resolve until no more collisions occur
-	collisions := FindCollisions(w)
+	collisions := findCollisions(w)
 
 	// Resolve the collisions
 	Resolve(collisions)
@@ -107,13 +162,25 @@ func (w *World) Step(delta float64) {
 	// Update the velocities from the collisions
 	ApplyMomentum(collisions)
 
+	// Collision listeners see the resolved bodies
+	w.emitCollisionEvents(collisions)
+
 	// Call step finish event
-	err := w.Event.EmitEvent(event.Event[PhysicsWorldEvent]{
-		Name: StepEndEvent,
-	})
-	if err != nil {
-		panic(err)
+	w.reportError(event.Emit(w.Event, StepEndTopic, struct{}{}))
+}
+
+// Emits the error as an ErrorEvent.
+// Logged if nothing listens for it by name
+func (w *World) reportError(err error) {
+	if err == nil {
+		return
 	}
+	if w.Event.ExactListenerCount(ErrorEvent) == 0 {
+		log.Println("physics world:", err)
+		return
+	}
+	// Errors of the error listeners aren't reported
+	event.Emit(w.Event, ErrorTopic, err)
 }
 
 // Makes a deep clone of this game world
@@ -122,6 +189,7 @@ func (w *World) Clone() *World {
 	clonedWorld.idIncrement = w.idIncrement
 	// A cloned world will be paused by default
 	clonedWorld.running = false
+	clonedWorld.clock = w.clock
 	for _, body := range w.bodies {
 		clonedWorld.bodies[body.Id] = body.Clone()
 	}
@@ -132,16 +200,28 @@ func (w *World) Clone() *World {
 // fps. If fps is -1, it runs at max possible
 // fps
 func (w *World) Run(fps int) {
-	currTime := time.Now()
+	w.runLock.Lock()
 	if w.running {
+		w.runLock.Unlock()
 		fmt.Println("can't run world as it is already running")
 		return
 	}
-
+	ctx, cancel := context.WithCancel(context.Background())
 	w.running = true
-	for w.running {
+	w.cancelRun = cancel
+	w.runLock.Unlock()
+	defer func() {
+		w.runLock.Lock()
+		defer w.runLock.Unlock()
+		w.running = false
+		w.cancelRun()
+		w.cancelRun = nil
+	}()
+
+	currTime := w.clock.Now()
+	for ctx.Err() == nil {
 		// Calculate delta
-		newTime := time.Now()
+		newTime := w.clock.Now()
 		dif := newTime.Sub(currTime)
 		delta := float64(dif.Microseconds()) / 1000.0
 		currTime = newTime
@@ -151,10 +231,15 @@ func (w *World) Run(fps int) {
 
 		// Sleep and wait for next tick
 		if fps != -1 {
-			time.Sleep(
+			sleepTimer := w.clock.NewTimer(
 				time.Duration(1000.0/float64(fps))*time.Millisecond -
 					time.Duration(delta)*time.Millisecond,
 			)
+			select {
+			case <-ctx.Done():
+				sleepTimer.Stop()
+			case <-sleepTimer.C():
+			}
 		}
 	}
 	fmt.Println("World stopped")
@@ -163,5 +248,9 @@ func (w *World) Run(fps int) {
 // Stops the world.
 // If the world isn't running, does nothing
 func (w *World) Stop() {
-	w.running = false
+	w.runLock.Lock()
+	defer w.runLock.Unlock()
+	if w.cancelRun != nil {
+		w.cancelRun()
+	}
 }
@@ -1,6 +1,15 @@
 package physics
 
-import "testing"
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ashleycheung/go-game/event"
+)
 
 func TestWorldClone(t *testing.T) {
 	w := NewWorld()
@@ -17,3 +26,90 @@ func TestWorldClone(t *testing.T) {
 		t.Error("Body was not cloned")
 	}
 }
+
+func TestWorldListenerErrors(t *testing.T) {
+	w := NewWorld()
+	errs := []error{}
+	w.Event.AddListener(ErrorEvent, func(e event.Event[PhysicsWorldEvent]) error {
+		errs = append(errs, e.Data.(error))
+		return nil
+	})
+	stepEnded := false
+	w.Event.AddListener(StepEndEvent, func(e event.Event[PhysicsWorldEvent]) error {
+		return errors.New("failed")
+	})
+	w.Event.AddListener(StepEndEvent, func(e event.Event[PhysicsWorldEvent]) error {
+		stepEnded = true
+		return nil
+	})
+
+	b1 := NewBody(Circle{Radius: 5})
+	b2 := NewBody(Circle{Radius: 5})
+	b2.Position = Vector{X: 5}
+	b1.GetEvent().AddListener(BodyCollideEvent, func(e event.Event[PhysicsBodyEvent]) error {
+		panic("buggy listener")
+	})
+	w.AddBody(b1)
+	w.AddBody(b2)
+
+	// Shouldn't panic
+	w.Step(16)
+	if !stepEnded {
+		t.Error("expected the other listeners to be called")
+	}
+	if len(errs) != 2 {
+		t.Fatal("expected both errors reported", errs)
+	}
+	var panicErr *event.ListenerPanicError
+	if !errors.As(errs[0], &panicErr) {
+		t.Error("expected the collision listener panic", errs[0])
+	}
+	if errs[1].Error() != "event stepend: failed" {
+		t.Error("expected the step end error", errs[1])
+	}
+}
+
+func TestCollisionEventsAfterResolve(t *testing.T) {
+	w := NewWorld()
+	b1 := NewBody(Circle{Radius: 5})
+	b1.Velocity = Vector{X: 1}
+	b2 := NewBody(Circle{Radius: 5})
+	b2.Position = Vector{X: 8}
+	w.AddBody(b1)
+	w.AddBody(b2)
+
+	var seenPosition, seenVelocity Vector
+	b1.GetEvent().AddListener(BodyCollideEvent, func(e event.Event[PhysicsBodyEvent]) error {
+		seenPosition = b1.Position
+		seenVelocity = b1.Velocity
+		return nil
+	})
+	w.Step(1)
+
+	if seenVelocity == (Vector{X: 1}) {
+		t.Error("expected the listener to see the velocity after the collision")
+	}
+	if seenPosition != b1.Position || seenVelocity != b1.Velocity {
+		t.Error("expected the listener to see the resolved body", seenPosition, b1.Position)
+	}
+}
+
+// Errors are still logged when only a
+// catch all listener is on the world
+func TestWorldErrorCatchAll(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	w := NewWorld()
+	w.Event.AddCatchAllListener(func(e event.Event[PhysicsWorldEvent]) error {
+		return nil
+	})
+	w.Event.AddListener(StepEndEvent, func(e event.Event[PhysicsWorldEvent]) error {
+		return errors.New("step failed")
+	})
+	w.Step(16)
+	if !strings.Contains(logged.String(), "step failed") {
+		t.Error("expected the error logged", logged.String())
+	}
+}
@@ -0,0 +1,139 @@
+package steering
+
+import (
+	"math"
+
+	"github.com/ashleycheung/go-game/physics"
+)
+
+// The fewest seconds to a collision used when
+// scoring. Stops overlaps scoring infinitely
+const minCollisionTime = 1e-3
+
+// Picks velocities that avoid nearby bodies using
+// reciprocal velocity obstacles. Velocities are sampled
+// around the preferred velocity and the one with the
+// lowest penalty is picked. The penalty is how far the
+// velocity is from the preferred one plus how soon it
+// collides. Each agent assumes the others take half
+// the responsibility of avoiding it
+type Avoidance struct {
+	// How far to look for bodies to avoid
+	NeighbourRadius float64
+
+	// How many milliseconds ahead collisions are avoided
+	TimeHorizon float64
+
+	// The number of directions sampled
+	Samples int
+
+	// How strongly collisions are penalised compared to
+	// moving away from the preferred velocity
+	CollisionWeight float64
+}
+
+// Creates avoidance of bodies within the radius which
+// collide within the time horizon in milliseconds
+func NewAvoidance(neighbourRadius, timeHorizon float64) *Avoidance {
+	return &Avoidance{
+		NeighbourRadius: neighbourRadius,
+		TimeHorizon:     timeHorizon,
+		Samples:         16,
+		CollisionWeight: 1,
+	}
+}
+
+// Returns the velocity closest to the preferred
+// velocity which avoids the neighbours of the agent
+func (a *Avoidance) Avoid(ctx *Context, preferred physics.Vector) physics.Vector {
+	agent := ctx.Agent
+	neighbours := ctx.FindNeighbours(a.NeighbourRadius)
+	if len(neighbours) == 0 || a.TimeHorizon <= 0 {
+		return preferred
+	}
+
+	best := preferred
+	bestPenalty := a.penalty(agent, neighbours, preferred, preferred)
+	if bestPenalty == 0 {
+		return preferred
+	}
+	consider := func(candidate physics.Vector) {
+		if p := a.penalty(agent, neighbours, preferred, candidate); p < bestPenalty {
+			best = candidate
+			bestPenalty = p
+		}
+	}
+	consider(physics.NewZeroVector())
+
+	// Sample around the preferred direction
+	// so the results follow the heading
+	heading := 0.0
+	if !preferred.IsZero() {
+		heading = math.Atan2(preferred.Y, preferred.X)
+	}
+	speeds := []float64{agent.MaxSpeed, agent.MaxSpeed / 2}
+	if speed := preferred.Magnitude(); speed > 0 && speed < agent.MaxSpeed {
+		speeds = append(speeds, speed)
+	}
+	for i := 0; i < a.Samples; i++ {
+		angle := heading + 2*math.Pi*float64(i)/float64(a.Samples)
+		for _, speed := range speeds {
+			consider(physics.NewVector(angle, speed))
+		}
+	}
+	return best
+}
+
+// Scores the candidate velocity. Lower is better
+func (a *Avoidance) penalty(
+	agent *Agent,
+	neighbours []*physics.Body,
+	preferred physics.Vector,
+	candidate physics.Vector,
+) float64 {
+	horizon := a.TimeHorizon / 1000
+	soonest := math.Inf(1)
+	for _, n := range neighbours {
+		// Reciprocal velocity relative to the neighbour
+		relVelocity := candidate.Scale(2).
+			Subtract(agent.Body.Velocity).
+			Subtract(n.Velocity)
+		t := timeToCollision(
+			n.Position.Subtract(agent.Body.Position),
+			relVelocity,
+			agent.Radius+ShapeRadius(n.Shape),
+		)
+		soonest = math.Min(soonest, t)
+	}
+	penalty := candidate.DistanceTo(preferred)
+	if soonest < horizon {
+		soonest = math.Max(soonest, minCollisionTime)
+		penalty += a.CollisionWeight * agent.MaxSpeed * (horizon/soonest - 1)
+	}
+	return penalty
+}
+
+// Returns the seconds until a point moving at the velocity
+// comes within the radius of the offset. Returns 0 if it
+// is already within and getting closer, and infinity if
+// they never collide
+func timeToCollision(offset, velocity physics.Vector, radius float64) float64 {
+	a := velocity.Dot(velocity)
+	b := -2 * velocity.Dot(offset)
+	c := offset.Dot(offset) - radius*radius
+	if c < 0 {
+		if b < 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	disc := b*b - 4*a*c
+	if a == 0 || disc < 0 {
+		return math.Inf(1)
+	}
+	t := (-b - math.Sqrt(disc)) / (2 * a)
+	if t < 0 {
+		return math.Inf(1)
+	}
+	return t
+}
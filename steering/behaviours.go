@@ -0,0 +1,269 @@
+package steering
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/ashleycheung/go-game/physics"
+)
+
+// Moves towards the target at full speed
+type Seek struct {
+	Target physics.Vector
+}
+
+// Creates a seek towards the target
+func NewSeek(target physics.Vector) *Seek {
+	return &Seek{Target: target}
+}
+
+// Returns the force towards the target
+func (s *Seek) Steer(ctx *Context) physics.Vector {
+	agent := ctx.Agent
+	return steerTowards(ctx, fullSpeed(agent, s.Target.Subtract(agent.Body.Position)))
+}
+
+// Returns a seek towards the same target
+func (s *Seek) Clone() Behaviour {
+	return NewSeek(s.Target)
+}
+
+// Moves away from the target at full speed
+type Flee struct {
+	Target physics.Vector
+	// Only flees when closer than this. 0 always flees
+	PanicDistance float64
+}
+
+// Creates a flee from the target
+func NewFlee(target physics.Vector, panicDistance float64) *Flee {
+	return &Flee{Target: target, PanicDistance: panicDistance}
+}
+
+// Returns the force away from the target
+func (f *Flee) Steer(ctx *Context) physics.Vector {
+	agent := ctx.Agent
+	away := agent.Body.Position.Subtract(f.Target)
+	if f.PanicDistance > 0 && away.Magnitude() > f.PanicDistance {
+		return physics.NewZeroVector()
+	}
+	return steerTowards(ctx, fullSpeed(agent, away))
+}
+
+// Returns a flee from the same target
+func (f *Flee) Clone() Behaviour {
+	return NewFlee(f.Target, f.PanicDistance)
+}
+
+// Moves towards the target, slowing
+// down to stop on top of it
+type Arrive struct {
+	Target physics.Vector
+	// The distance from the target to start slowing down
+	SlowRadius float64
+}
+
+// Creates an arrive at the target
+func NewArrive(target physics.Vector, slowRadius float64) *Arrive {
+	return &Arrive{Target: target, SlowRadius: slowRadius}
+}
+
+// Returns the force towards the target, slowing near it
+func (a *Arrive) Steer(ctx *Context) physics.Vector {
+	agent := ctx.Agent
+	offset := a.Target.Subtract(agent.Body.Position)
+	dist := offset.Magnitude()
+	if dist == 0 {
+		return steerTowards(ctx, physics.NewZeroVector())
+	}
+	speed := agent.MaxSpeed
+	if dist < a.SlowRadius {
+		speed *= dist / a.SlowRadius
+	}
+	// Don't overshoot within the step
+	if ctx.Delta > 0 {
+		speed = math.Min(speed, dist/(ctx.Delta/1000))
+	}
+	return steerTowards(ctx, offset.Scale(speed/dist))
+}
+
+// Returns an arrive at the same target
+func (a *Arrive) Clone() Behaviour {
+	return NewArrive(a.Target, a.SlowRadius)
+}
+
+// Seeks where the target body is going to be
+type Pursue struct {
+	Target *physics.Body
+}
+
+// Creates a pursue of the target body
+func NewPursue(target *physics.Body) *Pursue {
+	return &Pursue{Target: target}
+}
+
+// Returns the force towards the predicted position
+func (p *Pursue) Steer(ctx *Context) physics.Vector {
+	predicted := predictPosition(ctx.Agent, p.Target)
+	return (&Seek{Target: predicted}).Steer(ctx)
+}
+
+// Returns a pursue of the same body
+func (p *Pursue) Clone() Behaviour {
+	return NewPursue(p.Target)
+}
+
+// Flees from where the target body is going to be
+type Evade struct {
+	Target *physics.Body
+	// Only evades when closer than this. 0 always evades
+	PanicDistance float64
+}
+
+// Creates an evade of the target body
+func NewEvade(target *physics.Body, panicDistance float64) *Evade {
+	return &Evade{Target: target, PanicDistance: panicDistance}
+}
+
+// Returns the force away from the predicted position
+func (e *Evade) Steer(ctx *Context) physics.Vector {
+	if e.PanicDistance > 0 &&
+		ctx.Agent.Body.Position.DistanceTo(e.Target.Position) > e.PanicDistance {
+		return physics.NewZeroVector()
+	}
+	predicted := predictPosition(ctx.Agent, e.Target)
+	return (&Flee{Target: predicted}).Steer(ctx)
+}
+
+// Returns an evade of the same body
+func (e *Evade) Clone() Behaviour {
+	return NewEvade(e.Target, e.PanicDistance)
+}
+
+// Returns where the target will be by
+// the time the agent could reach it
+func predictPosition(agent *Agent, target *physics.Body) physics.Vector {
+	t := timeToReach(agent, target.Position)
+	return target.Position.Add(target.Velocity.Scale(t))
+}
+
+// Moves around randomly by seeking a point on a
+// circle in front of the agent which drifts each step
+type Wander struct {
+	// How far in front of the agent the circle is
+	Distance float64
+	// The radius of the circle
+	Radius float64
+	// The max radians the point drifts per second
+	Jitter float64
+	// The random source. Nil uses the global source
+	Rand *rand.Rand
+	// The angle of the point on the circle
+	// relative to the heading
+	angle float64
+}
+
+// Creates a wander using the global random source
+func NewWander(distance, radius, jitter float64) *Wander {
+	return &Wander{Distance: distance, Radius: radius, Jitter: jitter}
+}
+
+// Returns a random number from -1 to 1
+func (w *Wander) random() float64 {
+	if w.Rand != nil {
+		return w.Rand.Float64()*2 - 1
+	}
+	return rand.Float64()*2 - 1
+}
+
+// Drifts the point and returns the force towards it
+func (w *Wander) Steer(ctx *Context) physics.Vector {
+	agent := ctx.Agent
+	w.angle += w.random() * w.Jitter * ctx.Delta / 1000
+	heading := physics.Vector{X: 1}
+	if !agent.Body.Velocity.IsZero() {
+		heading = agent.Body.Velocity.Normalize()
+	}
+	target := agent.Body.Position.
+		Add(heading.Scale(w.Distance)).
+		Add(physics.NewVector(w.angle+math.Atan2(heading.Y, heading.X), w.Radius))
+	return (&Seek{Target: target}).Steer(ctx)
+}
+
+// The copy uses the same random source
+func (w *Wander) Clone() Behaviour {
+	clone := NewWander(w.Distance, w.Radius, w.Jitter)
+	clone.Rand = w.Rand
+	return clone
+}
+
+// Moves along a path, arriving at the last point
+type PathFollow struct {
+	Path []physics.Vector
+	// How close the agent needs to be
+	// to a point to move on to the next
+	ArriveDistance float64
+	// The distance from the end to start slowing down
+	SlowRadius float64
+	// Whether to go back to the start at the end
+	Loop bool
+	// The point being moved to
+	index int
+}
+
+// Creates a path follow slowing down
+// within 4 arrive distances of the end
+func NewPathFollow(path []physics.Vector, arriveDistance float64) *PathFollow {
+	return &PathFollow{
+		Path:           path,
+		ArriveDistance: arriveDistance,
+		SlowRadius:     arriveDistance * 4,
+	}
+}
+
+// Sets a new path starting from the first point
+func (p *PathFollow) SetPath(path []physics.Vector) {
+	p.Path = path
+	p.index = 0
+}
+
+// Returns the index of the point being moved to
+func (p *PathFollow) GetIndex() int {
+	return p.index
+}
+
+// Returns whether the end of the path was reached
+func (p *PathFollow) IsDone() bool {
+	return !p.Loop && p.index >= len(p.Path)
+}
+
+// Returns the force towards the point being moved to
+func (p *PathFollow) Steer(ctx *Context) physics.Vector {
+	if len(p.Path) == 0 {
+		return physics.NewZeroVector()
+	}
+	pos := ctx.Agent.Body.Position
+	for p.index < len(p.Path) && pos.DistanceTo(p.Path[p.index]) <= p.ArriveDistance {
+		p.index++
+		if p.Loop && p.index == len(p.Path) {
+			p.index = 0
+			break
+		}
+	}
+	if p.index >= len(p.Path) {
+		return (&Arrive{Target: p.Path[len(p.Path)-1], SlowRadius: p.SlowRadius}).Steer(ctx)
+	}
+	if !p.Loop && p.index == len(p.Path)-1 {
+		return (&Arrive{Target: p.Path[p.index], SlowRadius: p.SlowRadius}).Steer(ctx)
+	}
+	return (&Seek{Target: p.Path[p.index]}).Steer(ctx)
+}
+
+// Returns a path follow of a copy of the
+// path starting from the first point
+func (p *PathFollow) Clone() Behaviour {
+	clone := NewPathFollow(append([]physics.Vector{}, p.Path...), p.ArriveDistance)
+	clone.SlowRadius = p.SlowRadius
+	clone.Loop = p.Loop
+	return clone
+}
@@ -0,0 +1,93 @@
+package steering
+
+import "github.com/ashleycheung/go-game/physics"
+
+// Moves away from nearby bodies,
+// more strongly from closer ones
+type Separation struct {
+	Radius float64
+}
+
+// Creates a separation from bodies within the radius
+func NewSeparation(radius float64) *Separation {
+	return &Separation{Radius: radius}
+}
+
+// Returns the force away from the neighbours
+func (s *Separation) Steer(ctx *Context) physics.Vector {
+	agent := ctx.Agent
+	away := physics.NewZeroVector()
+	for _, n := range ctx.FindNeighbours(s.Radius) {
+		offset := agent.Body.Position.Subtract(n.Position)
+		dist := offset.Magnitude()
+		if dist == 0 {
+			continue
+		}
+		away = away.Add(offset.Scale(1 / (dist * dist)))
+	}
+	if away.IsZero() {
+		return physics.NewZeroVector()
+	}
+	return steerTowards(ctx, fullSpeed(agent, away))
+}
+
+// Returns a separation with the same radius
+func (s *Separation) Clone() Behaviour {
+	return NewSeparation(s.Radius)
+}
+
+// Matches the average heading of nearby bodies
+type Alignment struct {
+	Radius float64
+}
+
+// Creates an alignment with bodies within the radius
+func NewAlignment(radius float64) *Alignment {
+	return &Alignment{Radius: radius}
+}
+
+// Returns the force towards the average heading
+func (a *Alignment) Steer(ctx *Context) physics.Vector {
+	heading := physics.NewZeroVector()
+	for _, n := range ctx.FindNeighbours(a.Radius) {
+		heading = heading.Add(n.Velocity)
+	}
+	if heading.IsZero() {
+		return physics.NewZeroVector()
+	}
+	return steerTowards(ctx, fullSpeed(ctx.Agent, heading))
+}
+
+// Returns an alignment with the same radius
+func (a *Alignment) Clone() Behaviour {
+	return NewAlignment(a.Radius)
+}
+
+// Moves towards the centre of nearby bodies
+type Cohesion struct {
+	Radius float64
+}
+
+// Creates a cohesion with bodies within the radius
+func NewCohesion(radius float64) *Cohesion {
+	return &Cohesion{Radius: radius}
+}
+
+// Returns the force towards the centre of the neighbours
+func (c *Cohesion) Steer(ctx *Context) physics.Vector {
+	neighbours := ctx.FindNeighbours(c.Radius)
+	if len(neighbours) == 0 {
+		return physics.NewZeroVector()
+	}
+	centre := physics.NewZeroVector()
+	for _, n := range neighbours {
+		centre = centre.Add(n.Position)
+	}
+	centre = centre.Scale(1 / float64(len(neighbours)))
+	return (&Seek{Target: centre}).Steer(ctx)
+}
+
+// Returns a cohesion with the same radius
+func (c *Cohesion) Clone() Behaviour {
+	return NewCohesion(c.Radius)
+}
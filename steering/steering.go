@@ -0,0 +1,187 @@
+package steering
+
+import (
+	"math"
+
+	"github.com/ashleycheung/go-game/physics"
+)
+
+// A body moved by steering behaviours
+type Agent struct {
+	Body *physics.Body
+
+	// The max speed in units per second
+	MaxSpeed float64
+
+	// The max change in velocity in units per second squared
+	MaxForce float64
+
+	// The radius kept clear of other bodies
+	Radius float64
+}
+
+// Creates an agent for the body. The radius
+// is the radius of the body's shape
+func NewAgent(body *physics.Body, maxSpeed, maxForce float64) *Agent {
+	return &Agent{
+		Body:     body,
+		MaxSpeed: maxSpeed,
+		MaxForce: maxForce,
+		Radius:   ShapeRadius(body.Shape),
+	}
+}
+
+// Returns the radius of a circle that covers the shape
+func ShapeRadius(shape physics.Shape) float64 {
+	switch s := shape.(type) {
+	case physics.Circle:
+		return s.Radius
+	case physics.Rectangle:
+		return s.Size.Magnitude() / 2
+	}
+	return 0
+}
+
+// Returns the velocity after applying the steering force
+// for the delta in milliseconds, limited to the max speed
+func (a *Agent) ApplyForce(force physics.Vector, delta float64) physics.Vector {
+	velocity := a.Body.Velocity.Add(Truncate(force, a.MaxForce).Scale(delta / 1000))
+	return Truncate(velocity, a.MaxSpeed)
+}
+
+// Finds bodies near other bodies
+type NeighbourFinder interface {
+	// Returns the bodies other than the body within
+	// the radius of its position
+	FindNeighbours(body *physics.Body, radius float64) []*physics.Body
+}
+
+// Allows a function to be used as a neighbour finder
+type NeighbourFinderFunc func(body *physics.Body, radius float64) []*physics.Body
+
+// Calls the function
+func (f NeighbourFinderFunc) FindNeighbours(body *physics.Body, radius float64) []*physics.Body {
+	return f(body, radius)
+}
+
+// Finds neighbours using the quad tree of the physics world.
+// Static and sensor bodies are ignored. The tree is rebuilt
+// each physics step so bodies added since are not found
+func NewWorldNeighbours(world *physics.World) NeighbourFinder {
+	return NeighbourFinderFunc(func(body *physics.Body, radius float64) []*physics.Body {
+		area := physics.BBox{
+			TopLeft:     body.Position.Subtract(physics.Vector{X: radius, Y: radius}),
+			BottomRight: body.Position.Add(physics.Vector{X: radius, Y: radius}),
+		}
+		neighbours := []*physics.Body{}
+		for _, b := range world.QuadTree.QueryRegion(area) {
+			if b == body || b.Static || b.Sensor {
+				continue
+			}
+			if b.Position.DistanceSquaredTo(body.Position) <= radius*radius {
+				neighbours = append(neighbours, b)
+			}
+		}
+		return neighbours
+	})
+}
+
+// Passed to behaviours when steering
+type Context struct {
+	// The agent being steered
+	Agent *Agent
+
+	// The time to steer for in milliseconds
+	Delta float64
+
+	// Finds nearby bodies. Nil if there is none
+	Neighbours NeighbourFinder
+}
+
+// Returns the bodies near the agent.
+// Empty if there is no neighbour finder
+func (c *Context) FindNeighbours(radius float64) []*physics.Body {
+	if c.Neighbours == nil {
+		return []*physics.Body{}
+	}
+	return c.Neighbours.FindNeighbours(c.Agent.Body, radius)
+}
+
+// Returns a steering force which changes
+// the velocity of an agent
+type Behaviour interface {
+	// Returns the force in units per second squared
+	Steer(ctx *Context) physics.Vector
+
+	// Makes a copy with no running state
+	Clone() Behaviour
+}
+
+// Allows a function to be used as a behaviour
+type BehaviourFunc func(ctx *Context) physics.Vector
+
+// Calls the function
+func (f BehaviourFunc) Steer(ctx *Context) physics.Vector {
+	return f(ctx)
+}
+
+// Returns the same function as it keeps no state
+func (f BehaviourFunc) Clone() Behaviour {
+	return f
+}
+
+// A behaviour and how much it
+// contributes to the total force
+type WeightedBehaviour struct {
+	Behaviour Behaviour
+	Weight    float64
+}
+
+// Returns the weighted sum of the forces,
+// limited to the max force of the agent
+func Blend(ctx *Context, behaviours []WeightedBehaviour) physics.Vector {
+	force := physics.NewZeroVector()
+	for _, b := range behaviours {
+		force = force.Add(b.Behaviour.Steer(ctx).Scale(b.Weight))
+	}
+	return Truncate(force, ctx.Agent.MaxForce)
+}
+
+// Shortens the vector to the max length
+func Truncate(v physics.Vector, max float64) physics.Vector {
+	if v.MagnitudeSqred() > max*max {
+		return v.Normalize().Scale(max)
+	}
+	return v
+}
+
+// Returns the force that turns the velocity of the
+// agent into the desired velocity within the step
+func steerTowards(ctx *Context, desired physics.Vector) physics.Vector {
+	change := desired.Subtract(ctx.Agent.Body.Velocity)
+	if ctx.Delta <= 0 {
+		return change
+	}
+	return change.Scale(1000 / ctx.Delta)
+}
+
+// Returns the desired velocity at max speed
+// in the direction. Zero if there is no direction
+func fullSpeed(agent *Agent, direction physics.Vector) physics.Vector {
+	if direction.IsZero() {
+		return physics.NewZeroVector()
+	}
+	return direction.Normalize().Scale(agent.MaxSpeed)
+}
+
+// Returns the seconds it takes the agent to reach
+// the point at max speed. Used to predict movement
+func timeToReach(agent *Agent, point physics.Vector) float64 {
+	if agent.MaxSpeed <= 0 {
+		return 0
+	}
+	return math.Min(agent.Body.Position.DistanceTo(point)/agent.MaxSpeed, maxPredictionTime)
+}
+
+// The max seconds to predict the movement of targets
+const maxPredictionTime = 2
@@ -0,0 +1,258 @@
+package steering
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/ashleycheung/go-game/physics"
+)
+
+// Creates an agent at the position
+func newTestAgent(position physics.Vector) *Agent {
+	body := physics.NewBody(physics.Circle{Radius: 5})
+	body.Position = position
+	return NewAgent(body, 100, 400)
+}
+
+// Finds neighbours by checking every agent
+func bruteForceNeighbours(agents []*Agent) NeighbourFinder {
+	return NeighbourFinderFunc(func(body *physics.Body, radius float64) []*physics.Body {
+		found := []*physics.Body{}
+		for _, a := range agents {
+			if a.Body != body && a.Body.Position.DistanceTo(body.Position) <= radius {
+				found = append(found, a.Body)
+			}
+		}
+		return found
+	})
+}
+
+// Steps the agents with the behaviours for the number of steps
+func simulate(
+	agents []*Agent,
+	behaviours func(a *Agent) []WeightedBehaviour,
+	avoidance *Avoidance,
+	steps int,
+	onStep func(),
+) {
+	neighbours := bruteForceNeighbours(agents)
+	delta := 16.0
+	for i := 0; i < steps; i++ {
+		velocities := make([]physics.Vector, len(agents))
+		for j, a := range agents {
+			ctx := &Context{Agent: a, Delta: delta, Neighbours: neighbours}
+			velocities[j] = a.ApplyForce(Blend(ctx, behaviours(a)), delta)
+			if avoidance != nil {
+				velocities[j] = avoidance.Avoid(ctx, velocities[j])
+			}
+		}
+		for j, a := range agents {
+			a.Body.Velocity = velocities[j]
+			a.Body.Step(delta)
+		}
+		if onStep != nil {
+			onStep()
+		}
+	}
+}
+
+// Returns the behaviour with a weight of 1
+func only(b Behaviour) func(a *Agent) []WeightedBehaviour {
+	return func(a *Agent) []WeightedBehaviour {
+		return []WeightedBehaviour{{Behaviour: b, Weight: 1}}
+	}
+}
+
+func TestSeekAndFlee(t *testing.T) {
+	agent := newTestAgent(physics.Vector{})
+	target := physics.Vector{X: 100, Y: 100}
+	start := agent.Body.Position.DistanceTo(target)
+	simulate([]*Agent{agent}, only(NewSeek(target)), nil, 30, nil)
+	if agent.Body.Position.DistanceTo(target) >= start {
+		t.Error("expected seek to move closer", agent.Body.Position)
+	}
+	if speed := agent.Body.Velocity.Magnitude(); speed > agent.MaxSpeed+1e-9 {
+		t.Error("expected speed limited to the max speed", speed)
+	}
+
+	agent = newTestAgent(physics.Vector{})
+	simulate([]*Agent{agent}, only(NewFlee(target, 0)), nil, 30, nil)
+	if agent.Body.Position.DistanceTo(target) <= start {
+		t.Error("expected flee to move away", agent.Body.Position)
+	}
+
+	// Too far away to panic
+	agent = newTestAgent(physics.Vector{})
+	simulate([]*Agent{agent}, only(NewFlee(target, 50)), nil, 30, nil)
+	if !agent.Body.Position.IsZero() {
+		t.Error("expected flee to ignore far targets", agent.Body.Position)
+	}
+}
+
+func TestArrive(t *testing.T) {
+	agent := newTestAgent(physics.Vector{})
+	target := physics.Vector{X: 100}
+	simulate([]*Agent{agent}, only(NewArrive(target, 40)), nil, 300, nil)
+	if dist := agent.Body.Position.DistanceTo(target); dist > 0.5 {
+		t.Error("expected to stop at the target", agent.Body.Position)
+	}
+	if speed := agent.Body.Velocity.Magnitude(); speed > 1 {
+		t.Error("expected to slow down", speed)
+	}
+}
+
+func TestPursueAndEvade(t *testing.T) {
+	// The target crosses in front of the agents
+	newTarget := func() *physics.Body {
+		target := physics.NewBody(physics.Circle{Radius: 5})
+		target.Position = physics.Vector{X: 100, Y: -100}
+		target.Velocity = physics.Vector{Y: 50}
+		return target
+	}
+	closest := func(b Behaviour, target *physics.Body) float64 {
+		agent := newTestAgent(physics.Vector{})
+		minDist := math.Inf(1)
+		simulate([]*Agent{agent}, only(b), nil, 150, func() {
+			target.Step(16)
+			minDist = math.Min(minDist, agent.Body.Position.DistanceTo(target.Position))
+		})
+		return minDist
+	}
+
+	target := newTarget()
+	if dist := closest(NewPursue(target), target); dist > 5 {
+		t.Error("expected pursue to catch the target", dist)
+	}
+	pursueSeek := newTarget()
+	if closest(NewPursue(pursueSeek), pursueSeek) > closest(NewSeek(physics.Vector{X: 100, Y: -100}), newTarget()) {
+		t.Error("expected pursue to get closer than seeking the start")
+	}
+	target = newTarget()
+	if dist := closest(NewEvade(target, 0), target); dist < 100 {
+		t.Error("expected evade to keep away", dist)
+	}
+}
+
+func TestWander(t *testing.T) {
+	agent := newTestAgent(physics.Vector{})
+	wander := NewWander(20, 10, math.Pi)
+	wander.Rand = rand.New(rand.NewSource(1))
+	simulate([]*Agent{agent}, only(wander), nil, 100, nil)
+	if agent.Body.Position.IsZero() {
+		t.Error("expected wander to move")
+	}
+	if speed := agent.Body.Velocity.Magnitude(); speed > agent.MaxSpeed+1e-9 {
+		t.Error("expected speed limited to the max speed", speed)
+	}
+}
+
+func TestPathFollow(t *testing.T) {
+	agent := newTestAgent(physics.Vector{})
+	path := []physics.Vector{{X: 50}, {X: 50, Y: 50}, {X: 100, Y: 50}}
+	follow := NewPathFollow(path, 5)
+	visited := map[int]bool{}
+	simulate([]*Agent{agent}, only(follow), nil, 300, func() {
+		visited[follow.GetIndex()] = true
+	})
+	if !visited[1] || !visited[2] {
+		t.Error("expected each point to be followed", visited)
+	}
+	if dist := agent.Body.Position.DistanceTo(path[2]); dist > 5 {
+		t.Error("expected to arrive at the end", agent.Body.Position)
+	}
+
+	follow.Loop = true
+	follow.SetPath(path)
+	simulate([]*Agent{agent}, only(follow), nil, 300, nil)
+	if follow.IsDone() {
+		t.Error("a looping path never finishes")
+	}
+}
+
+func TestFlocking(t *testing.T) {
+	agents := []*Agent{}
+	for i := 0; i < 10; i++ {
+		a := newTestAgent(physics.Vector{X: float64(i%5) * 3, Y: float64(i/5) * 3})
+		a.Body.Velocity = physics.NewVector(float64(i), 50)
+		agents = append(agents, a)
+	}
+	behaviours := func(a *Agent) []WeightedBehaviour {
+		return []WeightedBehaviour{
+			{Behaviour: NewSeparation(25), Weight: 2},
+			{Behaviour: NewAlignment(50), Weight: 1},
+			{Behaviour: NewCohesion(50), Weight: 1},
+		}
+	}
+	simulate(agents, behaviours, nil, 200, nil)
+
+	// Separated but still together
+	centre := physics.NewZeroVector()
+	for _, a := range agents {
+		centre = centre.Add(a.Body.Position.Scale(1.0 / float64(len(agents))))
+	}
+	for i, a := range agents {
+		if a.Body.Position.DistanceTo(centre) > 60 {
+			t.Error("expected the flock to stay together", a.Body.Position, centre)
+		}
+		for _, b := range agents[i+1:] {
+			if a.Body.Position.DistanceTo(b.Body.Position) < 5 {
+				t.Error("expected the flock to separate", a.Body.Position, b.Body.Position)
+			}
+		}
+	}
+
+	// Aligned headings
+	heading := agents[0].Body.Velocity.Normalize()
+	for _, a := range agents {
+		if a.Body.Velocity.IsZero() || a.Body.Velocity.Normalize().Dot(heading) < 0.9 {
+			t.Error("expected the flock to move the same way", a.Body.Velocity)
+		}
+	}
+}
+
+func TestTimeToCollision(t *testing.T) {
+	// Moving straight at a body 20 away with a radius of 10
+	if got := timeToCollision(physics.Vector{X: 20}, physics.Vector{X: 10}, 10); math.Abs(got-1) > 1e-9 {
+		t.Error("expected a collision in 1 second", got)
+	}
+	if got := timeToCollision(physics.Vector{X: 20}, physics.Vector{X: -10}, 10); !math.IsInf(got, 1) {
+		t.Error("expected no collision moving away", got)
+	}
+	if got := timeToCollision(physics.Vector{X: 20}, physics.Vector{Y: 10}, 10); !math.IsInf(got, 1) {
+		t.Error("expected no collision moving past", got)
+	}
+	if got := timeToCollision(physics.Vector{X: 5}, physics.Vector{X: 10}, 10); got != 0 {
+		t.Error("expected an overlap moving closer", got)
+	}
+}
+
+// Agents swap places head on and should never overlap
+func TestAvoidance(t *testing.T) {
+	crossing := func(avoidance *Avoidance) float64 {
+		a := newTestAgent(physics.Vector{X: 0})
+		b := newTestAgent(physics.Vector{X: 200})
+		agents := []*Agent{a, b}
+		targets := map[*Agent]physics.Vector{a: b.Body.Position, b: a.Body.Position}
+		behaviours := func(agent *Agent) []WeightedBehaviour {
+			return []WeightedBehaviour{{Behaviour: NewArrive(targets[agent], 20), Weight: 1}}
+		}
+		minDist := math.Inf(1)
+		simulate(agents, behaviours, avoidance, 400, func() {
+			minDist = math.Min(minDist, a.Body.Position.DistanceTo(b.Body.Position))
+		})
+		for agent, target := range targets {
+			if agent.Body.Position.DistanceTo(target) > 2 {
+				t.Error("expected agents to reach their targets", agent.Body.Position, target)
+			}
+		}
+		return minDist
+	}
+
+	if dist := crossing(nil); dist >= 10 {
+		t.Fatal("expected agents to collide without avoidance", dist)
+	}
+	if dist := crossing(NewAvoidance(60, 1000)); dist < 10 {
+		t.Error("expected agents to avoid each other", dist)
+	}
+}
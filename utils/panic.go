@@ -0,0 +1,23 @@
+package utils
+
+import "fmt"
+
+// A panic recovered on one goroutine and raised again
+// on another. Keeps the value passed to panic and the
+// stack of the goroutine which panicked
+type PanicError struct {
+	// The value passed to panic
+	Value any
+	// The stack when it panicked
+	Stack []byte
+}
+
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("panicked: %v\n\n%s", p.Value, p.Stack)
+}
+
+// Returns the value if it is an error
+func (p *PanicError) Unwrap() error {
+	err, _ := p.Value.(error)
+	return err
+}
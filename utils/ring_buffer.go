@@ -0,0 +1,104 @@
+package utils
+
+// A fixed size queue which overwrites the
+// oldest item once full. Pushing never allocates
+type RingBuffer[T any] struct {
+	items []T
+	// The index of the oldest item
+	start int
+	count int
+}
+
+// Creates a ring buffer holding up to the capacity
+// of items. A capacity below 1 holds one item
+func NewRingBuffer[T any](capacity int) *RingBuffer[T] {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &RingBuffer[T]{items: make([]T, capacity)}
+}
+
+// Adds the item to the end. If full, the oldest item is
+// removed and returned with true
+func (r *RingBuffer[T]) Push(item T) (removed T, full bool) {
+	if r.count < len(r.items) {
+		r.items[(r.start+r.count)%len(r.items)] = item
+		r.count++
+		return removed, false
+	}
+	removed = r.items[r.start]
+	r.items[r.start] = item
+	r.start = (r.start + 1) % len(r.items)
+	return removed, true
+}
+
+// Removes and returns the oldest item.
+// Returns false if the buffer is empty
+func (r *RingBuffer[T]) Pop() (item T, ok bool) {
+	if r.count == 0 {
+		return item, false
+	}
+	var zero T
+	item = r.items[r.start]
+	r.items[r.start] = zero
+	r.start = (r.start + 1) % len(r.items)
+	r.count--
+	return item, true
+}
+
+// Returns the item at the index where 0 is the
+// oldest. Panics if the index is out of range
+func (r *RingBuffer[T]) Get(index int) T {
+	if index < 0 || index >= r.count {
+		panic("ring buffer index out of range")
+	}
+	return r.items[(r.start+index)%len(r.items)]
+}
+
+// Returns the number of items
+func (r *RingBuffer[T]) Len() int {
+	return r.count
+}
+
+// Returns the max number of items
+func (r *RingBuffer[T]) Cap() int {
+	return len(r.items)
+}
+
+// Returns a copy of the items from oldest to latest
+func (r *RingBuffer[T]) Items() []T {
+	out := make([]T, r.count)
+	for i := range out {
+		out[i] = r.Get(i)
+	}
+	return out
+}
+
+// Removes every item
+func (r *RingBuffer[T]) Clear() {
+	var zero T
+	for i := range r.items {
+		r.items[i] = zero
+	}
+	r.start = 0
+	r.count = 0
+}
+
+// Changes the max number of items. If there are more
+// items than the capacity, the oldest are removed
+func (r *RingBuffer[T]) SetCapacity(capacity int) {
+	if capacity < 1 {
+		capacity = 1
+	}
+	if capacity == len(r.items) {
+		return
+	}
+	items := r.Items()
+	if len(items) > capacity {
+		items = items[len(items)-capacity:]
+	}
+	r.items = make([]T, capacity)
+	copy(r.items, items)
+	r.start = 0
+	r.count = len(items)
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRingBuffer(t *testing.T) {
+	r := NewRingBuffer[int](3)
+	for i := 1; i <= 3; i++ {
+		if _, full := r.Push(i); full {
+			t.Error("buffer shouldn't be full", i)
+		}
+	}
+	removed, full := r.Push(4)
+	if !full || removed != 1 {
+		t.Error("expected the oldest item removed", removed)
+	}
+	if fmt.Sprint(r.Items()) != "[2 3 4]" || r.Get(0) != 2 {
+		t.Error("expected the items oldest first", r.Items())
+	}
+	if item, ok := r.Pop(); !ok || item != 2 {
+		t.Error("expected to pop the oldest", item)
+	}
+	r.Push(5)
+	r.Push(6)
+	if fmt.Sprint(r.Items()) != "[4 5 6]" || r.Len() != 3 {
+		t.Error("expected the items to wrap", r.Items())
+	}
+	r.Clear()
+	if _, ok := r.Pop(); ok || r.Len() != 0 {
+		t.Error("expected the buffer cleared")
+	}
+}
+
+func TestRingBufferSetCapacity(t *testing.T) {
+	r := NewRingBuffer[int](4)
+	for i := 1; i <= 6; i++ {
+		r.Push(i)
+	}
+	r.SetCapacity(2)
+	if fmt.Sprint(r.Items()) != "[5 6]" || r.Cap() != 2 {
+		t.Error("expected the latest items kept", r.Items())
+	}
+	r.SetCapacity(3)
+	r.Push(7)
+	if fmt.Sprint(r.Items()) != "[5 6 7]" {
+		t.Error("expected room for another item", r.Items())
+	}
+}
@@ -0,0 +1,98 @@
+package utils
+
+// A unit of work which declares the resources
+// it reads and writes so it can be run alongside
+// jobs it doesn't conflict with
+type Job[R comparable] struct {
+	// Name of the job
+	Name string
+
+	// Resources the job only reads
+	Reads []R
+
+	// Resources the job writes
+	Writes []R
+
+	// Does the work
+	Run func()
+}
+
+// Returns whether the job declared any resources.
+// Jobs that don't are assumed to touch everything
+func (j Job[R]) declaresAccess() bool {
+	return len(j.Reads) != 0 || len(j.Writes) != 0
+}
+
+// Returns whether two jobs can't run at the same time.
+// Jobs conflict if one writes a resource the other reads
+// or writes, or if either doesn't declare its resources
+func (j Job[R]) ConflictsWith(other Job[R]) bool {
+	if !j.declaresAccess() || !other.declaresAccess() {
+		return true
+	}
+	otherWrites := map[R]bool{}
+	for _, r := range other.Writes {
+		otherWrites[r] = true
+	}
+	for _, r := range j.Writes {
+		if otherWrites[r] {
+			return true
+		}
+	}
+	for _, r := range j.Reads {
+		if otherWrites[r] {
+			return true
+		}
+	}
+	for _, r := range other.Reads {
+		for _, w := range j.Writes {
+			if r == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Groups the jobs into stages. Jobs in the same stage
+// don't conflict and can run concurrently. A job is always
+// placed in a later stage than any job before it that it
+// conflicts with, so the result is the same as running
+// the jobs one by one in order
+func BuildStages[R comparable](jobs []Job[R]) [][]Job[R] {
+	stages := [][]Job[R]{}
+	jobStages := make([]int, len(jobs))
+	for i, job := range jobs {
+		stage := 0
+		for j := 0; j < i; j++ {
+			if jobStages[j] >= stage && job.ConflictsWith(jobs[j]) {
+				stage = jobStages[j] + 1
+			}
+		}
+		jobStages[i] = stage
+		if stage == len(stages) {
+			stages = append(stages, []Job[R]{})
+		}
+		stages[stage] = append(stages[stage], job)
+	}
+	return stages
+}
+
+// Runs the stages in order. Jobs within a stage are run
+// concurrently on the pool. If the pool is nil, every
+// job is run in order on the calling goroutine
+func RunStages[R comparable](stages [][]Job[R], pool *WorkerPool) {
+	for _, stage := range stages {
+		if pool == nil || len(stage) == 1 {
+			for _, job := range stage {
+				job.Run()
+			}
+			continue
+		}
+		tasks := make([]func(), len(stage))
+		for i, job := range stage {
+			tasks[i] = job.Run
+		}
+		pool.Run(tasks...)
+	}
+}
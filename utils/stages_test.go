@@ -0,0 +1,59 @@
+package utils
+
+import "testing"
+
+func TestBuildStages(t *testing.T) {
+	noop := func() {}
+	stages := BuildStages([]Job[string]{
+		{Name: "input", Writes: []string{"input"}, Run: noop},
+		{Name: "ai", Reads: []string{"map"}, Writes: []string{"enemies"}, Run: noop},
+		{Name: "movement", Reads: []string{"input"}, Writes: []string{"player"}, Run: noop},
+		{Name: "camera", Reads: []string{"player"}, Run: noop},
+		{Name: "audio", Reads: []string{"map"}, Run: noop},
+		{Name: "anything", Run: noop},
+	})
+
+	names := [][]string{}
+	for _, stage := range stages {
+		stageNames := []string{}
+		for _, job := range stage {
+			stageNames = append(stageNames, job.Name)
+		}
+		names = append(names, stageNames)
+	}
+
+	expected := [][]string{
+		{"input", "ai", "audio"},
+		{"movement"},
+		{"camera"},
+		{"anything"},
+	}
+	if len(names) != len(expected) {
+		t.Fatal("wrong stages", names)
+	}
+	for i := range expected {
+		if len(names[i]) != len(expected[i]) {
+			t.Fatal("wrong stages", names)
+		}
+		for j := range expected[i] {
+			if names[i][j] != expected[i][j] {
+				t.Fatal("wrong stages", names)
+			}
+		}
+	}
+}
+
+func TestRunStages(t *testing.T) {
+	pool := NewWorkerPool(4)
+	defer pool.Close()
+
+	order := []string{}
+	stages := BuildStages([]Job[string]{
+		{Name: "write", Writes: []string{"a"}, Run: func() { order = append(order, "write") }},
+		{Name: "read", Reads: []string{"a"}, Run: func() { order = append(order, "read") }},
+	})
+	RunStages(stages, pool)
+	if len(order) != 2 || order[0] != "write" || order[1] != "read" {
+		t.Error("conflicting jobs should run in order", order)
+	}
+}
@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+// Creates a pool with the given number of worker
+// goroutines. Workers less than 1 is treated as 1
+func NewWorkerPool(workers int) *WorkerPool {
+	if workers < 1 {
+		workers = 1
+	}
+	p := &WorkerPool{
+		workers: workers,
+		tasks:   make(chan func()),
+	}
+	// The calling goroutine also runs tasks,
+	// so one less worker is started
+	for i := 0; i < workers-1; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// A bounded pool of goroutines used to run tasks
+// concurrently. The goroutine calling Run also runs
+// tasks so calling Run from inside a task never deadlocks
+type WorkerPool struct {
+	// The max number of tasks run at once
+	workers int
+
+	// Tasks waiting for an idle worker
+	tasks chan func()
+
+	closeOnce sync.Once
+}
+
+// Runs tasks until the pool is closed
+func (p *WorkerPool) work() {
+	for task := range p.tasks {
+		task()
+	}
+}
+
+// Returns the max number of tasks run at once
+func (p *WorkerPool) Workers() int {
+	return p.workers
+}
+
+// Runs all the tasks and blocks until they are done.
+// Tasks are given to idle workers, and any left over are
+// run on the calling goroutine. If a task panics, the
+// panic is raised again on the calling goroutine once
+// every task is done as a *PanicError holding the
+// value and the stack of the task
+func (p *WorkerPool) Run(tasks ...func()) {
+	var wg sync.WaitGroup
+	var panicLock sync.Mutex
+	var taskPanic *PanicError
+
+	wrap := func(task func()) func() {
+		return func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					panicLock.Lock()
+					taskPanic = &PanicError{Value: r, Stack: debug.Stack()}
+					panicLock.Unlock()
+				}
+			}()
+			task()
+		}
+	}
+
+	wg.Add(len(tasks))
+	for _, task := range tasks {
+		wrapped := wrap(task)
+		select {
+		case p.tasks <- wrapped:
+		default:
+			// No idle worker so run it here
+			wrapped()
+		}
+	}
+	wg.Wait()
+
+	if taskPanic != nil {
+		panic(taskPanic)
+	}
+}
+
+// Splits the range [0, n) into chunks of at most chunkSize
+// and calls the function on each chunk concurrently.
+// Blocks until every chunk is done
+func (p *WorkerPool) ParallelFor(n int, chunkSize int, fn func(start, end int)) {
+	if chunkSize < 1 {
+		chunkSize = 1
+	}
+	tasks := []func(){}
+	for start := 0; start < n; start += chunkSize {
+		end := start + chunkSize
+		if end > n {
+			end = n
+		}
+		chunkStart := start
+		tasks = append(tasks, func() {
+			fn(chunkStart, end)
+		})
+	}
+	p.Run(tasks...)
+}
+
+// Stops the worker goroutines.
+// The pool must not be used after closing
+func (p *WorkerPool) Close() {
+	p.closeOnce.Do(func() {
+		close(p.tasks)
+	})
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWorkerPoolRun(t *testing.T) {
+	pool := NewWorkerPool(4)
+	defer pool.Close()
+
+	var count int64
+	tasks := []func(){}
+	for i := 0; i < 100; i++ {
+		tasks = append(tasks, func() {
+			atomic.AddInt64(&count, 1)
+		})
+	}
+	pool.Run(tasks...)
+	if count != 100 {
+		t.Error("not all tasks run", count)
+	}
+}
+
+// Running tasks from inside a task should not deadlock
+func TestWorkerPoolNestedRun(t *testing.T) {
+	pool := NewWorkerPool(2)
+	defer pool.Close()
+
+	var count int64
+	outer := []func(){}
+	for i := 0; i < 4; i++ {
+		outer = append(outer, func() {
+			pool.ParallelFor(10, 3, func(start, end int) {
+				atomic.AddInt64(&count, int64(end-start))
+			})
+		})
+	}
+	pool.Run(outer...)
+	if count != 40 {
+		t.Error("not all nested tasks run", count)
+	}
+}
+
+func TestWorkerPoolPanic(t *testing.T) {
+	pool := NewWorkerPool(2)
+	defer pool.Close()
+	taskErr := errors.New("task failed")
+	defer func() {
+		panicErr, ok := recover().(*PanicError)
+		if !ok {
+			t.Fatal("panic should be raised on the caller")
+		}
+		if !errors.Is(panicErr, taskErr) {
+			t.Error("expected the original panic value", panicErr.Value)
+		}
+		if !strings.Contains(string(panicErr.Stack), "TestWorkerPoolPanic") {
+			t.Error("expected the stack of the task")
+		}
+	}()
+	pool.Run(func() {}, func() { panic(taskErr) })
+}